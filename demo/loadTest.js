@@ -23,7 +23,7 @@ export const options = {
   },
 };
 
-const BASE_URL = __ENV.BASE_URL || "http://localhost:8080";
+const BASE_URL = __ENV.BASE_URL || "http://localhost:8080/v1";
 
 export function setup() {
   console.log(`Starting load test against ${BASE_URL}`);
@@ -345,7 +345,7 @@ function testWebSocketConnect() {
   // Connect WebSocket once and keep it open (simulated in k6)
   const chatId = chatIds[0]; // Use first chat
 
-  const wsUrl = `ws://localhost:8080/chats/ws`;
+  const wsUrl = `ws://localhost:8080/v1/chats/ws`;
   const params = {
     headers: {
       Cookie: `token=${authToken}`,
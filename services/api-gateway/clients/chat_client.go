@@ -3,8 +3,12 @@ package clients
 import (
 	"context"
 
+	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/resilience"
+	"github.com/wutthichod/sa-connext/shared/config"
+	"github.com/wutthichod/sa-connext/shared/correlation"
+	"github.com/wutthichod/sa-connext/shared/health"
 	pb "github.com/wutthichod/sa-connext/shared/proto/chat"
-
+	"github.com/wutthichod/sa-connext/shared/tracing"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
@@ -14,9 +18,24 @@ type ChatServiceClient struct {
 	conn   *grpc.ClientConn
 }
 
-func NewChatServiceClient(addr string) (*ChatServiceClient, error) {
+// chatIdempotent lists the methods that are safe to retry
+var chatIdempotent = []string{
+	pb.ChatService_GetChats_FullMethodName,
+	pb.ChatService_GetMessagesByChatId_FullMethodName,
+	pb.ChatService_GetScheduledMessages_FullMethodName,
+	pb.ChatService_GetPoll_FullMethodName,
+}
+
+func NewChatServiceClient(addr string, cfg config.Resilience) (*ChatServiceClient, error) {
 
-	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.NewClient(addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(
+			resilience.UnaryClientInterceptor("chat-service", cfg, chatIdempotent...),
+			correlation.UnaryClientInterceptor(),
+		),
+		tracing.DialOption(),
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -34,6 +53,11 @@ func (c *ChatServiceClient) Close() {
 	}
 }
 
+// Check asks the service behind the connection for its health
+func (c *ChatServiceClient) Check(ctx context.Context) error {
+	return health.GRPC(c.conn)(ctx)
+}
+
 func (c *ChatServiceClient) CreateChat(ctx context.Context, req *pb.CreateChatRequest) (*pb.CreateChatResponse, error) {
 	return c.Client.CreateChat(ctx, req)
 }
@@ -57,3 +81,39 @@ func (c *ChatServiceClient) GetChats(ctx context.Context, req *pb.GetChatsReques
 func (c *ChatServiceClient) GetChatMessagesByChatId(ctx context.Context, req *pb.GetMessagesByChatIdRequest) (*pb.GetMessagesByChatIdResponse, error) {
 	return c.Client.GetMessagesByChatId(ctx, req)
 }
+
+func (c *ChatServiceClient) ScheduleMessage(ctx context.Context, req *pb.ScheduleMessageRequest) (*pb.ScheduleMessageResponse, error) {
+	return c.Client.ScheduleMessage(ctx, req)
+}
+
+func (c *ChatServiceClient) GetScheduledMessages(ctx context.Context, req *pb.GetScheduledMessagesRequest) (*pb.GetScheduledMessagesResponse, error) {
+	return c.Client.GetScheduledMessages(ctx, req)
+}
+
+func (c *ChatServiceClient) UpdateScheduledMessage(ctx context.Context, req *pb.UpdateScheduledMessageRequest) (*pb.UpdateScheduledMessageResponse, error) {
+	return c.Client.UpdateScheduledMessage(ctx, req)
+}
+
+func (c *ChatServiceClient) CancelScheduledMessage(ctx context.Context, req *pb.CancelScheduledMessageRequest) (*pb.CancelScheduledMessageResponse, error) {
+	return c.Client.CancelScheduledMessage(ctx, req)
+}
+
+func (c *ChatServiceClient) CreatePoll(ctx context.Context, req *pb.CreatePollRequest) (*pb.CreatePollResponse, error) {
+	return c.Client.CreatePoll(ctx, req)
+}
+
+func (c *ChatServiceClient) GetPoll(ctx context.Context, req *pb.GetPollRequest) (*pb.GetPollResponse, error) {
+	return c.Client.GetPoll(ctx, req)
+}
+
+func (c *ChatServiceClient) Vote(ctx context.Context, req *pb.VoteRequest) (*pb.VoteResponse, error) {
+	return c.Client.Vote(ctx, req)
+}
+
+func (c *ChatServiceClient) ClosePoll(ctx context.Context, req *pb.ClosePollRequest) (*pb.ClosePollResponse, error) {
+	return c.Client.ClosePoll(ctx, req)
+}
+
+func (c *ChatServiceClient) PurgeMessages(ctx context.Context, req *pb.PurgeMessagesRequest) (*pb.PurgeMessagesResponse, error) {
+	return c.Client.PurgeMessages(ctx, req)
+}
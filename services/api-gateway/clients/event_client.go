@@ -1,251 +1,100 @@
 package clients
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
-	"time"
-
-	"github.com/wutthichod/sa-connext/shared/contracts"
-)
-
-type RouteDefinition struct {
-	Method string
-	Path   string
-}
 
-var routes = map[string]RouteDefinition{
-	"createEvent":       {Method: "POST", Path: "/events/"},
-	"getEvent":          {Method: "GET", Path: "/events/%s"},
-	"getAllEvents":      {Method: "GET", Path: "/events/"},
-	"joinEvent":         {Method: "POST", Path: "/events/join"},
-	"getEventsByUserID": {Method: "GET", Path: "/events/users/%s"},
-	"deleteEvent":       {Method: "DELETE", Path: "/events/%s"},
-}
+	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/resilience"
+	"github.com/wutthichod/sa-connext/shared/config"
+	"github.com/wutthichod/sa-connext/shared/correlation"
+	"github.com/wutthichod/sa-connext/shared/health"
+	pb "github.com/wutthichod/sa-connext/shared/proto/event"
+	"github.com/wutthichod/sa-connext/shared/tracing"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
 
 type EventServiceClient struct {
-	client *http.Client
-	addr   string
+	Client pb.EventServiceClient
+	conn   *grpc.ClientConn
 }
 
-func NewEventServiceClient(addr string) *EventServiceClient {
-	// Add http:// prefix if not present
-	if !strings.HasPrefix(addr, "http://") && !strings.HasPrefix(addr, "https://") {
-		addr = "http://" + addr
-	}
-
-	return &EventServiceClient{
-		client: &http.Client{
-			Timeout: 15 * time.Second,
-		},
-		addr: addr,
-	}
+// eventIdempotent lists the methods that are safe to retry
+var eventIdempotent = []string{
+	pb.EventService_GetEvent_FullMethodName,
+	pb.EventService_GetAllEvents_FullMethodName,
+	pb.EventService_GetEventsByUserId_FullMethodName,
 }
 
-func (c *EventServiceClient) CreateEvent(ctx context.Context, req *contracts.CreateEventRequest) (*contracts.Resp, error) {
-	route, ok := routes["createEvent"]
-	if !ok {
-		return nil, fmt.Errorf("route 'createEvent' not defined")
-	}
+func NewEventServiceClient(addr string, cfg config.Resilience) (*EventServiceClient, error) {
 
-	reqBodyBytes, err := json.Marshal(req)
+	conn, err := grpc.NewClient(addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(
+			resilience.UnaryClientInterceptor("event-service", cfg, eventIdempotent...),
+			correlation.UnaryClientInterceptor(),
+		),
+		tracing.DialOption(),
+	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request body: %w", err)
-	}
-
-	url := c.addr + route.Path
-	httpReq, err := http.NewRequestWithContext(ctx, route.Method, url, bytes.NewBuffer(reqBodyBytes))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create http request: %w", err)
-	}
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Accept", "application/json")
-
-	resp, err := c.client.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
-	}
-
-	defer resp.Body.Close()
-
-	// Read body first so we can use it for error messages if needed
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body (status %d): %w", resp.StatusCode, err)
-	}
-
-	var createEventResp contracts.Resp
-	if err := json.Unmarshal(bodyBytes, &createEventResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response body (status %d): %w, body: %s", resp.StatusCode, err, string(bodyBytes))
-	}
-
-	// Ensure status code is set from HTTP response
-	createEventResp.StatusCode = resp.StatusCode
-	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-		createEventResp.Success = true
-	} else {
-		createEventResp.Success = false
+		return nil, err
 	}
 
-	return &createEventResp, nil
+	client := pb.NewEventServiceClient(conn)
+	return &EventServiceClient{
+		Client: client,
+		conn:   conn,
+	}, nil
 }
 
-func (c *EventServiceClient) GetEventById(ctx context.Context, eventID string) (*contracts.Resp, error) {
-	route, ok := routes["getEvent"]
-	if !ok {
-		return nil, fmt.Errorf("route 'getEvent' not defined")
-	}
-
-	url := fmt.Sprintf(c.addr+route.Path, eventID)
-
-	httpReq, err := http.NewRequestWithContext(ctx, route.Method, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create http request: %w", err)
-	}
-	httpReq.Header.Set("Accept", "application/json")
-
-	httpRes, err := c.client.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+func (c *EventServiceClient) Close() {
+	if c.conn != nil {
+		if err := c.conn.Close(); err != nil {
+			return
+		}
 	}
-
-	defer httpRes.Body.Close()
-
-	var res contracts.Resp
-	if err := json.NewDecoder(httpRes.Body).Decode(&res); err != nil {
-		return nil, fmt.Errorf("failed to decode response body: %w", err)
-	}
-
-	return &res, nil
 }
 
-func (c *EventServiceClient) GetAllEvents(ctx context.Context) (*contracts.Resp, error) {
-	route, ok := routes["getAllEvents"]
-	if !ok {
-		return nil, fmt.Errorf("route 'getAllEvents' not defined")
-	}
-
-	url := c.addr + route.Path
-
-	httpReq, err := http.NewRequestWithContext(ctx, route.Method, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create http request: %w", err)
-	}
-	httpReq.Header.Set("Accept", "application/json")
-
-	httpRes, err := c.client.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
-	}
-
-	defer httpRes.Body.Close()
-
-	var res contracts.Resp
-	if err := json.NewDecoder(httpRes.Body).Decode(&res); err != nil {
-		return nil, fmt.Errorf("failed to decode response body: %w", err)
-	}
-
-	return &res, nil
+// Check asks the service behind the connection for its health
+func (c *EventServiceClient) Check(ctx context.Context) error {
+	return health.GRPC(c.conn)(ctx)
 }
 
-func (c *EventServiceClient) JoinEvent(ctx context.Context, req *contracts.JoinEventRequest) (*contracts.Resp, error) {
-	route, ok := routes["joinEvent"]
-	if !ok {
-		return nil, fmt.Errorf("route 'joinEvent' not defined")
-	}
-
-	reqBodyBytes, err := json.Marshal(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request body: %w", err)
-	}
-
-	url := c.addr + route.Path
-	httpReq, err := http.NewRequestWithContext(ctx, route.Method, url, bytes.NewBuffer(reqBodyBytes))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create http request: %w", err)
-	}
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Accept", "application/json")
-
-	resp, err := c.client.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
-	}
-
-	defer resp.Body.Close()
-
-	// Read body first so we can use it for error messages if needed
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body (status %d): %w", resp.StatusCode, err)
-	}
+func (c *EventServiceClient) CreateEvent(ctx context.Context, req *pb.CreateEventRequest) (*pb.CreateEventResponse, error) {
+	return c.Client.CreateEvent(ctx, req)
+}
 
-	var joinEventResp contracts.Resp
-	if err := json.Unmarshal(bodyBytes, &joinEventResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response body (status %d): %w, body: %s", resp.StatusCode, err, string(bodyBytes))
-	}
+func (c *EventServiceClient) GetEventById(ctx context.Context, req *pb.GetEventRequest) (*pb.GetEventResponse, error) {
+	return c.Client.GetEvent(ctx, req)
+}
 
-	// Ensure status code is set from HTTP response
-	joinEventResp.StatusCode = resp.StatusCode
-	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-		joinEventResp.Success = true
-	} else {
-		joinEventResp.Success = false
-	}
+func (c *EventServiceClient) GetAllEvents(ctx context.Context, req *pb.GetAllEventsRequest) (*pb.GetAllEventsResponse, error) {
+	return c.Client.GetAllEvents(ctx, req)
+}
 
-	return &joinEventResp, nil
+func (c *EventServiceClient) JoinEvent(ctx context.Context, req *pb.JoinEventRequest) (*pb.JoinEventResponse, error) {
+	return c.Client.JoinEvent(ctx, req)
 }
 
-func (c *EventServiceClient) GetEventsByUserID(ctx context.Context, userID string) (*contracts.Resp, error) {
-	route, ok := routes["getEventsByUserID"]
-	if !ok {
-		return nil, fmt.Errorf("route 'getEventsByUserID' not defined")
-	}
-	url := fmt.Sprintf(c.addr+route.Path, userID)
-	httpReq, err := http.NewRequestWithContext(ctx, route.Method, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create http request: %w", err)
-	}
-	httpReq.Header.Set("Accept", "application/json")
+func (c *EventServiceClient) GetEventsByUserID(ctx context.Context, req *pb.GetEventsByUserIdRequest) (*pb.GetEventsByUserIdResponse, error) {
+	return c.Client.GetEventsByUserId(ctx, req)
+}
 
-	httpRes, err := c.client.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
-	}
+func (c *EventServiceClient) UpdateEvent(ctx context.Context, req *pb.UpdateEventRequest) (*pb.UpdateEventResponse, error) {
+	return c.Client.UpdateEvent(ctx, req)
+}
 
-	defer httpRes.Body.Close()
+func (c *EventServiceClient) AddCoOrganizer(ctx context.Context, req *pb.CoOrganizerRequest) (*pb.CoOrganizerResponse, error) {
+	return c.Client.AddCoOrganizer(ctx, req)
+}
 
-	var res contracts.Resp
-	if err := json.NewDecoder(httpRes.Body).Decode(&res); err != nil {
-		return nil, fmt.Errorf("failed to decode response body: %w", err)
-	}
+func (c *EventServiceClient) RemoveCoOrganizer(ctx context.Context, req *pb.CoOrganizerRequest) (*pb.CoOrganizerResponse, error) {
+	return c.Client.RemoveCoOrganizer(ctx, req)
+}
 
-	return &res, nil
+func (c *EventServiceClient) DeleteEvent(ctx context.Context, req *pb.DeleteEventRequest) (*pb.DeleteEventResponse, error) {
+	return c.Client.DeleteEvent(ctx, req)
 }
 
-func (c *EventServiceClient) DeleteEvent(ctx context.Context, eventID string) (*contracts.Resp, error) {
-	route, ok := routes["deleteEvent"]
-	if !ok {
-		return nil, fmt.Errorf("route 'deleteEvent' not defined")
-	}
-	url := fmt.Sprintf(c.addr+route.Path, eventID)
-	httpReq, err := http.NewRequestWithContext(ctx, route.Method, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create http request: %w", err)
-	}
-	httpReq.Header.Set("Accept", "application/json")
-	httpRes, err := c.client.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
-	}
-	defer httpRes.Body.Close()
-	var res contracts.Resp
-	if err := json.NewDecoder(httpRes.Body).Decode(&res); err != nil {
-		return nil, fmt.Errorf("failed to decode response body: %w", err)
-	}
-	return &res, nil
+func (c *EventServiceClient) ForceDeleteEvent(ctx context.Context, req *pb.ForceDeleteEventRequest) (*pb.DeleteEventResponse, error) {
+	return c.Client.ForceDeleteEvent(ctx, req)
 }
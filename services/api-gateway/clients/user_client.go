@@ -3,8 +3,12 @@ package clients
 import (
 	"context"
 
+	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/resilience"
+	"github.com/wutthichod/sa-connext/shared/config"
+	"github.com/wutthichod/sa-connext/shared/correlation"
+	"github.com/wutthichod/sa-connext/shared/health"
 	pb "github.com/wutthichod/sa-connext/shared/proto/user"
-
+	"github.com/wutthichod/sa-connext/shared/tracing"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
@@ -14,9 +18,28 @@ type UserServiceClient struct {
 	conn   *grpc.ClientConn
 }
 
-func NewUserServiceClient(addr string) (*UserServiceClient, error) {
+// userIdempotent lists the methods that are safe to retry
+var userIdempotent = []string{
+	pb.UserService_GetUserById_FullMethodName,
+	pb.UserService_GetUsersByIds_FullMethodName,
+	pb.UserService_GetUsersByEventId_FullMethodName,
+	pb.UserService_GetUserKeys_FullMethodName,
+	pb.UserService_ListUsers_FullMethodName,
+	pb.UserService_GetSuspendedUserIds_FullMethodName,
+	pb.UserService_Logout_FullMethodName,
+	pb.UserService_GetRevocations_FullMethodName,
+}
+
+func NewUserServiceClient(addr string, cfg config.Resilience) (*UserServiceClient, error) {
 
-	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.NewClient(addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(
+			resilience.UnaryClientInterceptor("user-service", cfg, userIdempotent...),
+			correlation.UnaryClientInterceptor(),
+		),
+		tracing.DialOption(),
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -36,6 +59,11 @@ func (c *UserServiceClient) Close() {
 	}
 }
 
+// Check asks the service behind the connection for its health
+func (c *UserServiceClient) Check(ctx context.Context) error {
+	return health.GRPC(c.conn)(ctx)
+}
+
 func (c *UserServiceClient) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.CreateUserResponse, error) {
 	return c.Client.CreateUser(ctx, req)
 }
@@ -44,10 +72,38 @@ func (c *UserServiceClient) Login(ctx context.Context, req *pb.LoginRequest) (*p
 	return c.Client.Login(ctx, req)
 }
 
+func (c *UserServiceClient) Logout(ctx context.Context, req *pb.LogoutRequest) (*pb.LogoutResponse, error) {
+	return c.Client.Logout(ctx, req)
+}
+
+func (c *UserServiceClient) LogoutEverywhere(ctx context.Context, req *pb.LogoutEverywhereRequest) (*pb.LogoutEverywhereResponse, error) {
+	return c.Client.LogoutEverywhere(ctx, req)
+}
+
+func (c *UserServiceClient) GetRevocations(ctx context.Context, req *pb.GetRevocationsRequest) (*pb.GetRevocationsResponse, error) {
+	return c.Client.GetRevocations(ctx, req)
+}
+
+func (c *UserServiceClient) VerifyEmail(ctx context.Context, req *pb.VerifyEmailRequest) (*pb.VerifyEmailResponse, error) {
+	return c.Client.VerifyEmail(ctx, req)
+}
+
+func (c *UserServiceClient) ResendVerification(ctx context.Context, req *pb.ResendVerificationRequest) (*pb.ResendVerificationResponse, error) {
+	return c.Client.ResendVerification(ctx, req)
+}
+
+func (c *UserServiceClient) RefreshToken(ctx context.Context, req *pb.RefreshTokenRequest) (*pb.RefreshTokenResponse, error) {
+	return c.Client.RefreshToken(ctx, req)
+}
+
 func (c *UserServiceClient) GetUserByID(ctx context.Context, req *pb.GetUserByIdRequest) (*pb.GetUserByIdResponse, error) {
 	return c.Client.GetUserById(ctx, req)
 }
 
+func (c *UserServiceClient) GetUsersByIds(ctx context.Context, req *pb.GetUsersByIdsRequest) (*pb.GetUsersByIdsResponse, error) {
+	return c.Client.GetUsersByIds(ctx, req)
+}
+
 func (c *UserServiceClient) GetUserByEventID(ctx context.Context, req *pb.GetUsersByEventIdRequest) (*pb.GetUsersByEventIdResponse, error) {
 	return c.Client.GetUsersByEventId(ctx, req)
 }
@@ -63,3 +119,31 @@ func (c *UserServiceClient) LeaveEvent(ctx context.Context, req *pb.LeaveEventRe
 func (c *UserServiceClient) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.UpdateUserResponse, error) {
 	return c.Client.UpdateUser(ctx, req)
 }
+
+func (c *UserServiceClient) RegisterDeviceKey(ctx context.Context, req *pb.RegisterDeviceKeyRequest) (*pb.RegisterDeviceKeyResponse, error) {
+	return c.Client.RegisterDeviceKey(ctx, req)
+}
+
+func (c *UserServiceClient) GetUserKeys(ctx context.Context, req *pb.GetUserKeysRequest) (*pb.GetUserKeysResponse, error) {
+	return c.Client.GetUserKeys(ctx, req)
+}
+
+func (c *UserServiceClient) RemoveDeviceKey(ctx context.Context, req *pb.RemoveDeviceKeyRequest) (*pb.RemoveDeviceKeyResponse, error) {
+	return c.Client.RemoveDeviceKey(ctx, req)
+}
+
+func (c *UserServiceClient) ListUsers(ctx context.Context, req *pb.ListUsersRequest) (*pb.ListUsersResponse, error) {
+	return c.Client.ListUsers(ctx, req)
+}
+
+func (c *UserServiceClient) SuspendUser(ctx context.Context, req *pb.SuspendUserRequest) (*pb.SuspendUserResponse, error) {
+	return c.Client.SuspendUser(ctx, req)
+}
+
+func (c *UserServiceClient) ReinstateUser(ctx context.Context, req *pb.ReinstateUserRequest) (*pb.ReinstateUserResponse, error) {
+	return c.Client.ReinstateUser(ctx, req)
+}
+
+func (c *UserServiceClient) GetSuspendedUserIds(ctx context.Context, req *pb.GetSuspendedUserIdsRequest) (*pb.GetSuspendedUserIdsResponse, error) {
+	return c.Client.GetSuspendedUserIds(ctx, req)
+}
@@ -0,0 +1,29 @@
+package dto
+
+type SuspendUserRequest struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+}
+
+type ForceDeleteEventRequest struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+}
+
+type ListUsersResponse struct {
+	Users []AdminUser `json:"users"`
+	Total int64       `json:"total"`
+}
+
+// AdminUser is a user as admins see it, including the account state the
+// public profile leaves out
+type AdminUser struct {
+	UserID           string `json:"user_id"`
+	Username         string `json:"username"`
+	Email            string `json:"email"`
+	Role             string `json:"role"`
+	SuspendedAt      string `json:"suspended_at,omitempty"`
+	SuspensionReason string `json:"suspension_reason,omitempty"`
+}
+
+type PurgeMessagesResponse struct {
+	DeletedCount int64 `json:"deleted_count"`
+}
@@ -1,16 +1,17 @@
 package dto
 
 type CreateChatRequest struct {
-	RecipientID string `json:"recipient_id"`
+	RecipientID string `json:"recipient_id" validate:"required,numeric"`
 }
 
 type CreateGroupRequest struct {
-	GroupName string `json:"group_name"`
+	GroupName string `json:"group_name" validate:"required,max=100"`
 }
 
 type SendMessageRequest struct {
-	ChatID  string `json:"chat_id"`
-	Message string `json:"message"`
+	ChatID    string `json:"chat_id" validate:"required,objectid"`
+	Message   string `json:"message" validate:"required"`
+	Encrypted bool   `json:"encrypted"` // message is end-to-end encrypted ciphertext
 }
 
 type GetChatsResponse struct {
@@ -28,5 +29,73 @@ type GetMessagesByChatIdResponse struct {
 	SenderID    string `json:"sender_id"`
 	RecipientID string `json:"recipient_id"`
 	Message     string `json:"message"`
+	Type        string `json:"type"`
+	PollID      string `json:"poll_id,omitempty"`
+	Encrypted   bool   `json:"encrypted"`
 	CreatedAt   string `json:"created_at"`
 }
+
+type ScheduleMessageRequest struct {
+	ChatID  string `json:"chat_id" validate:"required,objectid"`
+	Message string `json:"message" validate:"required"`
+	SendAt  string `json:"send_at" validate:"required,rfc3339"`
+}
+
+type ScheduleMessageResponse struct {
+	ScheduledMessageID string `json:"scheduled_message_id"`
+	Status             string `json:"status"`
+}
+
+type UpdateScheduledMessageRequest struct {
+	Message string `json:"message" validate:"required_without=SendAt"`
+	SendAt  string `json:"send_at" validate:"required_without=Message,omitempty,rfc3339"`
+}
+
+type ScheduledMessageResponse struct {
+	ScheduledMessageID string `json:"scheduled_message_id"`
+	ChatID             string `json:"chat_id"`
+	Message            string `json:"message"`
+	SendAt             string `json:"send_at"`
+	Status             string `json:"status"`
+	CreatedAt          string `json:"created_at"`
+	UpdatedAt          string `json:"updated_at"`
+}
+
+type CreatePollRequest struct {
+	Question       string   `json:"question" validate:"required,max=300"`
+	Options        []string `json:"options" validate:"required,min=2,max=10,dive,required,max=100"`
+	MultipleChoice bool     `json:"multiple_choice"`
+	Anonymous      bool     `json:"anonymous"`
+	ClosesAt       string   `json:"closes_at" validate:"omitempty,rfc3339"` // optional
+}
+
+type CreatePollResponse struct {
+	PollID    string `json:"poll_id"`
+	MessageID string `json:"message_id"`
+}
+
+type VoteRequest struct {
+	OptionIDs []string `json:"option_ids" validate:"unique,dive,required"` // empty retracts the vote
+}
+
+type PollOptionResponse struct {
+	OptionID string   `json:"option_id"`
+	Text     string   `json:"text"`
+	Votes    int32    `json:"votes"`
+	VoterIDs []string `json:"voter_ids,omitempty"`
+}
+
+type PollResponse struct {
+	PollID         string               `json:"poll_id"`
+	ChatID         string               `json:"chat_id"`
+	MessageID      string               `json:"message_id"`
+	CreatorID      string               `json:"creator_id"`
+	Question       string               `json:"question"`
+	Options        []PollOptionResponse `json:"options"`
+	MultipleChoice bool                 `json:"multiple_choice"`
+	Anonymous      bool                 `json:"anonymous"`
+	ClosesAt       string               `json:"closes_at,omitempty"`
+	Closed         bool                 `json:"closed"`
+	TotalVoters    int32                `json:"total_voters"`
+	MyOptionIDs    []string             `json:"my_option_ids"`
+}
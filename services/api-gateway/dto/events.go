@@ -1,23 +1,36 @@
 package dto
 
 type CreateEventRequest struct {
-	Name        string `json:"name"`
-	Detail      string `json:"detail"`
-	Location    string `json:"location"`
-	Date        string `json:"date"`
-	OrganizerId string `json:"organizer_id"`
+	Name        string `json:"name" validate:"required,max=100"`
+	Detail      string `json:"detail" validate:"max=2000"`
+	Location    string `json:"location" validate:"max=200"`
+	Date        string `json:"date" validate:"required,rfc3339"`
+	OrganizerId string `json:"organizer_id"` // set from the token, never trusted from the body
 }
 
+// GetEventResponse omits the joining code unless the caller organizes the event
 type GetEventResponse struct {
-	EventID     uint   `json:"event_id"`
-	Name        string `json:"name"`
-	Detail      string `json:"detail"`
-	Location    string `json:"location"`
-	Date        string `json:"date"`
-	JoiningCode string `json:"joining_code"`
-	OrganizerId string `json:"organizer_id"`
+	EventID        uint     `json:"event_id"`
+	Name           string   `json:"name"`
+	Detail         string   `json:"detail"`
+	Location       string   `json:"location"`
+	Date           string   `json:"date"`
+	JoiningCode    string   `json:"joining_code,omitempty"`
+	OrganizerId    string   `json:"organizer_id"`
+	CoOrganizerIDs []string `json:"co_organizer_ids"`
+}
+
+type UpdateEventRequest struct {
+	Name     string `json:"name" validate:"required,max=100"`
+	Detail   string `json:"detail" validate:"max=2000"`
+	Location string `json:"location" validate:"max=200"`
+	Date     string `json:"date" validate:"required,rfc3339"`
+}
+
+type AddCoOrganizerRequest struct {
+	UserID string `json:"user_id" validate:"required,numeric"`
 }
 
 type JoinEventRequest struct {
-	JoiningCode string `json:"joining_code"`
+	JoiningCode string `json:"joining_code" validate:"required,len=6,alphanum"`
 }
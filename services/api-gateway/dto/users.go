@@ -5,8 +5,8 @@ type RegisterRequest struct {
 	Password  string    `json:"password" validate:"required,min=8"`
 	Contact   Contact   `json:"contact" validate:"required"`
 	Education Education `json:"education" validate:"required"`
-	JobTitle  string    `json:"jobTitle"`
-	Interests []string  `json:"interests"`
+	JobTitle  string    `json:"jobTitle" validate:"max=100"`
+	Interests []string  `json:"interests" validate:"max=20,dive,required,max=50"`
 }
 
 type Contact struct {
@@ -24,6 +24,19 @@ type LoginRequest struct {
 	Password string `json:"password" validate:"required,min=8"`
 }
 
+type AuthResponse struct {
+	Success      bool   `json:"success"`
+	JWTToken     string `json:"jwtToken"`
+	RefreshToken string `json:"refreshToken"`
+	ExpiresIn    int64  `json:"expiresIn"` // seconds until jwtToken expires
+}
+
+// RefreshRequest is only needed by clients without cookies; browsers send
+// the refresh_token cookie instead
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
 type User struct {
 	UserID    string   `json:"user_id"`
 	Username  string   `json:"username"`
@@ -34,6 +47,10 @@ type User struct {
 	Interests []string `json:"interests"`
 }
 
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
 type UpdateUserRequest struct {
 	Username  string    `json:"username" validate:"omitempty,min=2,max=50"`
 	Contact   Contact   `json:"contact" validate:"omitempty"`
@@ -41,3 +58,9 @@ type UpdateUserRequest struct {
 	JobTitle  string    `json:"jobTitle" validate:"omitempty"`
 	Interests []string  `json:"interests" validate:"omitempty"`
 }
+
+type RegisterDeviceKeyRequest struct {
+	DeviceID    string `json:"device_id" validate:"required,max=64"`
+	IdentityKey string `json:"identity_key" validate:"required,base64"` // base64 encoded public key
+	Algorithm   string `json:"algorithm"`                               // "x25519" (default) or "ed25519"
+}
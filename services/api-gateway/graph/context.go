@@ -0,0 +1,52 @@
+package graph
+
+import (
+	"context"
+	"time"
+
+	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/dataloader"
+	chatpb "github.com/wutthichod/sa-connext/shared/proto/chat"
+	eventpb "github.com/wutthichod/sa-connext/shared/proto/event"
+	userpb "github.com/wutthichod/sa-connext/shared/proto/user"
+)
+
+const (
+	// loaderWait is how long a loader collects keys before calling the backend
+	loaderWait = 2 * time.Millisecond
+	// maxUserBatch matches the most IDs user-service accepts per GetUsersByIds call
+	maxUserBatch = 100
+)
+
+type requestKey struct{}
+
+// request is the per-request state resolvers share: who is asking, and the
+// loaders that batch their lookups
+type request struct {
+	viewerID string
+	users    *dataloader.Loader[string, *userpb.User]
+	events   *dataloader.Loader[string, *eventpb.Event]
+	polls    *dataloader.Loader[string, *chatpb.Poll]
+}
+
+// WithViewer attaches the authenticated user and fresh dataloaders to ctx.
+// Call it once per HTTP request or websocket operation, so loaders only
+// cache what one operation read.
+func (r *Resolver) WithViewer(ctx context.Context, viewerID string) context.Context {
+	req := &request{viewerID: viewerID}
+	req.users = dataloader.New(r.loadUsers, loaderWait, maxUserBatch)
+	req.events = dataloader.New(func(ctx context.Context, ids []string) (map[string]*eventpb.Event, error) {
+		return r.loadEvents(ctx, viewerID, ids)
+	}, loaderWait, 0)
+	req.polls = dataloader.New(func(ctx context.Context, ids []string) (map[string]*chatpb.Poll, error) {
+		return r.loadPolls(ctx, viewerID, ids)
+	}, loaderWait, 0)
+	return context.WithValue(ctx, requestKey{}, req)
+}
+
+func requestFrom(ctx context.Context) (*request, error) {
+	req, ok := ctx.Value(requestKey{}).(*request)
+	if !ok || req.viewerID == "" {
+		return nil, &Error{Message: "authentication required", Code: "UNAUTHORIZED"}
+	}
+	return req, nil
+}
@@ -0,0 +1,25 @@
+package graph
+
+import (
+	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/errors"
+)
+
+// Error is a resolver error carrying the same error codes the REST routes
+// put in data.error_code
+type Error struct {
+	Message string
+	Code    string
+}
+
+func (e *Error) Error() string { return e.Message }
+
+// Extensions is copied into the GraphQL error
+func (e *Error) Extensions() map[string]any {
+	return map[string]any{"code": e.Code}
+}
+
+// fromGRPC converts a downstream gRPC error
+func fromGRPC(err error) error {
+	_, code, message := errors.FromGRPC(err)
+	return &Error{Message: message, Code: code}
+}
@@ -0,0 +1,87 @@
+package graph
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	chatpb "github.com/wutthichod/sa-connext/shared/proto/chat"
+	eventpb "github.com/wutthichod/sa-connext/shared/proto/event"
+	userpb "github.com/wutthichod/sa-connext/shared/proto/user"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// loadUsers fetches the profiles with one GetUsersByIds call
+func (r *Resolver) loadUsers(ctx context.Context, ids []string) (map[string]*userpb.User, error) {
+	res, err := r.Users.GetUsersByIds(ctx, &userpb.GetUsersByIdsRequest{UserIds: ids})
+	if err != nil {
+		return nil, fromGRPC(err)
+	}
+	users := make(map[string]*userpb.User, len(res.GetUsers()))
+	for _, user := range res.GetUsers() {
+		users[user.GetUserId()] = user
+	}
+	return users, nil
+}
+
+// loadEvents fetches events as seen by viewerID, which decides whether the
+// joining code is shown. They come one by one, since event-service has no
+// batch RPC; the loader still collapses repeated IDs into one call.
+func (r *Resolver) loadEvents(ctx context.Context, viewerID string, ids []string) (map[string]*eventpb.Event, error) {
+	return fanOut(ctx, ids, func(ctx context.Context, id string) (*eventpb.Event, bool, error) {
+		eventID, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			return nil, false, nil
+		}
+		res, err := r.Events.GetEventById(ctx, &eventpb.GetEventRequest{EventId: eventID, RequesterId: viewerID})
+		if status.Code(err) == codes.NotFound {
+			return nil, false, nil
+		}
+		if err != nil {
+			return nil, false, fromGRPC(err)
+		}
+		return res.GetEvent(), true, nil
+	})
+}
+
+// loadPolls fetches polls as seen by viewerID, which decides my_option_ids
+func (r *Resolver) loadPolls(ctx context.Context, viewerID string, ids []string) (map[string]*chatpb.Poll, error) {
+	return fanOut(ctx, ids, func(ctx context.Context, id string) (*chatpb.Poll, bool, error) {
+		res, err := r.Chats.GetPoll(ctx, &chatpb.GetPollRequest{UserId: viewerID, PollId: id})
+		if status.Code(err) == codes.NotFound {
+			return nil, false, nil
+		}
+		if err != nil {
+			return nil, false, fromGRPC(err)
+		}
+		return res.GetPoll(), true, nil
+	})
+}
+
+// fanOut calls load for every key concurrently and fails if any call fails
+func fanOut[V any](ctx context.Context, keys []string, load func(ctx context.Context, key string) (V, bool, error)) (map[string]V, error) {
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		values   = make(map[string]V, len(keys))
+		firstErr error
+	)
+	for _, key := range keys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, found, err := load(ctx, key)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err != nil && firstErr == nil:
+				firstErr = err
+			case found:
+				values[key] = value
+			}
+		}()
+	}
+	wg.Wait()
+	return values, firstErr
+}
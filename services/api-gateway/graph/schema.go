@@ -0,0 +1,413 @@
+// Package graph is the gateway's GraphQL schema. Its resolvers call the same
+// service clients as the REST handlers and batch related lookups, such as
+// chat participants and message senders, through per-request dataloaders.
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/wutthichod/sa-connext/services/api-gateway/clients"
+	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/graphql"
+	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/usercache"
+	"github.com/wutthichod/sa-connext/shared/contracts"
+	"github.com/wutthichod/sa-connext/shared/messaging"
+	chatpb "github.com/wutthichod/sa-connext/shared/proto/chat"
+	eventpb "github.com/wutthichod/sa-connext/shared/proto/event"
+	userpb "github.com/wutthichod/sa-connext/shared/proto/user"
+)
+
+type Resolver struct {
+	Users       *clients.UserServiceClient
+	Chats       *clients.ChatServiceClient
+	Events      *clients.EventServiceClient
+	Connections *messaging.ConnectionManager
+}
+
+// message is a chat message from either GetMessagesByChatId or the
+// chat.gateway delivery, which carries the stored message document
+type message struct {
+	ID        string `json:"_id"`
+	ChatID    string `json:"chat_id"`
+	SenderID  string `json:"sender_id"`
+	Body      string `json:"message"`
+	Type      string `json:"type"`
+	PollID    string `json:"poll_id"`
+	Encrypted bool   `json:"encrypted"`
+	CreatedAt string `json:"created_at"`
+}
+
+// NewSchema builds the schema around r
+func NewSchema(r *Resolver) *graphql.Schema {
+	user := &graphql.Object{Name: "User", Fields: []*graphql.FieldDef{
+		{Name: "id", Type: graphql.NonNullOf(graphql.ID), Resolve: userField(func(u *userpb.User) any { return u.GetUserId() })},
+		{Name: "username", Type: graphql.NonNullOf(graphql.String), Resolve: userField(func(u *userpb.User) any { return u.GetUsername() })},
+		{Name: "email", Type: graphql.String, Resolve: userField(func(u *userpb.User) any { return u.GetContact().GetEmail() })},
+		{Name: "phone", Type: graphql.String, Resolve: userField(func(u *userpb.User) any { return u.GetContact().GetPhone() })},
+		{Name: "university", Type: graphql.String, Resolve: userField(func(u *userpb.User) any { return u.GetEducation().GetUniversity() })},
+		{Name: "major", Type: graphql.String, Resolve: userField(func(u *userpb.User) any {
+			if major := u.GetEducation().GetMajor(); major != "" {
+				return major
+			}
+			return u.GetMajor()
+		})},
+		{Name: "jobTitle", Type: graphql.String, Resolve: userField(func(u *userpb.User) any { return u.GetJobTitle() })},
+		{Name: "interests", Type: graphql.ListOfNonNull(graphql.String), Resolve: userField(func(u *userpb.User) any { return u.GetInterests() })},
+	}}
+
+	pollOption := &graphql.Object{Name: "PollOption", Fields: []*graphql.FieldDef{
+		{Name: "id", Type: graphql.NonNullOf(graphql.ID), Resolve: pollOptionField(func(o *chatpb.PollOption) any { return o.GetOptionId() })},
+		{Name: "text", Type: graphql.NonNullOf(graphql.String), Resolve: pollOptionField(func(o *chatpb.PollOption) any { return o.GetText() })},
+		{Name: "votes", Type: graphql.NonNullOf(graphql.Int), Resolve: pollOptionField(func(o *chatpb.PollOption) any { return o.GetVotes() })},
+		{Name: "voters", Type: graphql.ListOfNonNull(user), Description: "Empty for anonymous polls",
+			Resolve: func(ctx context.Context, source any, _ graphql.Args) (any, error) {
+				return loadUserList(ctx, source.(*chatpb.PollOption).GetVoterIds())
+			}},
+	}}
+
+	poll := &graphql.Object{Name: "Poll", Fields: []*graphql.FieldDef{
+		{Name: "id", Type: graphql.NonNullOf(graphql.ID), Resolve: pollField(func(p *chatpb.Poll) any { return p.GetPollId() })},
+		{Name: "chatId", Type: graphql.NonNullOf(graphql.ID), Resolve: pollField(func(p *chatpb.Poll) any { return p.GetChatId() })},
+		{Name: "question", Type: graphql.NonNullOf(graphql.String), Resolve: pollField(func(p *chatpb.Poll) any { return p.GetQuestion() })},
+		{Name: "options", Type: graphql.ListOfNonNull(pollOption), Resolve: pollField(func(p *chatpb.Poll) any { return p.GetOptions() })},
+		{Name: "multipleChoice", Type: graphql.NonNullOf(graphql.Boolean), Resolve: pollField(func(p *chatpb.Poll) any { return p.GetMultipleChoice() })},
+		{Name: "anonymous", Type: graphql.NonNullOf(graphql.Boolean), Resolve: pollField(func(p *chatpb.Poll) any { return p.GetAnonymous() })},
+		{Name: "closesAt", Type: graphql.String, Resolve: pollField(func(p *chatpb.Poll) any { return optional(p.GetClosesAt()) })},
+		{Name: "closed", Type: graphql.NonNullOf(graphql.Boolean), Resolve: pollField(func(p *chatpb.Poll) any { return p.GetClosed() })},
+		{Name: "totalVoters", Type: graphql.NonNullOf(graphql.Int), Resolve: pollField(func(p *chatpb.Poll) any { return p.GetTotalVoters() })},
+		{Name: "myOptionIds", Type: graphql.ListOfNonNull(graphql.ID), Resolve: pollField(func(p *chatpb.Poll) any { return p.GetMyOptionIds() })},
+		{Name: "creator", Type: user, Resolve: func(ctx context.Context, source any, _ graphql.Args) (any, error) {
+			return loadUser(ctx, source.(*chatpb.Poll).GetCreatorId())
+		}},
+	}}
+
+	chatMessage := &graphql.Object{Name: "Message", Fields: []*graphql.FieldDef{
+		{Name: "id", Type: graphql.NonNullOf(graphql.ID), Resolve: messageField(func(m *message) any { return m.ID })},
+		{Name: "chatId", Type: graphql.NonNullOf(graphql.ID), Resolve: messageField(func(m *message) any { return m.ChatID })},
+		{Name: "senderId", Type: graphql.NonNullOf(graphql.ID), Resolve: messageField(func(m *message) any { return m.SenderID })},
+		{Name: "message", Type: graphql.NonNullOf(graphql.String), Description: "Ciphertext when encrypted is true",
+			Resolve: messageField(func(m *message) any { return m.Body })},
+		{Name: "type", Type: graphql.NonNullOf(graphql.String), Description: `"text" or "poll"`, Resolve: messageField(func(m *message) any { return m.Type })},
+		{Name: "encrypted", Type: graphql.NonNullOf(graphql.Boolean), Resolve: messageField(func(m *message) any { return m.Encrypted })},
+		{Name: "createdAt", Type: graphql.NonNullOf(graphql.String), Resolve: messageField(func(m *message) any { return m.CreatedAt })},
+		{Name: "sender", Type: user, Resolve: func(ctx context.Context, source any, _ graphql.Args) (any, error) {
+			return loadUser(ctx, source.(*message).SenderID)
+		}},
+		{Name: "poll", Type: poll, Resolve: func(ctx context.Context, source any, _ graphql.Args) (any, error) {
+			m := source.(*message)
+			if m.PollID == "" {
+				return nil, nil
+			}
+			req, err := requestFrom(ctx)
+			if err != nil {
+				return nil, err
+			}
+			p, found, err := req.polls.Load(ctx, m.PollID)
+			if !found || err != nil {
+				return nil, err
+			}
+			return p, nil
+		}},
+	}}
+
+	chat := &graphql.Object{Name: "Chat", Fields: []*graphql.FieldDef{
+		{Name: "id", Type: graphql.NonNullOf(graphql.ID), Resolve: chatField(func(c *chatpb.Chat) any { return c.GetChatId() })},
+		{Name: "isGroup", Type: graphql.NonNullOf(graphql.Boolean), Resolve: chatField(func(c *chatpb.Chat) any { return c.GetIsGroup() })},
+		{Name: "name", Type: graphql.NonNullOf(graphql.String), Description: "For direct chats, the other participant's username",
+			Resolve: func(ctx context.Context, source any, _ graphql.Args) (any, error) {
+				c := source.(*chatpb.Chat)
+				if c.GetIsGroup() || len(c.GetOtherParticipantIds()) == 0 {
+					return c.GetName(), nil
+				}
+				u, err := loadUser(ctx, c.GetOtherParticipantIds()[0])
+				if err != nil || u == nil {
+					return usercache.UnknownName, err
+				}
+				return u.(*userpb.User).GetUsername(), nil
+			}},
+		{Name: "participants", Type: graphql.ListOfNonNull(user), Description: "Everyone in the chat except the caller",
+			Resolve: func(ctx context.Context, source any, _ graphql.Args) (any, error) {
+				return loadUserList(ctx, source.(*chatpb.Chat).GetOtherParticipantIds())
+			}},
+		{Name: "lastMessageAt", Type: graphql.String, Resolve: chatField(func(c *chatpb.Chat) any { return optional(c.GetLastMessageAt()) })},
+		{Name: "createdAt", Type: graphql.NonNullOf(graphql.String), Resolve: chatField(func(c *chatpb.Chat) any { return c.GetCreatedAt() })},
+		{Name: "updatedAt", Type: graphql.NonNullOf(graphql.String), Resolve: chatField(func(c *chatpb.Chat) any { return c.GetUpdatedAt() })},
+		{Name: "messages", Type: graphql.ListOfNonNull(chatMessage), Resolve: func(ctx context.Context, source any, _ graphql.Args) (any, error) {
+			return r.messages(ctx, source.(*chatpb.Chat).GetChatId())
+		}},
+	}}
+
+	event := &graphql.Object{Name: "Event", Fields: []*graphql.FieldDef{
+		{Name: "id", Type: graphql.NonNullOf(graphql.ID), Resolve: eventField(func(e *eventpb.Event) any { return strconv.FormatUint(e.GetEventId(), 10) })},
+		{Name: "name", Type: graphql.NonNullOf(graphql.String), Resolve: eventField(func(e *eventpb.Event) any { return e.GetName() })},
+		{Name: "detail", Type: graphql.String, Resolve: eventField(func(e *eventpb.Event) any { return e.GetDetail() })},
+		{Name: "location", Type: graphql.String, Resolve: eventField(func(e *eventpb.Event) any { return e.GetLocation() })},
+		{Name: "date", Type: graphql.NonNullOf(graphql.String), Resolve: eventField(func(e *eventpb.Event) any { return e.GetDate() })},
+		// Null unless the viewer organizes the event
+		{Name: "joiningCode", Type: graphql.String, Resolve: eventField(func(e *eventpb.Event) any { return optional(e.GetJoiningCode()) })},
+		{Name: "organizer", Type: user, Resolve: func(ctx context.Context, source any, _ graphql.Args) (any, error) {
+			return loadUser(ctx, source.(*eventpb.Event).GetOrganizerId())
+		}},
+		{Name: "coOrganizers", Type: graphql.ListOfNonNull(user), Resolve: func(ctx context.Context, source any, _ graphql.Args) (any, error) {
+			return loadUserList(ctx, source.(*eventpb.Event).GetCoOrganizerIds())
+		}},
+		{Name: "attendees", Type: graphql.ListOfNonNull(user), Resolve: func(ctx context.Context, source any, _ graphql.Args) (any, error) {
+			return r.attendees(ctx, source.(*eventpb.Event))
+		}},
+	}}
+
+	query := &graphql.Object{Name: "Query", Fields: []*graphql.FieldDef{
+		{Name: "me", Type: graphql.NonNullOf(user), Resolve: func(ctx context.Context, _ any, _ graphql.Args) (any, error) {
+			req, err := requestFrom(ctx)
+			if err != nil {
+				return nil, err
+			}
+			u, err := loadUser(ctx, req.viewerID)
+			if u == nil && err == nil {
+				err = &Error{Message: "user not found", Code: "NOT_FOUND"}
+			}
+			return u, err
+		}},
+		{Name: "user", Type: user, Args: []*graphql.Argument{{Name: "id", Type: graphql.NonNullOf(graphql.ID)}},
+			Resolve: func(ctx context.Context, _ any, args graphql.Args) (any, error) {
+				return loadUser(ctx, args.String("id"))
+			}},
+		{Name: "events", Type: graphql.ListOfNonNull(event), Resolve: func(ctx context.Context, _ any, _ graphql.Args) (any, error) {
+			req, err := requestFrom(ctx)
+			if err != nil {
+				return nil, err
+			}
+			res, err := r.Events.GetAllEvents(ctx, &eventpb.GetAllEventsRequest{RequesterId: req.viewerID})
+			if err != nil {
+				return nil, fromGRPC(err)
+			}
+			return res.GetEvents(), nil
+		}},
+		{Name: "myEvents", Type: graphql.ListOfNonNull(event), Resolve: func(ctx context.Context, _ any, _ graphql.Args) (any, error) {
+			req, err := requestFrom(ctx)
+			if err != nil {
+				return nil, err
+			}
+			res, err := r.Events.GetEventsByUserID(ctx, &eventpb.GetEventsByUserIdRequest{UserId: req.viewerID})
+			if err != nil {
+				return nil, fromGRPC(err)
+			}
+			return res.GetEvents(), nil
+		}},
+		{Name: "event", Type: event, Args: []*graphql.Argument{{Name: "id", Type: graphql.NonNullOf(graphql.ID)}},
+			Resolve: func(ctx context.Context, _ any, args graphql.Args) (any, error) {
+				req, err := requestFrom(ctx)
+				if err != nil {
+					return nil, err
+				}
+				e, found, err := req.events.Load(ctx, args.String("id"))
+				if !found || err != nil {
+					return nil, err
+				}
+				return e, nil
+			}},
+		{Name: "chats", Type: graphql.ListOfNonNull(chat), Resolve: func(ctx context.Context, _ any, _ graphql.Args) (any, error) {
+			req, err := requestFrom(ctx)
+			if err != nil {
+				return nil, err
+			}
+			res, err := r.Chats.GetChats(ctx, &chatpb.GetChatsRequest{UserId: req.viewerID})
+			if err != nil {
+				return nil, fromGRPC(err)
+			}
+			return res.GetChats(), nil
+		}},
+		{Name: "messages", Type: graphql.ListOfNonNull(chatMessage), Args: []*graphql.Argument{{Name: "chatId", Type: graphql.NonNullOf(graphql.ID)}},
+			Resolve: func(ctx context.Context, _ any, args graphql.Args) (any, error) {
+				return r.messages(ctx, args.String("chatId"))
+			}},
+		{Name: "poll", Type: poll, Args: []*graphql.Argument{{Name: "id", Type: graphql.NonNullOf(graphql.ID)}},
+			Resolve: func(ctx context.Context, _ any, args graphql.Args) (any, error) {
+				req, err := requestFrom(ctx)
+				if err != nil {
+					return nil, err
+				}
+				p, found, err := req.polls.Load(ctx, args.String("id"))
+				if !found || err != nil {
+					return nil, err
+				}
+				return p, nil
+			}},
+	}}
+
+	subscription := &graphql.Object{Name: "Subscription", Fields: []*graphql.FieldDef{
+		{Name: "messageAdded", Type: graphql.NonNullOf(chatMessage),
+			Description: "Messages other participants send to the caller's chats, optionally only those in chatId",
+			Args:        []*graphql.Argument{{Name: "chatId", Type: graphql.ID}},
+			Subscribe:   r.subscribeMessages},
+	}}
+
+	return &graphql.Schema{Query: query, Subscription: subscription}
+}
+
+func (r *Resolver) messages(ctx context.Context, chatID string) ([]*message, error) {
+	req, err := requestFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res, err := r.Chats.GetChatMessagesByChatId(ctx, &chatpb.GetMessagesByChatIdRequest{ChatId: chatID, UserId: req.viewerID})
+	if err != nil {
+		return nil, fromGRPC(err)
+	}
+	messages := make([]*message, 0, len(res.GetMessages()))
+	for _, m := range res.GetMessages() {
+		messages = append(messages, &message{
+			ID:        m.GetMessageId(),
+			ChatID:    chatID,
+			SenderID:  m.GetSenderId(),
+			Body:      m.GetMessage(),
+			Type:      m.GetType(),
+			PollID:    m.GetPollId(),
+			Encrypted: m.GetEncrypted(),
+			CreatedAt: m.GetCreatedAt(),
+		})
+	}
+	return messages, nil
+}
+
+func (r *Resolver) attendees(ctx context.Context, event *eventpb.Event) ([]*userpb.User, error) {
+	res, err := r.Users.GetUserByEventID(ctx, &userpb.GetUsersByEventIdRequest{EventId: strconv.FormatUint(event.GetEventId(), 10)})
+	if err != nil {
+		return nil, fromGRPC(err)
+	}
+	return res.GetUsers(), nil
+}
+
+// subscribeMessages follows the same delivery path as the chat websocket:
+// the gateway's chat.gateway consumer hands each message to the connection
+// manager, which copies it to subscribers of the recipient
+func (r *Resolver) subscribeMessages(ctx context.Context, args graphql.Args) (<-chan any, error) {
+	req, err := requestFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	chatID := args.String("chatId")
+
+	deliveries, unsubscribe := r.Connections.Subscribe(req.viewerID)
+	events := make(chan any)
+	go func() {
+		defer close(events)
+		defer unsubscribe()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case delivery, ok := <-deliveries:
+				if !ok {
+					return
+				}
+				m, ok := toMessage(delivery)
+				if !ok || (chatID != "" && m.ChatID != chatID) {
+					continue
+				}
+				select {
+				case events <- m:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return events, nil
+}
+
+// toMessage picks new chat messages out of the deliveries, which also carry
+// poll updates
+func toMessage(delivery contracts.WSMessage) (*message, bool) {
+	raw, err := json.Marshal(delivery.Data)
+	if err != nil {
+		return nil, false
+	}
+	var m message
+	if err := json.Unmarshal(raw, &m); err != nil || m.ID == "" || m.ChatID == "" {
+		return nil, false
+	}
+	if m.Type == "" {
+		m.Type = "text"
+	}
+	return &m, true
+}
+
+func loadUser(ctx context.Context, id string) (any, error) {
+	if id == "" {
+		return nil, nil
+	}
+	req, err := requestFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	u, found, err := req.users.Load(ctx, id)
+	if !found || err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// loadUserList keeps the order of ids and leaves out users that no longer exist
+func loadUserList(ctx context.Context, ids []string) ([]*userpb.User, error) {
+	req, err := requestFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	found, err := req.users.LoadMany(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	users := make([]*userpb.User, 0, len(ids))
+	for _, id := range ids {
+		if u, ok := found[id]; ok {
+			users = append(users, u)
+		}
+	}
+	return users, nil
+}
+
+// optional maps empty strings to null
+func optional(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func userField(get func(*userpb.User) any) graphql.Resolver {
+	return func(_ context.Context, source any, _ graphql.Args) (any, error) {
+		return get(source.(*userpb.User)), nil
+	}
+}
+
+func chatField(get func(*chatpb.Chat) any) graphql.Resolver {
+	return func(_ context.Context, source any, _ graphql.Args) (any, error) {
+		return get(source.(*chatpb.Chat)), nil
+	}
+}
+
+func messageField(get func(*message) any) graphql.Resolver {
+	return func(_ context.Context, source any, _ graphql.Args) (any, error) {
+		return get(source.(*message)), nil
+	}
+}
+
+func pollField(get func(*chatpb.Poll) any) graphql.Resolver {
+	return func(_ context.Context, source any, _ graphql.Args) (any, error) {
+		return get(source.(*chatpb.Poll)), nil
+	}
+}
+
+func pollOptionField(get func(*chatpb.PollOption) any) graphql.Resolver {
+	return func(_ context.Context, source any, _ graphql.Args) (any, error) {
+		return get(source.(*chatpb.PollOption)), nil
+	}
+}
+
+func eventField(get func(*eventpb.Event) any) graphql.Resolver {
+	return func(_ context.Context, source any, _ graphql.Args) (any, error) {
+		return get(source.(*eventpb.Event)), nil
+	}
+}
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wutthichod/sa-connext/services/api-gateway/clients"
+	"github.com/wutthichod/sa-connext/services/api-gateway/dto"
+	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/errors"
+	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/middlewares"
+	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/openapi"
+	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/validation"
+	"github.com/wutthichod/sa-connext/shared/auth"
+	"github.com/wutthichod/sa-connext/shared/config"
+	"github.com/wutthichod/sa-connext/shared/contracts"
+	chatpb "github.com/wutthichod/sa-connext/shared/proto/chat"
+	eventpb "github.com/wutthichod/sa-connext/shared/proto/event"
+	userpb "github.com/wutthichod/sa-connext/shared/proto/user"
+)
+
+// AdminHandler serves the moderation routes, open to admins only
+type AdminHandler struct {
+	UserClient  *clients.UserServiceClient
+	EventClient *clients.EventServiceClient
+	ChatClient  *clients.ChatServiceClient
+	Config      *config.Config
+}
+
+func NewAdminHandler(uc *clients.UserServiceClient, ec *clients.EventServiceClient, cc *clients.ChatServiceClient, config *config.Config) *AdminHandler {
+	return &AdminHandler{UserClient: uc, EventClient: ec, ChatClient: cc, Config: config}
+}
+
+func (h *AdminHandler) RegisterRoutes(router fiber.Router) {
+	adminRoutes := router.Group("/admin", middlewares.JWTMiddleware(*h.Config), middlewares.RequireRole(auth.RoleAdmin))
+	adminRoutes.Get("/users", h.ListUsers)
+	adminRoutes.Post("/users/:id/suspend", h.SuspendUser)
+	adminRoutes.Delete("/users/:id/suspend", h.ReinstateUser)
+	adminRoutes.Delete("/users/:id/messages", h.PurgeUserMessages)
+	adminRoutes.Delete("/events/:eid", h.ForceDeleteEvent)
+	adminRoutes.Delete("/chats/:id/messages", h.PurgeChatMessages)
+}
+
+// Docs describes the routes registered above for the OpenAPI document
+func (h *AdminHandler) Docs() []openapi.Route {
+	const tag = "admin"
+	const adminOnly = "Admins only; other users get 403 FORBIDDEN."
+	return []openapi.Route{
+		{Method: fiber.MethodGet, Path: "/admin/users", Tag: tag, Auth: true, Summary: "Search users", Response: dto.ListUsersResponse{},
+			Description: adminOnly,
+			Query: []openapi.Param{
+				{Name: "q", Description: "Part of a username or email"},
+				{Name: "suspended", Description: "true to list suspended users only"},
+				{Name: "page", Description: "1-based page number"},
+				{Name: "page_size", Description: "Users per page, 50 by default and at most 200"},
+			}},
+		{Method: fiber.MethodPost, Path: "/admin/users/:id/suspend", Tag: tag, Auth: true, Summary: "Suspend a user", Request: dto.SuspendUserRequest{}, Response: dto.AdminUser{},
+			Description: "The user can no longer log in, and their existing tokens are refused. " + adminOnly},
+		{Method: fiber.MethodDelete, Path: "/admin/users/:id/suspend", Tag: tag, Auth: true, Summary: "Reinstate a suspended user", Response: dto.AdminUser{},
+			Description: adminOnly},
+		{Method: fiber.MethodDelete, Path: "/admin/users/:id/messages", Tag: tag, Auth: true, Summary: "Delete every message a user has sent", Response: dto.PurgeMessagesResponse{},
+			Description: adminOnly},
+		{Method: fiber.MethodDelete, Path: "/admin/events/:eid", Tag: tag, Auth: true, Summary: "Remove an event", Request: dto.ForceDeleteEventRequest{},
+			Description: "Unlike DELETE /events/:eid this works on any event. " + adminOnly},
+		{Method: fiber.MethodDelete, Path: "/admin/chats/:id/messages", Tag: tag, Auth: true, Summary: "Delete messages in a chat", Response: dto.PurgeMessagesResponse{},
+			Description: adminOnly,
+			Query:       []openapi.Param{{Name: "sender_id", Description: "Only messages from this user"}}},
+	}
+}
+
+func (h *AdminHandler) ListUsers(c *fiber.Ctx) error {
+	res, err := h.UserClient.ListUsers(c.UserContext(), &userpb.ListUsersRequest{
+		Query:         c.Query("q"),
+		SuspendedOnly: c.QueryBool("suspended"),
+		Page:          int32(c.QueryInt("page")),
+		PageSize:      int32(c.QueryInt("page_size")),
+	})
+	if err != nil {
+		return errors.HandleGRPCError(c, err)
+	}
+
+	users := make([]dto.AdminUser, 0, len(res.GetUsers()))
+	for _, user := range res.GetUsers() {
+		users = append(users, toAdminUser(user))
+	}
+	return c.Status(fiber.StatusOK).JSON(contracts.Resp{
+		Success: true,
+		Data:    dto.ListUsersResponse{Users: users, Total: res.GetTotal()},
+	})
+}
+
+func (h *AdminHandler) SuspendUser(c *fiber.Ctx) error {
+	var req dto.SuspendUserRequest
+	if err := validation.BindBody(c, &req); err != nil {
+		return validation.Respond(c, err)
+	}
+
+	res, err := h.UserClient.SuspendUser(c.UserContext(), &userpb.SuspendUserRequest{
+		UserId:  c.Params("id"),
+		AdminId: requesterID(c),
+		Reason:  req.Reason,
+	})
+	if err != nil {
+		return errors.HandleGRPCError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(contracts.Resp{
+		Success: true,
+		Data:    toAdminUser(res.GetUser()),
+	})
+}
+
+func (h *AdminHandler) ReinstateUser(c *fiber.Ctx) error {
+	res, err := h.UserClient.ReinstateUser(c.UserContext(), &userpb.ReinstateUserRequest{
+		UserId:  c.Params("id"),
+		AdminId: requesterID(c),
+	})
+	if err != nil {
+		return errors.HandleGRPCError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(contracts.Resp{
+		Success: true,
+		Data:    toAdminUser(res.GetUser()),
+	})
+}
+
+func (h *AdminHandler) PurgeUserMessages(c *fiber.Ctx) error {
+	return h.purgeMessages(c, &chatpb.PurgeMessagesRequest{SenderId: c.Params("id")})
+}
+
+func (h *AdminHandler) PurgeChatMessages(c *fiber.Ctx) error {
+	return h.purgeMessages(c, &chatpb.PurgeMessagesRequest{ChatId: c.Params("id"), SenderId: c.Query("sender_id")})
+}
+
+func (h *AdminHandler) purgeMessages(c *fiber.Ctx, req *chatpb.PurgeMessagesRequest) error {
+	req.AdminId = requesterID(c)
+	res, err := h.ChatClient.PurgeMessages(c.UserContext(), req)
+	if err != nil {
+		return errors.HandleGRPCError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(contracts.Resp{
+		Success: true,
+		Data:    dto.PurgeMessagesResponse{DeletedCount: res.GetDeletedCount()},
+	})
+}
+
+func (h *AdminHandler) ForceDeleteEvent(c *fiber.Ctx) error {
+	eventID, err := strconv.ParseUint(c.Params("eid"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(contracts.Resp{
+			Success: false,
+			Message: "Invalid event ID format",
+		})
+	}
+	var req dto.ForceDeleteEventRequest
+	if err := validation.BindBody(c, &req); err != nil {
+		return validation.Respond(c, err)
+	}
+
+	_, err = h.EventClient.ForceDeleteEvent(c.UserContext(), &eventpb.ForceDeleteEventRequest{
+		EventId: eventID,
+		AdminId: requesterID(c),
+		Reason:  req.Reason,
+	})
+	if err != nil {
+		return errors.HandleGRPCError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(contracts.Resp{
+		Success: true,
+	})
+}
+
+func toAdminUser(user *userpb.User) dto.AdminUser {
+	return dto.AdminUser{
+		UserID:           user.GetUserId(),
+		Username:         user.GetUsername(),
+		Email:            user.GetContact().GetEmail(),
+		Role:             user.GetRole(),
+		SuspendedAt:      user.GetSuspendedAt(),
+		SuspensionReason: user.GetSuspensionReason(),
+	}
+}
@@ -1,10 +1,8 @@
 package handlers
 
 import (
-	"encoding/json"
 	"fmt"
 	"log"
-	"os"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
@@ -13,28 +11,29 @@ import (
 	"github.com/wutthichod/sa-connext/services/api-gateway/dto"
 	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/errors"
 	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/middlewares"
+	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/openapi"
+	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/usercache"
+	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/validation"
 	"github.com/wutthichod/sa-connext/shared/config"
 	"github.com/wutthichod/sa-connext/shared/contracts"
+	"github.com/wutthichod/sa-connext/shared/correlation"
 	"github.com/wutthichod/sa-connext/shared/messaging"
 	pb "github.com/wutthichod/sa-connext/shared/proto/chat"
-	pbUser "github.com/wutthichod/sa-connext/shared/proto/user"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 )
 
 type ChatHandler struct {
 	ChatClient  *clients.ChatServiceClient
-	UserClient  *clients.UserServiceClient
+	UserCache   *usercache.Cache
 	ConnManager *messaging.ConnectionManager
 	Queue       *messaging.QueueConsumer
 	Config      *config.Config
 }
 
 // Constructor
-func NewChatHandler(chatClient *clients.ChatServiceClient, userClient *clients.UserServiceClient, connManager *messaging.ConnectionManager, queue *messaging.QueueConsumer, config *config.Config) *ChatHandler {
+func NewChatHandler(chatClient *clients.ChatServiceClient, userCache *usercache.Cache, connManager *messaging.ConnectionManager, queue *messaging.QueueConsumer, config *config.Config) *ChatHandler {
 	return &ChatHandler{
 		ChatClient:  chatClient,
-		UserClient:  userClient,
+		UserCache:   userCache,
 		ConnManager: connManager,
 		Queue:       queue,
 		Config:      config,
@@ -42,17 +41,51 @@ func NewChatHandler(chatClient *clients.ChatServiceClient, userClient *clients.U
 }
 
 // Register all chat routes
-func (h *ChatHandler) RegisterRoutes(app *fiber.App) {
-	chatRoutes := app.Group("/chats")
-	chatRoutes.Post("/", middlewares.JWTMiddleware(*h.Config), h.CreateChat)
+func (h *ChatHandler) RegisterRoutes(router fiber.Router) {
+	chatRoutes := router.Group("/chats")
+	chatRoutes.Post("/", middlewares.JWTMiddleware(*h.Config), middlewares.RequireVerified(*h.Config, middlewares.ActionDirectMessage), h.CreateChat)
 	chatRoutes.Post("/:id/join", middlewares.JWTMiddleware(*h.Config), h.JoinGroup)
-	chatRoutes.Post("/group", middlewares.JWTMiddleware(*h.Config), h.CreateGroup)
+	chatRoutes.Post("/group", middlewares.JWTMiddleware(*h.Config), middlewares.RequireVerified(*h.Config, middlewares.ActionCreateGroup), h.CreateGroup)
 	chatRoutes.Post("/send", middlewares.JWTMiddleware(*h.Config), h.SendMessage)
+	chatRoutes.Post("/scheduled", middlewares.JWTMiddleware(*h.Config), h.ScheduleMessage)
+	chatRoutes.Get("/scheduled", middlewares.JWTMiddleware(*h.Config), h.GetScheduledMessages)
+	chatRoutes.Put("/scheduled/:sid", middlewares.JWTMiddleware(*h.Config), h.UpdateScheduledMessage)
+	chatRoutes.Delete("/scheduled/:sid", middlewares.JWTMiddleware(*h.Config), h.CancelScheduledMessage)
+	chatRoutes.Post("/:id/polls", middlewares.JWTMiddleware(*h.Config), h.CreatePoll)
+	chatRoutes.Get("/polls/:pid", middlewares.JWTMiddleware(*h.Config), h.GetPoll)
+	chatRoutes.Post("/polls/:pid/vote", middlewares.JWTMiddleware(*h.Config), h.Vote)
+	chatRoutes.Post("/polls/:pid/close", middlewares.JWTMiddleware(*h.Config), h.ClosePoll)
 	chatRoutes.Get("/ws/", middlewares.JWTMiddleware(*h.Config), websocket.New(h.WebSocketHandler))
 	chatRoutes.Get("/", middlewares.JWTMiddleware(*h.Config), h.GetChats)
 	chatRoutes.Get("/:id/messages", middlewares.JWTMiddleware(*h.Config), h.GetChatMessagesByChatId)
 }
 
+// Docs describes the routes registered above for the OpenAPI document
+func (h *ChatHandler) Docs() []openapi.Route {
+	const tag = "chats"
+	return []openapi.Route{
+		{Method: fiber.MethodPost, Path: "/chats", Tag: tag, Auth: true, Summary: "Start a direct chat", Request: dto.CreateChatRequest{}, Status: fiber.StatusCreated,
+			Description: verifiedOnly},
+		{Method: fiber.MethodPost, Path: "/chats/:id/join", Tag: tag, Auth: true, Summary: "Join a group chat"},
+		{Method: fiber.MethodPost, Path: "/chats/group", Tag: tag, Auth: true, Summary: "Create a group chat", Request: dto.CreateGroupRequest{}, Status: fiber.StatusCreated,
+			Description: verifiedOnly},
+		{Method: fiber.MethodPost, Path: "/chats/send", Tag: tag, Auth: true, Summary: "Send a message", Request: dto.SendMessageRequest{}, Status: fiber.StatusCreated},
+		{Method: fiber.MethodPost, Path: "/chats/scheduled", Tag: tag, Auth: true, Summary: "Schedule a message", Request: dto.ScheduleMessageRequest{}, Response: dto.ScheduleMessageResponse{}, Status: fiber.StatusCreated},
+		{Method: fiber.MethodGet, Path: "/chats/scheduled", Tag: tag, Auth: true, Summary: "List pending scheduled messages", Response: []dto.ScheduledMessageResponse{},
+			Query: []openapi.Param{{Name: "chat_id", Description: "Only messages scheduled in this chat"}}},
+		{Method: fiber.MethodPut, Path: "/chats/scheduled/:sid", Tag: tag, Auth: true, Summary: "Edit a scheduled message", Request: dto.UpdateScheduledMessageRequest{}, Response: dto.ScheduledMessageResponse{}},
+		{Method: fiber.MethodDelete, Path: "/chats/scheduled/:sid", Tag: tag, Auth: true, Summary: "Cancel a scheduled message"},
+		{Method: fiber.MethodPost, Path: "/chats/:id/polls", Tag: tag, Auth: true, Summary: "Create a poll", Request: dto.CreatePollRequest{}, Response: dto.CreatePollResponse{}, Status: fiber.StatusCreated},
+		{Method: fiber.MethodGet, Path: "/chats/polls/:pid", Tag: tag, Auth: true, Summary: "Get a poll with its tally", Response: dto.PollResponse{}},
+		{Method: fiber.MethodPost, Path: "/chats/polls/:pid/vote", Tag: tag, Auth: true, Summary: "Vote on a poll", Request: dto.VoteRequest{}, Response: dto.PollResponse{}},
+		{Method: fiber.MethodPost, Path: "/chats/polls/:pid/close", Tag: tag, Auth: true, Summary: "Close a poll", Response: dto.PollResponse{}},
+		{Method: fiber.MethodGet, Path: "/chats/ws", Tag: tag, Auth: true, Summary: "Receive messages over a websocket", WebSocket: true},
+		{Method: fiber.MethodGet, Path: "/chats", Tag: tag, Auth: true, Summary: "List the caller's chats", Response: []dto.GetChatsResponse{}},
+		{Method: fiber.MethodGet, Path: "/chats/:id/messages", Tag: tag, Auth: true, Summary: "List the messages of a chat", Response: []dto.GetMessagesByChatIdResponse{},
+			Description: "Only participants of the chat may read it; anyone else gets 403 FORBIDDEN."},
+	}
+}
+
 // WebSocket handler extracted for clarity
 func (h *ChatHandler) WebSocketHandler(c *websocket.Conn) {
 	userID := c.Locals("userID").(uint)
@@ -72,38 +105,27 @@ func (h *ChatHandler) WebSocketHandler(c *websocket.Conn) {
 func (h *ChatHandler) CreateChat(c *fiber.Ctx) error {
 	senderID_uint := c.Locals("userID").(uint)
 	senderID := strconv.FormatUint(uint64(senderID_uint), 10)
-	fmt.Fprintf(os.Stdout, "[API Gateway] CreateChat: Sender ID (uint): %d, Sender ID (string): %s\n", senderID_uint, senderID)
+	correlation.Printf(c.UserContext(), "[API Gateway] CreateChat: Sender ID (uint): %d, Sender ID (string): %s", senderID_uint, senderID)
 
 	var req dto.CreateChatRequest
-	if err := c.BodyParser(&req); err != nil {
-		fmt.Fprintf(os.Stderr, "[API Gateway] CreateChat: Failed to parse request body: %v\n", err)
-		return c.Status(fiber.StatusBadRequest).JSON(contracts.Resp{
-			Success: false,
-			Message: "invalid json format",
-		})
+	if err := validation.BindBody(c, &req); err != nil {
+		correlation.Printf(c.UserContext(), "[API Gateway] CreateChat: Invalid request body: %v", err)
+		return validation.Respond(c, err)
 	}
 
-	fmt.Fprintf(os.Stdout, "[API Gateway] CreateChat: Request body - RecipientID: %s (type: %T)\n", req.RecipientID, req.RecipientID)
-
-	if req.RecipientID == "" {
-		fmt.Fprintf(os.Stderr, "[API Gateway] CreateChat: RecipientID is empty\n")
-		return c.Status(fiber.StatusBadRequest).JSON(contracts.Resp{
-			Success: false,
-			Message: "required recipientID",
-		})
-	}
+	correlation.Printf(c.UserContext(), "[API Gateway] CreateChat: Request body - RecipientID: %s (type: %T)", req.RecipientID, req.RecipientID)
 
-	fmt.Fprintf(os.Stdout, "[API Gateway] CreateChat: Creating chat with SenderId: %s, RecipientId: %s\n", senderID, req.RecipientID)
-	_, err := h.ChatClient.CreateChat(c.Context(), &pb.CreateChatRequest{
+	correlation.Printf(c.UserContext(), "[API Gateway] CreateChat: Creating chat with SenderId: %s, RecipientId: %s", senderID, req.RecipientID)
+	_, err := h.ChatClient.CreateChat(c.UserContext(), &pb.CreateChatRequest{
 		SenderId:    senderID,
 		RecipientId: req.RecipientID,
 	})
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "[API Gateway] CreateChat: Error from chat service: %v\n", err)
+		correlation.Printf(c.UserContext(), "[API Gateway] CreateChat: Error from chat service: %v", err)
 		return errors.HandleGRPCError(c, err)
 	}
 
-	fmt.Fprintf(os.Stdout, "[API Gateway] CreateChat: Chat created successfully\n")
+	correlation.Printf(c.UserContext(), "[API Gateway] CreateChat: Chat created successfully")
 	return c.Status(fiber.StatusCreated).JSON(contracts.Resp{
 		Success: true,
 	})
@@ -113,21 +135,11 @@ func (h *ChatHandler) CreateGroup(c *fiber.Ctx) error {
 	senderID_uint := c.Locals("userID").(uint)
 	senderID := strconv.FormatUint(uint64(senderID_uint), 10)
 	var req dto.CreateGroupRequest
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(contracts.Resp{
-			Success: false,
-			Message: "invalid json format",
-		})
+	if err := validation.BindBody(c, &req); err != nil {
+		return validation.Respond(c, err)
 	}
 
-	if req.GroupName == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(contracts.Resp{
-			Success: false,
-			Message: "empty group name",
-		})
-	}
-
-	_, err := h.ChatClient.CreateGroup(c.Context(), &pb.CreateGroupRequest{
+	_, err := h.ChatClient.CreateGroup(c.UserContext(), &pb.CreateGroupRequest{
 		SenderId:  senderID,
 		GroupName: req.GroupName,
 	})
@@ -145,7 +157,7 @@ func (h *ChatHandler) JoinGroup(c *fiber.Ctx) error {
 
 	chatID := c.Params("id")
 
-	_, err := h.ChatClient.JoinGroup(c.Context(), &pb.JoinGroupRequest{
+	_, err := h.ChatClient.JoinGroup(c.UserContext(), &pb.JoinGroupRequest{
 		UserId: userID,
 		ChatId: chatID,
 	})
@@ -162,24 +174,17 @@ func (h *ChatHandler) SendMessage(c *fiber.Ctx) error {
 	senderID_uint := c.Locals("userID").(uint)
 	senderID := strconv.FormatUint(uint64(senderID_uint), 10)
 	var req dto.SendMessageRequest
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(contracts.Resp{
-			Success: false,
-			Message: "invalid json format",
-		})
-	}
-
-	if req.ChatID == "" || req.Message == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(contracts.Resp{
-			Success: false,
-			Message: "required chat_id and message",
-		})
+	if err := validation.BindBody(c, &req); err != nil {
+		return validation.Respond(c, err)
 	}
 
-	_, err := h.ChatClient.SendMessage(c.Context(), &pb.SendMessageRequest{
-		SenderId: senderID,
-		ChatId:   req.ChatID,
-		Message:  req.Message,
+	_, err := h.ChatClient.SendMessage(c.UserContext(), &pb.SendMessageRequest{
+		SenderId:  senderID,
+		ChatId:    req.ChatID,
+		Message:   req.Message,
+		Encrypted: req.Encrypted,
+		// Only chat-service knows whether the chat is direct
+		DirectRestricted: middlewares.Restricted(c, *h.Config, middlewares.ActionDirectMessage),
 	})
 	if err != nil {
 		return errors.HandleGRPCError(c, err)
@@ -193,7 +198,7 @@ func (h *ChatHandler) SendMessage(c *fiber.Ctx) error {
 // Get chats by user id
 func (h *ChatHandler) GetChats(c *fiber.Ctx) error {
 	userID := c.Locals("userID").(uint)
-	res, err := h.ChatClient.GetChats(c.Context(), &pb.GetChatsRequest{
+	res, err := h.ChatClient.GetChats(c.UserContext(), &pb.GetChatsRequest{
 		UserId: fmt.Sprintf("%d", userID),
 	})
 	if err != nil {
@@ -203,79 +208,24 @@ func (h *ChatHandler) GetChats(c *fiber.Ctx) error {
 		return errors.HandleGRPCError(c, err)
 	}
 
-	// Collect all participant IDs to fetch usernames
-	participantIDs := make(map[string]bool)
+	// Resolve the other participant of every direct chat in one batch
+	var participantIDs []string
 	for _, chat := range res.Chats {
 		if !chat.IsGroup {
-			for _, participantID := range chat.OtherParticipantIds {
-				participantIDs[participantID] = true
-			}
+			participantIDs = append(participantIDs, chat.OtherParticipantIds...)
 		}
 	}
-
-	// Fetch usernames for all participants
-	participantNames := make(map[string]string)
-	for participantID := range participantIDs {
-		fmt.Fprintf(os.Stdout, "[API Gateway] GetChats: Attempting to fetch user %s\n", participantID)
-		userRes, err := h.UserClient.GetUserByID(c.Context(), &pbUser.GetUserByIdRequest{
-			UserId: participantID,
-		})
-		if err != nil {
-			// Check if it's a "not found" error - this is expected for some users
-			st, ok := status.FromError(err)
-			if ok && st.Code() == codes.NotFound {
-				fmt.Fprintf(os.Stdout, "[API Gateway] GetChats: User %s not found (expected for deleted/invalid users)\n", participantID)
-				participantNames[participantID] = "Unknown"
-			} else {
-				fmt.Fprintf(os.Stderr, "[API Gateway] GetChats: gRPC error for user %s: %v (code: %v)\n", participantID, err, func() string {
-					if ok {
-						return st.Code().String()
-					}
-					return "unknown"
-				}())
-				participantNames[participantID] = "Unknown"
-			}
-			continue
-		}
-		if userRes == nil {
-			fmt.Fprintf(os.Stderr, "[API Gateway] GetChats: userRes is nil for user %s\n", participantID)
-			participantNames[participantID] = "Unknown"
-			continue
-		}
-		fmt.Fprintf(os.Stdout, "[API Gateway] GetChats: User response for %s - Success: %v, User: %+v\n", participantID, userRes.Success, userRes.User)
-		if userRes.Success && userRes.User != nil {
-			username := userRes.User.Username
-			if username == "" {
-				fmt.Fprintf(os.Stderr, "[API Gateway] GetChats: Username is empty for user %s\n", participantID)
-				participantNames[participantID] = "Unknown"
-			} else {
-				participantNames[participantID] = username
-				fmt.Fprintf(os.Stdout, "[API Gateway] GetChats: Fetched username for user %s: %s\n", participantID, username)
-			}
-		} else {
-			fmt.Fprintf(os.Stderr, "[API Gateway] GetChats: Failed to fetch username for user %s - Success: %v, User nil: %v\n", participantID, userRes.Success, userRes.User == nil)
-			participantNames[participantID] = "Unknown"
-		}
-	}
-	fmt.Fprintf(os.Stdout, "[API Gateway] GetChats: All participant names: %+v\n", participantNames)
+	participantNames := h.UserCache.GetNames(c.UserContext(), participantIDs)
 
 	var chats []dto.GetChatsResponse
 	for _, chat := range res.Chats {
 		// For direct chats (not groups), set name to the other participant's username
 		chatName := chat.Name
 		if !chat.IsGroup && len(chat.OtherParticipantIds) > 0 {
-			// Get the first (and typically only) other participant's username
-			participantID := chat.OtherParticipantIds[0]
-			if username, ok := participantNames[participantID]; ok && username != "" {
-				chatName = username
-				fmt.Fprintf(os.Stdout, "[API Gateway] GetChats: Chat %s - setting name to participant username: %s\n", chat.ChatId, username)
-			} else {
-				chatName = "Unknown"
-				fmt.Fprintf(os.Stderr, "[API Gateway] GetChats: Chat %s - participant %s not found, using 'Unknown'\n", chat.ChatId, participantID)
-			}
+			chatName = participantNames[chat.OtherParticipantIds[0]]
 		}
 
-		chatResp := dto.GetChatsResponse{
+		chats = append(chats, dto.GetChatsResponse{
 			ChatID:             chat.ChatId,
 			IsGroup:            chat.IsGroup,
 			Name:               chatName,
@@ -283,15 +233,8 @@ func (h *ChatHandler) GetChats(c *fiber.Ctx) error {
 			LastMessageAt:      chat.LastMessageAt,
 			CreatedAt:          chat.CreatedAt,
 			UpdatedAt:          chat.UpdatedAt,
-		}
-		fmt.Fprintf(os.Stdout, "[API Gateway] GetChats: Chat response - ChatID: %s, Name: %s\n", chatResp.ChatID, chatResp.Name)
-		chats = append(chats, chatResp)
+		})
 	}
-	fmt.Fprintf(os.Stdout, "[API Gateway] GetChats: Returning %d chats\n", len(chats))
-
-	// Debug: Print the JSON that will be sent
-	jsonBytes, _ := json.Marshal(chats)
-	fmt.Fprintf(os.Stdout, "[API Gateway] GetChats: JSON response: %s\n", string(jsonBytes))
 
 	return c.Status(fiber.StatusOK).JSON(contracts.Resp{
 		Success:    true,
@@ -306,8 +249,10 @@ func (h *ChatHandler) GetChatMessagesByChatId(c *fiber.Ctx) error {
 	if chatID == "" {
 		return fiber.NewError(fiber.StatusBadRequest, "Chat ID is required")
 	}
-	res, err := h.ChatClient.GetChatMessagesByChatId(c.Context(), &pb.GetMessagesByChatIdRequest{
+	userID_uint := c.Locals("userID").(uint)
+	res, err := h.ChatClient.GetChatMessagesByChatId(c.UserContext(), &pb.GetMessagesByChatIdRequest{
 		ChatId: chatID,
+		UserId: strconv.FormatUint(uint64(userID_uint), 10),
 	})
 	if err != nil {
 		return errors.HandleGRPCError(c, err)
@@ -322,6 +267,9 @@ func (h *ChatHandler) GetChatMessagesByChatId(c *fiber.Ctx) error {
 			MessageID: message.MessageId,
 			SenderID:  message.SenderId,
 			Message:   message.Message,
+			Type:      message.Type,
+			PollID:    message.PollId,
+			Encrypted: message.Encrypted,
 			CreatedAt: message.CreatedAt,
 		})
 	}
@@ -331,6 +279,237 @@ func (h *ChatHandler) GetChatMessagesByChatId(c *fiber.Ctx) error {
 	})
 }
 
+// Schedule a message to be sent later
+func (h *ChatHandler) ScheduleMessage(c *fiber.Ctx) error {
+	senderID_uint := c.Locals("userID").(uint)
+	senderID := strconv.FormatUint(uint64(senderID_uint), 10)
+	var req dto.ScheduleMessageRequest
+	if err := validation.BindBody(c, &req); err != nil {
+		return validation.Respond(c, err)
+	}
+
+	res, err := h.ChatClient.ScheduleMessage(c.UserContext(), &pb.ScheduleMessageRequest{
+		SenderId:         senderID,
+		ChatId:           req.ChatID,
+		Message:          req.Message,
+		SendAt:           req.SendAt,
+		DirectRestricted: middlewares.Restricted(c, *h.Config, middlewares.ActionDirectMessage),
+	})
+	if err != nil {
+		return errors.HandleGRPCError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(contracts.Resp{
+		Success: true,
+		Data: dto.ScheduleMessageResponse{
+			ScheduledMessageID: res.ScheduledMessageId,
+			Status:             res.Status,
+		},
+	})
+}
+
+// List the caller's pending scheduled messages, optionally filtered by chat_id
+func (h *ChatHandler) GetScheduledMessages(c *fiber.Ctx) error {
+	senderID_uint := c.Locals("userID").(uint)
+	senderID := strconv.FormatUint(uint64(senderID_uint), 10)
+
+	res, err := h.ChatClient.GetScheduledMessages(c.UserContext(), &pb.GetScheduledMessagesRequest{
+		SenderId: senderID,
+		ChatId:   c.Query("chat_id"),
+	})
+	if err != nil {
+		return errors.HandleGRPCError(c, err)
+	}
+
+	scheduled := []dto.ScheduledMessageResponse{}
+	for _, message := range res.ScheduledMessages {
+		scheduled = append(scheduled, toScheduledMessageResponse(message))
+	}
+	return c.Status(fiber.StatusOK).JSON(contracts.Resp{
+		Success: true,
+		Data:    scheduled,
+	})
+}
+
+// Edit the text or send time of a pending scheduled message
+func (h *ChatHandler) UpdateScheduledMessage(c *fiber.Ctx) error {
+	senderID_uint := c.Locals("userID").(uint)
+	senderID := strconv.FormatUint(uint64(senderID_uint), 10)
+	var req dto.UpdateScheduledMessageRequest
+	if err := validation.BindBody(c, &req); err != nil {
+		return validation.Respond(c, err)
+	}
+
+	res, err := h.ChatClient.UpdateScheduledMessage(c.UserContext(), &pb.UpdateScheduledMessageRequest{
+		ScheduledMessageId: c.Params("sid"),
+		SenderId:           senderID,
+		Message:            req.Message,
+		SendAt:             req.SendAt,
+	})
+	if err != nil {
+		return errors.HandleGRPCError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(contracts.Resp{
+		Success: true,
+		Data:    toScheduledMessageResponse(res.ScheduledMessage),
+	})
+}
+
+// Cancel a pending scheduled message
+func (h *ChatHandler) CancelScheduledMessage(c *fiber.Ctx) error {
+	senderID_uint := c.Locals("userID").(uint)
+	senderID := strconv.FormatUint(uint64(senderID_uint), 10)
+
+	_, err := h.ChatClient.CancelScheduledMessage(c.UserContext(), &pb.CancelScheduledMessageRequest{
+		ScheduledMessageId: c.Params("sid"),
+		SenderId:           senderID,
+	})
+	if err != nil {
+		return errors.HandleGRPCError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(contracts.Resp{
+		Success: true,
+		Message: "Scheduled message cancelled",
+	})
+}
+
+func toScheduledMessageResponse(message *pb.ScheduledMessage) dto.ScheduledMessageResponse {
+	return dto.ScheduledMessageResponse{
+		ScheduledMessageID: message.GetScheduledMessageId(),
+		ChatID:             message.GetChatId(),
+		Message:            message.GetMessage(),
+		SendAt:             message.GetSendAt(),
+		Status:             message.GetStatus(),
+		CreatedAt:          message.GetCreatedAt(),
+		UpdatedAt:          message.GetUpdatedAt(),
+	}
+}
+
+// Create a poll in a group chat
+func (h *ChatHandler) CreatePoll(c *fiber.Ctx) error {
+	senderID_uint := c.Locals("userID").(uint)
+	senderID := strconv.FormatUint(uint64(senderID_uint), 10)
+	var req dto.CreatePollRequest
+	if err := validation.BindBody(c, &req); err != nil {
+		return validation.Respond(c, err)
+	}
+
+	res, err := h.ChatClient.CreatePoll(c.UserContext(), &pb.CreatePollRequest{
+		SenderId:       senderID,
+		ChatId:         c.Params("id"),
+		Question:       req.Question,
+		Options:        req.Options,
+		MultipleChoice: req.MultipleChoice,
+		Anonymous:      req.Anonymous,
+		ClosesAt:       req.ClosesAt,
+	})
+	if err != nil {
+		return errors.HandleGRPCError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(contracts.Resp{
+		Success: true,
+		Data: dto.CreatePollResponse{
+			PollID:    res.PollId,
+			MessageID: res.MessageId,
+		},
+	})
+}
+
+// Get a poll with its current tally
+func (h *ChatHandler) GetPoll(c *fiber.Ctx) error {
+	userID_uint := c.Locals("userID").(uint)
+	userID := strconv.FormatUint(uint64(userID_uint), 10)
+
+	res, err := h.ChatClient.GetPoll(c.UserContext(), &pb.GetPollRequest{
+		UserId: userID,
+		PollId: c.Params("pid"),
+	})
+	if err != nil {
+		return errors.HandleGRPCError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(contracts.Resp{
+		Success: true,
+		Data:    toPollResponse(res.Poll),
+	})
+}
+
+// Cast, change or retract a vote
+func (h *ChatHandler) Vote(c *fiber.Ctx) error {
+	userID_uint := c.Locals("userID").(uint)
+	userID := strconv.FormatUint(uint64(userID_uint), 10)
+	var req dto.VoteRequest
+	if err := validation.BindBody(c, &req); err != nil {
+		return validation.Respond(c, err)
+	}
+
+	res, err := h.ChatClient.Vote(c.UserContext(), &pb.VoteRequest{
+		UserId:    userID,
+		PollId:    c.Params("pid"),
+		OptionIds: req.OptionIDs,
+	})
+	if err != nil {
+		return errors.HandleGRPCError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(contracts.Resp{
+		Success: true,
+		Data:    toPollResponse(res.Poll),
+	})
+}
+
+// Close a poll early; only its creator may do this
+func (h *ChatHandler) ClosePoll(c *fiber.Ctx) error {
+	userID_uint := c.Locals("userID").(uint)
+	userID := strconv.FormatUint(uint64(userID_uint), 10)
+
+	res, err := h.ChatClient.ClosePoll(c.UserContext(), &pb.ClosePollRequest{
+		UserId: userID,
+		PollId: c.Params("pid"),
+	})
+	if err != nil {
+		return errors.HandleGRPCError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(contracts.Resp{
+		Success: true,
+		Data:    toPollResponse(res.Poll),
+	})
+}
+
+func toPollResponse(poll *pb.Poll) dto.PollResponse {
+	options := make([]dto.PollOptionResponse, 0, len(poll.GetOptions()))
+	for _, option := range poll.GetOptions() {
+		options = append(options, dto.PollOptionResponse{
+			OptionID: option.GetOptionId(),
+			Text:     option.GetText(),
+			Votes:    option.GetVotes(),
+			VoterIDs: option.GetVoterIds(),
+		})
+	}
+	myOptionIDs := poll.GetMyOptionIds()
+	if myOptionIDs == nil {
+		myOptionIDs = []string{}
+	}
+	return dto.PollResponse{
+		PollID:         poll.GetPollId(),
+		ChatID:         poll.GetChatId(),
+		MessageID:      poll.GetMessageId(),
+		CreatorID:      poll.GetCreatorId(),
+		Question:       poll.GetQuestion(),
+		Options:        options,
+		MultipleChoice: poll.GetMultipleChoice(),
+		Anonymous:      poll.GetAnonymous(),
+		ClosesAt:       poll.GetClosesAt(),
+		Closed:         poll.GetClosed(),
+		TotalVoters:    poll.GetTotalVoters(),
+		MyOptionIDs:    myOptionIDs,
+	}
+}
+
 // Start RabbitMQ consumer
 func (h *ChatHandler) ListenRabbit() {
 	if err := h.Queue.Start(); err != nil {
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wutthichod/sa-connext/services/api-gateway/graph"
+	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/openapi"
+	"github.com/wutthichod/sa-connext/shared/config"
+)
+
+// documented registers every handler's routes on a fresh app and returns it
+// together with the routes the handlers document
+func documented(t *testing.T) (*fiber.App, []openapi.Route) {
+	t.Helper()
+	var cfg config.Config // only read when a request comes in
+	chat := NewChatHandler(nil, nil, nil, nil, &cfg)
+	user := NewUserHandler(nil, nil, &cfg)
+	event := NewEventHandler(nil, nil, &cfg)
+	gql := NewGraphQLHandler(&graph.Resolver{}, &cfg)
+	admin := NewAdminHandler(nil, nil, nil, &cfg)
+
+	app := fiber.New()
+	chat.RegisterRoutes(app)
+	user.RegisterRoutes(app)
+	event.RegisterRoutes(app)
+	gql.RegisterRoutes(app)
+	admin.RegisterRoutes(app)
+
+	var routes []openapi.Route
+	routes = append(routes, chat.Docs()...)
+	routes = append(routes, user.Docs()...)
+	routes = append(routes, event.Docs()...)
+	routes = append(routes, gql.Docs()...)
+	routes = append(routes, admin.Docs()...)
+	return app, routes
+}
+
+func TestEveryRouteIsDocumented(t *testing.T) {
+	app, routes := documented(t)
+	doc, err := openapi.Build("test", "test", routes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	registered := map[string]bool{}
+	for _, route := range app.GetRoutes(true) {
+		// Fiber adds a HEAD route for every GET
+		if route.Method == fiber.MethodHead {
+			continue
+		}
+		key := route.Method + " " + openapi.Path(route.Path)
+		registered[key] = true
+
+		if _, ok := doc.Paths[openapi.Path(route.Path)][strings.ToLower(route.Method)]; !ok {
+			t.Errorf("%s has no OpenAPI entry; add it to the handler's Docs", key)
+		}
+	}
+
+	for _, route := range routes {
+		key := route.Method + " " + openapi.Path(route.Path)
+		if !registered[key] {
+			t.Errorf("%s is documented but not registered", key)
+		}
+	}
+}
+
+func TestBuildRejectsDuplicateRoutes(t *testing.T) {
+	_, routes := documented(t)
+	routes = append(routes, routes[0])
+	if _, err := openapi.Build("test", "test", routes); err == nil {
+		t.Fatalf("expected an error for %s %s documented twice", routes[0].Method, routes[0].Path)
+	}
+}
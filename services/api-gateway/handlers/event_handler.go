@@ -2,182 +2,329 @@ package handlers
 
 import (
 	"fmt"
-	"os"
+	"strconv"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/wutthichod/sa-connext/services/api-gateway/clients"
 	"github.com/wutthichod/sa-connext/services/api-gateway/dto"
+	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/errors"
+	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/httpcache"
 	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/middlewares"
+	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/openapi"
+	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/validation"
 	"github.com/wutthichod/sa-connext/shared/config"
 	"github.com/wutthichod/sa-connext/shared/contracts"
+	"github.com/wutthichod/sa-connext/shared/correlation"
+	pb "github.com/wutthichod/sa-connext/shared/proto/event"
 )
 
 type EventHandler struct {
 	EventClient *clients.EventServiceClient
+	Cache       *httpcache.Cache
 	Config      *config.Config
 }
 
-func NewEventHandler(client *clients.EventServiceClient, config *config.Config) *EventHandler {
-	return &EventHandler{client, config}
+func NewEventHandler(client *clients.EventServiceClient, cache *httpcache.Cache, config *config.Config) *EventHandler {
+	return &EventHandler{client, cache, config}
 }
 
-func (h *EventHandler) RegisterRoutes(app *fiber.App) {
-	eventRoutes := app.Group("/events")
-	eventRoutes.Get("/", middlewares.JWTMiddleware(*h.Config), h.GetAllEvents)
+func (h *EventHandler) RegisterRoutes(router fiber.Router) {
+	eventRoutes := router.Group("/events")
+	// Joining codes are only shown to organizers, so event responses are cached per caller
+	eventRoutes.Get("/", middlewares.JWTMiddleware(*h.Config),
+		middlewares.CacheMiddleware(h.Cache, httpcache.Rule{TTL: time.Minute, Tags: httpcache.Static(httpcache.EventListTag), PerUser: true}), h.GetAllEvents)
 	eventRoutes.Get("/user", middlewares.JWTMiddleware(*h.Config), h.GetEventsByUserID)
-	eventRoutes.Post("/", middlewares.JWTMiddleware(*h.Config), h.CreateEvent)
+	eventRoutes.Post("/", middlewares.JWTMiddleware(*h.Config), middlewares.RequireVerified(*h.Config, middlewares.ActionCreateEvent), h.CreateEvent)
 	eventRoutes.Post("/join", middlewares.JWTMiddleware(*h.Config), h.JoinEvent)
 	eventRoutes.Delete("/:eid", middlewares.JWTMiddleware(*h.Config), h.DeleteEvent)
-	eventRoutes.Get("/:eid", middlewares.JWTMiddleware(*h.Config), h.GetEventById)
+	eventRoutes.Put("/:eid", middlewares.JWTMiddleware(*h.Config), h.UpdateEvent)
+	eventRoutes.Post("/:eid/organizers", middlewares.JWTMiddleware(*h.Config), h.AddCoOrganizer)
+	eventRoutes.Delete("/:eid/organizers/:uid", middlewares.JWTMiddleware(*h.Config), h.RemoveCoOrganizer)
+	eventRoutes.Get("/:eid", middlewares.JWTMiddleware(*h.Config),
+		middlewares.CacheMiddleware(h.Cache, httpcache.Rule{TTL: 5 * time.Minute, Tags: httpcache.Param(httpcache.EventTag, "eid"), PerUser: true}), h.GetEventById)
+}
+
+// cachedRoute describes the routes behind CacheMiddleware
+const cachedRoute = "Served from the gateway cache with an ETag; send If-None-Match to get 304 when unchanged."
+
+// verifiedOnly describes the routes config.Verification can hold back from
+// unverified users
+const verifiedOnly = "Users who haven't verified their email may get 403 EMAIL_NOT_VERIFIED, depending on the gateway's policy (direct chats by default)."
+
+const (
+	organizersOnly = "Only the event's organizer and co-organizers may do this; anyone else gets 403 FORBIDDEN."
+	organizerOnly  = "Only the event's organizer may do this; anyone else gets 403 FORBIDDEN."
+)
+
+// Docs describes the routes registered above for the OpenAPI document
+func (h *EventHandler) Docs() []openapi.Route {
+	const tag = "events"
+	return []openapi.Route{
+		{Method: fiber.MethodGet, Path: "/events", Tag: tag, Auth: true, Summary: "List all events", Response: []dto.GetEventResponse{},
+			Description: "joining_code is only included for events the caller organizes. " + cachedRoute},
+		{Method: fiber.MethodGet, Path: "/events/user", Tag: tag, Auth: true, Summary: "List the caller's events", Response: []dto.GetEventResponse{}},
+		{Method: fiber.MethodPost, Path: "/events", Tag: tag, Auth: true, Summary: "Create an event", Request: dto.CreateEventRequest{}, Response: contracts.CreateEventResponse{}, Status: fiber.StatusCreated,
+			Description: verifiedOnly},
+		{Method: fiber.MethodPost, Path: "/events/join", Tag: tag, Auth: true, Summary: "Join an event with its joining code", Request: dto.JoinEventRequest{}, Response: contracts.JoinEventResponse{}},
+		{Method: fiber.MethodDelete, Path: "/events/:eid", Tag: tag, Auth: true, Summary: "Delete an event", Description: organizersOnly},
+		{Method: fiber.MethodPut, Path: "/events/:eid", Tag: tag, Auth: true, Summary: "Update an event's details", Request: dto.UpdateEventRequest{}, Response: dto.GetEventResponse{},
+			Description: organizersOnly},
+		{Method: fiber.MethodPost, Path: "/events/:eid/organizers", Tag: tag, Auth: true, Summary: "Add a co-organizer", Request: dto.AddCoOrganizerRequest{}, Response: dto.GetEventResponse{},
+			Description: organizerOnly},
+		{Method: fiber.MethodDelete, Path: "/events/:eid/organizers/:uid", Tag: tag, Auth: true, Summary: "Remove a co-organizer", Response: dto.GetEventResponse{},
+			Description: organizerOnly},
+		{Method: fiber.MethodGet, Path: "/events/:eid", Tag: tag, Auth: true, Summary: "Get an event", Response: dto.GetEventResponse{},
+			Description: "joining_code is only included if the caller organizes the event. " + cachedRoute},
+	}
 }
 
 func (h *EventHandler) GetAllEvents(c *fiber.Ctx) error {
-	ctx := c.Context()
-	res, err := h.EventClient.GetAllEvents(ctx)
+	res, err := h.EventClient.GetAllEvents(c.UserContext(), &pb.GetAllEventsRequest{RequesterId: requesterID(c)})
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(contracts.Resp{
-			Success: false,
-			Message: "Internal server error",
-		})
+		return errors.HandleGRPCError(c, err)
 	}
-	return c.Status(res.StatusCode).JSON(res)
+	return c.Status(fiber.StatusOK).JSON(contracts.Resp{
+		Success: true,
+		Data:    toEventResponses(res.GetEvents()),
+	})
 }
 
 func (h *EventHandler) GetEventById(c *fiber.Ctx) error {
-	ctx := c.Context()
-
-	eventID := c.Params("eid")
-	if eventID == "" {
+	eventID, err := strconv.ParseUint(c.Params("eid"), 10, 64)
+	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(contracts.Resp{
 			Success: false,
-			Message: "Event ID is required",
+			Message: "Invalid event ID format",
 		})
 	}
 
-	res, err := h.EventClient.GetEventById(ctx, eventID)
+	res, err := h.EventClient.GetEventById(c.UserContext(), &pb.GetEventRequest{EventId: eventID, RequesterId: requesterID(c)})
 	if err != nil {
-		fmt.Fprintf(os.Stdout, "Error calling event service: %v\n", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(contracts.Resp{
-			Success: false,
-			Message: "Internal server error",
-		})
+		correlation.Printf(c.UserContext(), "Error calling event service: %v", err)
+		return errors.HandleGRPCError(c, err)
 	}
 
-	if !res.Success {
-		return c.Status(res.StatusCode).JSON(res)
-	}
-
-	return c.Status(res.StatusCode).JSON(res)
+	return c.Status(fiber.StatusOK).JSON(contracts.Resp{
+		Success: true,
+		Data:    toEventResponse(res.GetEvent()),
+	})
 }
 
 func (h *EventHandler) CreateEvent(c *fiber.Ctx) error {
-	fmt.Fprintf(os.Stdout, "[API Gateway] CreateEvent: Request received\n")
-	ctx := c.Context()
+	correlation.Printf(c.UserContext(), "[API Gateway] CreateEvent: Request received")
+	ctx := c.UserContext()
 	userID := c.Locals("userID").(uint)
-	fmt.Fprintf(os.Stdout, "[API Gateway] CreateEvent: userID=%d\n", userID)
+	correlation.Printf(c.UserContext(), "[API Gateway] CreateEvent: userID=%d", userID)
 
 	req := &dto.CreateEventRequest{}
-	if err := c.BodyParser(req); err != nil {
-		fmt.Fprintf(os.Stderr, "[API Gateway] CreateEvent: BodyParser error: %v\n", err)
-		return c.Status(fiber.StatusBadRequest).JSON(contracts.Resp{
-			Success: false,
-			Message: fmt.Sprintf("Invalid JSON input: %v", err),
-		})
+	if err := validation.BindBody(c, req); err != nil {
+		correlation.Printf(c.UserContext(), "[API Gateway] CreateEvent: Invalid request body: %v", err)
+		return validation.Respond(c, err)
 	}
 
-	fmt.Fprintf(os.Stdout, "[API Gateway] CreateEvent: Parsed request - name=%s, location=%s, date=%s, detail=%s\n",
+	correlation.Printf(c.UserContext(), "[API Gateway] CreateEvent: Parsed request - name=%s, location=%s, date=%s, detail=%s",
 		req.Name, req.Location, req.Date, req.Detail)
 
-	contract := contracts.CreateEventRequest(*req)
-	contract.OrganizerId = fmt.Sprintf("%d", userID)
-	fmt.Fprintf(os.Stdout, "[API Gateway] CreateEvent: Calling event service with organizerID=%s\n", contract.OrganizerId)
+	organizerID := fmt.Sprintf("%d", userID)
+	correlation.Printf(c.UserContext(), "[API Gateway] CreateEvent: Calling event service with organizerID=%s", organizerID)
 
-	res, err := h.EventClient.CreateEvent(ctx, &contract)
+	res, err := h.EventClient.CreateEvent(ctx, &pb.CreateEventRequest{
+		Name:        req.Name,
+		Detail:      req.Detail,
+		Location:    req.Location,
+		Date:        req.Date,
+		OrganizerId: organizerID,
+	})
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "[API Gateway] CreateEvent: Event service call failed: %v\n", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(contracts.Resp{
-			Success: false,
-			Message: fmt.Sprintf("Internal server error: %v", err),
-		})
+		correlation.Printf(c.UserContext(), "[API Gateway] CreateEvent: Event service call failed: %v", err)
+		return errors.HandleGRPCError(c, err)
 	}
 
-	fmt.Fprintf(os.Stdout, "[API Gateway] CreateEvent: Event service response - success=%v, statusCode=%d\n", res.Success, res.StatusCode)
-	if !res.Success {
-		fmt.Fprintf(os.Stderr, "[API Gateway] CreateEvent: Event service returned error - message=%s\n", res.Message)
-		return c.Status(res.StatusCode).JSON(res)
-	}
-
-	fmt.Fprintf(os.Stdout, "[API Gateway] CreateEvent: Success\n")
-	return c.Status(fiber.StatusCreated).JSON(res)
+	correlation.Printf(c.UserContext(), "[API Gateway] CreateEvent: Success")
+	return c.Status(fiber.StatusCreated).JSON(contracts.Resp{
+		Success: true,
+		Data: contracts.CreateEventResponse{
+			EventID:     uint(res.GetEventId()),
+			JoiningCode: res.GetJoiningCode(),
+		},
+	})
 }
 
 func (h *EventHandler) JoinEvent(c *fiber.Ctx) error {
-	ctx := c.Context()
+	ctx := c.UserContext()
 
 	req := &dto.JoinEventRequest{}
 
-	if err := c.BodyParser(req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(contracts.Resp{
-			Success: false,
-			Message: "Invalid JSON input. Please check the request body.",
-		})
+	if err := validation.BindBody(c, req); err != nil {
+		return validation.Respond(c, err)
 	}
 
 	userID := c.Locals("userID").(uint)
 
-	contract := &contracts.JoinEventRequest{
-		UserID:      userID,
+	res, err := h.EventClient.JoinEvent(ctx, &pb.JoinEventRequest{
+		UserId:      strconv.FormatUint(uint64(userID), 10),
 		JoiningCode: req.JoiningCode,
+	})
+	if err != nil {
+		correlation.Printf(c.UserContext(), "Error calling event service: %v", err)
+		return errors.HandleGRPCError(c, err)
 	}
 
-	res, err := h.EventClient.JoinEvent(ctx, contract)
+	return c.Status(fiber.StatusOK).JSON(contracts.Resp{
+		Success: true,
+		Data:    contracts.JoinEventResponse{EventID: uint(res.GetEventId())},
+	})
+}
+
+func (h *EventHandler) GetEventsByUserID(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(uint)
+	res, err := h.EventClient.GetEventsByUserID(c.UserContext(), &pb.GetEventsByUserIdRequest{
+		UserId: strconv.FormatUint(uint64(userID), 10),
+	})
+	if err != nil {
+		return errors.HandleGRPCError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(contracts.Resp{
+		Success: true,
+		Data:    toEventResponses(res.GetEvents()),
+	})
+}
+
+func (h *EventHandler) DeleteEvent(c *fiber.Ctx) error {
+	eventID, err := strconv.ParseUint(c.Params("eid"), 10, 64)
 	if err != nil {
-		fmt.Fprintf(os.Stdout, "Error calling event service: %v\n", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(contracts.Resp{
-			Success:    false,
-			StatusCode: fiber.StatusInternalServerError,
-			Message:    "Internal server error while processing the request.",
+		return c.Status(fiber.StatusBadRequest).JSON(contracts.Resp{
+			Success: false,
+			Message: "Invalid event ID format",
 		})
 	}
+	correlation.Printf(c.UserContext(), "DeleteEvent called with eventID: %d", eventID)
+	if _, err := h.EventClient.DeleteEvent(c.UserContext(), &pb.DeleteEventRequest{EventId: eventID, RequesterId: requesterID(c)}); err != nil {
+		correlation.Printf(c.UserContext(), "Error calling event service DeleteEvent: %v", err)
+		return errors.HandleGRPCError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(contracts.Resp{
+		Success: true,
+		Message: "Event deleted successfully",
+	})
+}
 
-	if !res.Success {
-		return c.Status(res.StatusCode).JSON(res)
+func (h *EventHandler) UpdateEvent(c *fiber.Ctx) error {
+	eventID, err := strconv.ParseUint(c.Params("eid"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(contracts.Resp{
+			Success: false,
+			Message: "Invalid event ID format",
+		})
 	}
 
-	return c.Status(res.StatusCode).JSON(res)
+	req := &dto.UpdateEventRequest{}
+	if err := validation.BindBody(c, req); err != nil {
+		return validation.Respond(c, err)
+	}
+
+	res, err := h.EventClient.UpdateEvent(c.UserContext(), &pb.UpdateEventRequest{
+		EventId:     eventID,
+		RequesterId: requesterID(c),
+		Name:        req.Name,
+		Detail:      req.Detail,
+		Location:    req.Location,
+		Date:        req.Date,
+	})
+	if err != nil {
+		correlation.Printf(c.UserContext(), "Error calling event service UpdateEvent: %v", err)
+		return errors.HandleGRPCError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(contracts.Resp{
+		Success: true,
+		Data:    toEventResponse(res.GetEvent()),
+	})
 }
 
-func (h *EventHandler) GetEventsByUserID(c *fiber.Ctx) error {
-	ctx := c.Context()
-	userID := c.Locals("userID").(uint)
-	res, err := h.EventClient.GetEventsByUserID(ctx, fmt.Sprintf("%d", userID))
+func (h *EventHandler) AddCoOrganizer(c *fiber.Ctx) error {
+	eventID, err := strconv.ParseUint(c.Params("eid"), 10, 64)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(contracts.Resp{
+		return c.Status(fiber.StatusBadRequest).JSON(contracts.Resp{
 			Success: false,
-			Message: "Internal server error",
+			Message: "Invalid event ID format",
 		})
 	}
-	return c.Status(res.StatusCode).JSON(res)
+
+	req := &dto.AddCoOrganizerRequest{}
+	if err := validation.BindBody(c, req); err != nil {
+		return validation.Respond(c, err)
+	}
+
+	res, err := h.EventClient.AddCoOrganizer(c.UserContext(), &pb.CoOrganizerRequest{
+		EventId:     eventID,
+		RequesterId: requesterID(c),
+		UserId:      req.UserID,
+	})
+	if err != nil {
+		correlation.Printf(c.UserContext(), "Error calling event service AddCoOrganizer: %v", err)
+		return errors.HandleGRPCError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(contracts.Resp{
+		Success: true,
+		Data:    toEventResponse(res.GetEvent()),
+	})
 }
 
-func (h *EventHandler) DeleteEvent(c *fiber.Ctx) error {
-	ctx := c.Context()
-	eventID := c.Params("eid")
-	if eventID == "" {
+func (h *EventHandler) RemoveCoOrganizer(c *fiber.Ctx) error {
+	eventID, err := strconv.ParseUint(c.Params("eid"), 10, 64)
+	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(contracts.Resp{
 			Success: false,
-			Message: "Event ID is required",
+			Message: "Invalid event ID format",
 		})
 	}
-	fmt.Fprintf(os.Stdout, "DeleteEvent called with eventID: %s\n", eventID)
-	res, err := h.EventClient.DeleteEvent(ctx, eventID)
+	userID, err := strconv.ParseUint(c.Params("uid"), 10, 64)
 	if err != nil {
-		fmt.Fprintf(os.Stdout, "Error calling event service DeleteEvent: %v\n", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(contracts.Resp{
+		return c.Status(fiber.StatusBadRequest).JSON(contracts.Resp{
 			Success: false,
-			Message: "Internal server error",
+			Message: "Invalid user ID format",
 		})
 	}
-	fmt.Fprintf(os.Stdout, "DeleteEvent response: %+v\n", res)
-	return c.Status(res.StatusCode).JSON(res)
+
+	res, err := h.EventClient.RemoveCoOrganizer(c.UserContext(), &pb.CoOrganizerRequest{
+		EventId:     eventID,
+		RequesterId: requesterID(c),
+		UserId:      strconv.FormatUint(userID, 10),
+	})
+	if err != nil {
+		correlation.Printf(c.UserContext(), "Error calling event service RemoveCoOrganizer: %v", err)
+		return errors.HandleGRPCError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(contracts.Resp{
+		Success: true,
+		Data:    toEventResponse(res.GetEvent()),
+	})
+}
+
+// requesterID is the caller as event-service identifies users; what it
+// returns and allows depends on their role in the event
+func requesterID(c *fiber.Ctx) string {
+	return strconv.FormatUint(uint64(c.Locals("userID").(uint)), 10)
+}
+
+func toEventResponse(event *pb.Event) dto.GetEventResponse {
+	return dto.GetEventResponse{
+		EventID:        uint(event.GetEventId()),
+		Name:           event.GetName(),
+		Detail:         event.GetDetail(),
+		Location:       event.GetLocation(),
+		Date:           event.GetDate(),
+		JoiningCode:    event.GetJoiningCode(),
+		OrganizerId:    event.GetOrganizerId(),
+		CoOrganizerIDs: event.GetCoOrganizerIds(),
+	}
+}
+
+func toEventResponses(events []*pb.Event) []dto.GetEventResponse {
+	responses := make([]dto.GetEventResponse, 0, len(events))
+	for _, event := range events {
+		responses = append(responses, toEventResponse(event))
+	}
+	return responses
 }
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
+	"github.com/wutthichod/sa-connext/services/api-gateway/graph"
+	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/graphql"
+	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/middlewares"
+	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/openapi"
+	"github.com/wutthichod/sa-connext/shared/config"
+)
+
+type GraphQLHandler struct {
+	Resolver *graph.Resolver
+	Schema   *graphql.Schema
+	Config   *config.Config
+}
+
+// Constructor
+func NewGraphQLHandler(resolver *graph.Resolver, config *config.Config) *GraphQLHandler {
+	return &GraphQLHandler{
+		Resolver: resolver,
+		Schema:   graph.NewSchema(resolver),
+		Config:   config,
+	}
+}
+
+// Register all GraphQL routes
+func (h *GraphQLHandler) RegisterRoutes(router fiber.Router) {
+	graphqlRoutes := router.Group("/graphql")
+	graphqlRoutes.Get("/schema", h.GetSchema)
+	graphqlRoutes.Get("/ws", middlewares.JWTMiddleware(*h.Config), graphql.WebSocketHandler(h.Schema, func(ctx context.Context, conn *websocket.Conn) context.Context {
+		return h.Resolver.WithViewer(ctx, viewerID(conn.Locals("userID")))
+	}))
+
+	query := graphql.Handler(h.Schema, func(c *fiber.Ctx) context.Context {
+		return h.Resolver.WithViewer(c.UserContext(), viewerID(c.Locals("userID")))
+	})
+	graphqlRoutes.Post("/", middlewares.JWTMiddleware(*h.Config), query)
+	graphqlRoutes.Get("/", middlewares.JWTMiddleware(*h.Config), query)
+}
+
+// Docs describes the routes registered above for the OpenAPI document
+func (h *GraphQLHandler) Docs() []openapi.Route {
+	const tag = "graphql"
+	return []openapi.Route{
+		{Method: fiber.MethodPost, Path: "/graphql", Tag: tag, Auth: true, Summary: "Run a GraphQL query",
+			Description: "Errors are reported in the errors array with the REST error code in extensions.code",
+			Request:     graphql.Request{}, Response: graphql.Response{}, Unwrapped: true},
+		{Method: fiber.MethodGet, Path: "/graphql", Tag: tag, Auth: true, Summary: "Run a GraphQL query from the query string", Response: graphql.Response{}, Unwrapped: true,
+			Query: []openapi.Param{
+				{Name: "query", Required: true},
+				{Name: "operationName"},
+				{Name: "variables", Description: "JSON object"},
+			}},
+		{Method: fiber.MethodGet, Path: "/graphql/ws", Tag: tag, Auth: true, Summary: "Subscribe over the graphql-transport-ws protocol", WebSocket: true},
+		{Method: fiber.MethodGet, Path: "/graphql/schema", Tag: tag, Summary: "Get the schema in SDL", Unwrapped: true},
+	}
+}
+
+func (h *GraphQLHandler) GetSchema(c *fiber.Ctx) error {
+	c.Type("graphql")
+	return c.SendString(h.Schema.String())
+}
+
+func viewerID(local any) string {
+	id, ok := local.(uint)
+	if !ok {
+		return ""
+	}
+	return strconv.FormatUint(uint64(id), 10)
+}
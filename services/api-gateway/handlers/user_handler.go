@@ -2,50 +2,98 @@ package handlers
 
 import (
 	"fmt"
-	"log"
-	"os"
+	"strconv"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/wutthichod/sa-connext/services/api-gateway/clients"
 	"github.com/wutthichod/sa-connext/services/api-gateway/dto"
 	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/errors"
+	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/httpcache"
 	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/middlewares"
+	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/openapi"
+	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/validation"
+	"github.com/wutthichod/sa-connext/shared/auth"
 	"github.com/wutthichod/sa-connext/shared/config"
 	"github.com/wutthichod/sa-connext/shared/contracts"
+	"github.com/wutthichod/sa-connext/shared/correlation"
 	pb "github.com/wutthichod/sa-connext/shared/proto/user"
 )
 
 type UserHandler struct {
 	UserClient *clients.UserServiceClient
+	Cache      *httpcache.Cache
 	Config     *config.Config
 }
 
-func NewUserHandler(uc *clients.UserServiceClient, config *config.Config) *UserHandler {
-	return &UserHandler{UserClient: uc, Config: config}
+func NewUserHandler(uc *clients.UserServiceClient, cache *httpcache.Cache, config *config.Config) *UserHandler {
+	return &UserHandler{UserClient: uc, Cache: cache, Config: config}
 }
 
-func (h *UserHandler) RegisterRoutes(app *fiber.App) {
-	userRoutes := app.Group("/users")
+func (h *UserHandler) RegisterRoutes(router fiber.Router) {
+	userRoutes := router.Group("/users")
 	userRoutes.Post("/register", h.Register)
 	userRoutes.Post("/login", h.Login)
+	userRoutes.Post("/refresh", h.Refresh)
+	userRoutes.Post("/verify-email", h.VerifyEmail)
 	userRoutes.Post("/logout", h.Logout)
+	userRoutes.Post("/logout-everywhere", middlewares.JWTMiddleware(*h.Config), h.LogoutEverywhere)
 	userRoutes.Get("/me", middlewares.JWTMiddleware(*h.Config), h.GetMe)
+	userRoutes.Post("/me/verification", middlewares.JWTMiddleware(*h.Config), h.ResendVerification)
 	userRoutes.Put("/me", middlewares.JWTMiddleware(*h.Config), h.UpdateProfile)
 	userRoutes.Post("/leave-event", middlewares.JWTMiddleware(*h.Config), h.LeaveEvent)
-	userRoutes.Get("/:id", middlewares.JWTMiddleware(*h.Config), h.GetUserByID)
-	userRoutes.Get("/events/:eid", middlewares.JWTMiddleware(*h.Config), h.GetUserByEventID)
+	userRoutes.Put("/me/keys", middlewares.JWTMiddleware(*h.Config), h.RegisterDeviceKey)
+	userRoutes.Delete("/me/keys/:device_id", middlewares.JWTMiddleware(*h.Config), h.RemoveDeviceKey)
+	userRoutes.Get("/:id/keys", middlewares.JWTMiddleware(*h.Config), h.GetUserKeys)
+	userRoutes.Get("/:id", middlewares.JWTMiddleware(*h.Config),
+		middlewares.CacheMiddleware(h.Cache, httpcache.Rule{TTL: 5 * time.Minute, Tags: httpcache.Param(httpcache.UserTag, "id")}), h.GetUserByID)
+	userRoutes.Get("/events/:eid", middlewares.JWTMiddleware(*h.Config),
+		middlewares.CacheMiddleware(h.Cache, httpcache.Rule{TTL: time.Minute, Tags: httpcache.Static(httpcache.AttendeesTag)}), h.GetUserByEventID)
+}
+
+// authCookies describes the routes that sign the caller in
+const authCookies = "Also sets the token and refresh_token cookies."
+
+// Docs describes the routes registered above for the OpenAPI document
+func (h *UserHandler) Docs() []openapi.Route {
+	const tag = "users"
+	return []openapi.Route{
+		{Method: fiber.MethodPost, Path: "/users/register", Tag: tag, Summary: "Create an account and sign in", Request: dto.RegisterRequest{}, Response: dto.AuthResponse{}, Unwrapped: true, Status: fiber.StatusCreated,
+			Description: authCookies},
+		{Method: fiber.MethodPost, Path: "/users/login", Tag: tag, Summary: "Sign in", Request: dto.LoginRequest{}, Response: dto.AuthResponse{}, Unwrapped: true,
+			Description: authCookies},
+		{Method: fiber.MethodPost, Path: "/users/refresh", Tag: tag, Summary: "Trade a refresh token for new tokens", Request: dto.RefreshRequest{}, Response: dto.AuthResponse{}, Unwrapped: true,
+			Description: "Reads the refresh_token cookie, or refreshToken in the body. Each refresh token works once; using one again signs out every session started from the same login. " + authCookies},
+		{Method: fiber.MethodPost, Path: "/users/verify-email", Tag: tag, Summary: "Verify an email address", Request: dto.VerifyEmailRequest{},
+			Description: "Takes the token from the link in the verification email. Tokens issued before this still say the email is unverified, so signed-in clients should call /users/refresh afterwards."},
+		{Method: fiber.MethodPost, Path: "/users/logout", Tag: tag, Summary: "Sign out", Request: dto.RefreshRequest{},
+			Description: "Clears the token cookies and revokes the access token sent with the request, if still valid, along with the refresh token. Works without a valid token too."},
+		{Method: fiber.MethodPost, Path: "/users/logout-everywhere", Tag: tag, Auth: true, Summary: "Sign out on every device",
+			Description: "Revokes every access and refresh token the caller holds, and clears the token cookies."},
+		{Method: fiber.MethodGet, Path: "/users/me", Tag: tag, Auth: true, Summary: "Get the caller's profile", Response: pb.User{}},
+		{Method: fiber.MethodPost, Path: "/users/me/verification", Tag: tag, Auth: true, Summary: "Send the verification email again",
+			Description: "Links in earlier emails stop working. Answers 429 TOO_MANY_REQUESTS when the last email was sent moments ago."},
+		{Method: fiber.MethodPut, Path: "/users/me", Tag: tag, Auth: true, Summary: "Update the caller's profile", Request: dto.UpdateUserRequest{}, Response: pb.User{}},
+		{Method: fiber.MethodPost, Path: "/users/leave-event", Tag: tag, Auth: true, Summary: "Leave the caller's current event"},
+		{Method: fiber.MethodPut, Path: "/users/me/keys", Tag: tag, Auth: true, Summary: "Register a device identity key", Request: dto.RegisterDeviceKeyRequest{}, Response: pb.DeviceKey{}},
+		{Method: fiber.MethodDelete, Path: "/users/me/keys/:device_id", Tag: tag, Auth: true, Summary: "Remove a device identity key"},
+		{Method: fiber.MethodGet, Path: "/users/:id/keys", Tag: tag, Auth: true, Summary: "List a user's device identity keys", Response: []pb.DeviceKey{}},
+		{Method: fiber.MethodGet, Path: "/users/:id", Tag: tag, Auth: true, Summary: "Get a user", Response: pb.User{},
+			Description: cachedRoute},
+		{Method: fiber.MethodGet, Path: "/users/events/:eid", Tag: tag, Auth: true, Summary: "List the users in an event", Response: []pb.User{},
+			Description: cachedRoute},
+	}
 }
 
 func (h *UserHandler) Register(c *fiber.Ctx) error {
 	// Parse incoming JSON
 	var req dto.RegisterRequest
-	if err := c.BodyParser(&req); err != nil {
-		return errors.HandleGRPCError(c, err)
+	if err := validation.BindBody(c, &req); err != nil {
+		return validation.Respond(c, err)
 	}
 
 	// Call gRPC CreateUser
-	res, err := h.UserClient.CreateUser(c.Context(), &pb.CreateUserRequest{
+	res, err := h.UserClient.CreateUser(c.UserContext(), &pb.CreateUserRequest{
 		Username: req.Username,
 		Password: req.Password,
 		Contact: &pb.Contact{
@@ -63,54 +111,68 @@ func (h *UserHandler) Register(c *fiber.Ctx) error {
 		return errors.HandleGRPCError(c, err)
 	}
 
-	c.Cookie(&fiber.Cookie{
-		Name:     "token",
-		Value:    res.GetJwtToken(),
-		Expires:  time.Now().Add(24 * time.Hour), // cookie expires in 1 day
-		HTTPOnly: true,                           // not accessible via JS (important for security)
-		Secure:   true,                           // send only over HTTPS
-		SameSite: "None",                         // "Lax" or "None" for cross-site
-	})
+	setAuthCookies(c, res.GetJwtToken(), res.GetRefreshToken(), res.GetExpiresIn(), res.GetRefreshExpiresIn())
 
 	// Return gRPC response to HTTP client
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
-		"success":  res.GetSuccess(),
-		"jwtToken": res.GetJwtToken(),
+	return c.Status(fiber.StatusCreated).JSON(dto.AuthResponse{
+		Success:      res.GetSuccess(),
+		JWTToken:     res.GetJwtToken(),
+		RefreshToken: res.GetRefreshToken(),
+		ExpiresIn:    res.GetExpiresIn(),
 	})
 }
 
 func (h *UserHandler) Login(c *fiber.Ctx) error {
 	var req dto.LoginRequest
-	if err := c.BodyParser(&req); err != nil {
-		return errors.HandleGRPCError(c, err)
+	if err := validation.BindBody(c, &req); err != nil {
+		return validation.Respond(c, err)
 	}
 
-	res, err := h.UserClient.Client.Login(c.Context(), &pb.LoginRequest{
+	res, err := h.UserClient.Client.Login(c.UserContext(), &pb.LoginRequest{
 		Email:    req.Email,
 		Password: req.Password,
 	})
 	if err != nil {
-		log.Println(err)
+		correlation.Println(c.UserContext(), err)
 		return errors.HandleGRPCError(c, err)
 	}
 
-	c.Cookie(&fiber.Cookie{
-		Name:     "token",
-		Value:    res.GetJwtToken(),
-		Expires:  time.Now().Add(24 * time.Hour),
-		HTTPOnly: true,
-		Secure:   false,
-		SameSite: "None",
+	setAuthCookies(c, res.GetJwtToken(), res.GetRefreshToken(), res.GetExpiresIn(), res.GetRefreshExpiresIn())
+
+	return c.Status(fiber.StatusOK).JSON(dto.AuthResponse{
+		Success:      res.GetSuccess(),
+		JWTToken:     res.GetJwtToken(),
+		RefreshToken: res.GetRefreshToken(),
+		ExpiresIn:    res.GetExpiresIn(),
 	})
+}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"success":  res.GetSuccess(),
-		"jwtToken": res.GetJwtToken(),
+func (h *UserHandler) Refresh(c *fiber.Ctx) error {
+	refreshToken, err := refreshTokenFromRequest(c)
+	if err != nil {
+		return validation.Respond(c, err)
+	}
+	if refreshToken == "" {
+		return fiber.NewError(fiber.StatusUnauthorized, "missing refresh token")
+	}
+
+	res, err := h.UserClient.RefreshToken(c.UserContext(), &pb.RefreshTokenRequest{RefreshToken: refreshToken})
+	if err != nil {
+		return errors.HandleGRPCError(c, err)
+	}
+
+	setAuthCookies(c, res.GetJwtToken(), res.GetRefreshToken(), res.GetExpiresIn(), res.GetRefreshExpiresIn())
+
+	return c.Status(fiber.StatusOK).JSON(dto.AuthResponse{
+		Success:      res.GetSuccess(),
+		JWTToken:     res.GetJwtToken(),
+		RefreshToken: res.GetRefreshToken(),
+		ExpiresIn:    res.GetExpiresIn(),
 	})
 }
 
 func (h *UserHandler) GetUserByID(c *fiber.Ctx) error {
-	ctx := c.Context()
+	ctx := c.UserContext()
 
 	userID := c.Params("id")
 	if userID == "" {
@@ -137,10 +199,10 @@ func (h *UserHandler) GetUserByID(c *fiber.Ctx) error {
 }
 
 func (h *UserHandler) GetUserByEventID(c *fiber.Ctx) error {
-	ctx := c.Context()
+	ctx := c.UserContext()
 
 	eventID := c.Params("eid")
-	fmt.Fprintf(os.Stdout, "[API Gateway] GetUserByEventID: eventID=%s\n", eventID)
+	correlation.Printf(c.UserContext(), "[API Gateway] GetUserByEventID: eventID=%s", eventID)
 	if eventID == "" {
 		return c.Status(fiber.StatusBadRequest).JSON(contracts.Resp{
 			Success: false,
@@ -151,11 +213,11 @@ func (h *UserHandler) GetUserByEventID(c *fiber.Ctx) error {
 		EventId: eventID,
 	})
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "[API Gateway] GetUserByEventID: gRPC error: %v\n", err)
+		correlation.Printf(c.UserContext(), "[API Gateway] GetUserByEventID: gRPC error: %v", err)
 		return errors.HandleGRPCError(c, err)
 	}
 	if !res.Success {
-		fmt.Fprintf(os.Stderr, "[API Gateway] GetUserByEventID: service returned success=false\n")
+		correlation.Printf(c.UserContext(), "[API Gateway] GetUserByEventID: service returned success=false")
 		// Return error response when the gRPC call succeeded but returned failure
 		return c.Status(fiber.StatusInternalServerError).JSON(contracts.Resp{
 			Success: false,
@@ -163,7 +225,7 @@ func (h *UserHandler) GetUserByEventID(c *fiber.Ctx) error {
 		})
 	}
 
-	fmt.Fprintf(os.Stdout, "[API Gateway] GetUserByEventID: success, found %d users\n", len(res.GetUsers()))
+	correlation.Printf(c.UserContext(), "[API Gateway] GetUserByEventID: success, found %d users", len(res.GetUsers()))
 	return c.Status(fiber.StatusOK).JSON(contracts.Resp{
 		Success: true,
 		Data:    res.GetUsers(),
@@ -171,7 +233,7 @@ func (h *UserHandler) GetUserByEventID(c *fiber.Ctx) error {
 }
 
 func (h *UserHandler) GetMe(c *fiber.Ctx) error {
-	ctx := c.Context()
+	ctx := c.UserContext()
 	userID := c.Locals("userID").(uint)
 
 	res, err := h.UserClient.GetUserByID(ctx, &pb.GetUserByIdRequest{
@@ -192,12 +254,12 @@ func (h *UserHandler) GetMe(c *fiber.Ctx) error {
 }
 
 func (h *UserHandler) UpdateProfile(c *fiber.Ctx) error {
-	ctx := c.Context()
+	ctx := c.UserContext()
 	userID := c.Locals("userID").(uint)
 
 	var req dto.UpdateUserRequest
-	if err := c.BodyParser(&req); err != nil {
-		return errors.HandleGRPCError(c, err)
+	if err := validation.BindBody(c, &req); err != nil {
+		return validation.Respond(c, err)
 	}
 
 	res, err := h.UserClient.UpdateUser(ctx, &pb.UpdateUserRequest{
@@ -229,7 +291,7 @@ func (h *UserHandler) UpdateProfile(c *fiber.Ctx) error {
 }
 
 func (h *UserHandler) LeaveEvent(c *fiber.Ctx) error {
-	ctx := c.Context()
+	ctx := c.UserContext()
 	userID := c.Locals("userID").(uint)
 
 	res, err := h.UserClient.LeaveEvent(ctx, &pb.LeaveEventRequest{
@@ -252,17 +314,192 @@ func (h *UserHandler) LeaveEvent(c *fiber.Ctx) error {
 	})
 }
 
+// RegisterDeviceKey stores or replaces the public identity key of one of the caller's devices
+func (h *UserHandler) RegisterDeviceKey(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	userID := c.Locals("userID").(uint)
+
+	var req dto.RegisterDeviceKeyRequest
+	if err := validation.BindBody(c, &req); err != nil {
+		return validation.Respond(c, err)
+	}
+
+	res, err := h.UserClient.RegisterDeviceKey(ctx, &pb.RegisterDeviceKeyRequest{
+		UserId:      fmt.Sprintf("%d", userID),
+		DeviceId:    req.DeviceID,
+		IdentityKey: req.IdentityKey,
+		Algorithm:   req.Algorithm,
+	})
+	if err != nil {
+		return errors.HandleGRPCError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(contracts.Resp{
+		Success: true,
+		Data:    res.GetKey(),
+	})
+}
+
+// GetUserKeys returns the public identity keys of every device of a user
+func (h *UserHandler) GetUserKeys(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	userID := c.Params("id")
+	if userID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(contracts.Resp{
+			Success: false,
+			Message: "User ID is required",
+		})
+	}
+
+	res, err := h.UserClient.GetUserKeys(ctx, &pb.GetUserKeysRequest{
+		UserId: userID,
+	})
+	if err != nil {
+		return errors.HandleGRPCError(c, err)
+	}
+
+	keys := res.GetKeys()
+	if keys == nil {
+		keys = []*pb.DeviceKey{}
+	}
+	return c.Status(fiber.StatusOK).JSON(contracts.Resp{
+		Success: true,
+		Data:    keys,
+	})
+}
+
+// RemoveDeviceKey deletes the key of one of the caller's devices
+func (h *UserHandler) RemoveDeviceKey(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	userID := c.Locals("userID").(uint)
+
+	_, err := h.UserClient.RemoveDeviceKey(ctx, &pb.RemoveDeviceKeyRequest{
+		UserId:   fmt.Sprintf("%d", userID),
+		DeviceId: c.Params("device_id"),
+	})
+	if err != nil {
+		return errors.HandleGRPCError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(contracts.Resp{
+		Success: true,
+		Message: "Device key removed",
+	})
+}
+
+func (h *UserHandler) VerifyEmail(c *fiber.Ctx) error {
+	var req dto.VerifyEmailRequest
+	if err := validation.BindBody(c, &req); err != nil {
+		return validation.Respond(c, err)
+	}
+
+	_, err := h.UserClient.VerifyEmail(c.UserContext(), &pb.VerifyEmailRequest{Token: req.Token})
+	if err != nil {
+		return errors.HandleGRPCError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(contracts.Resp{
+		Success: true,
+		Message: "Email verified",
+	})
+}
+
+func (h *UserHandler) ResendVerification(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(uint)
+	_, err := h.UserClient.ResendVerification(c.UserContext(), &pb.ResendVerificationRequest{
+		UserId: strconv.FormatUint(uint64(userID), 10),
+	})
+	if err != nil {
+		return errors.HandleGRPCError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(contracts.Resp{
+		Success: true,
+		Message: "Verification email sent",
+	})
+}
+
+// Logout works without a valid token, so a client holding an expired one can
+// still clear its cookies. The refresh token is always revoked; the access
+// token only while it is still valid.
 func (h *UserHandler) Logout(c *fiber.Ctx) error {
+	refreshToken, err := refreshTokenFromRequest(c)
+	if err != nil {
+		return validation.Respond(c, err)
+	}
+	clearAuthCookies(c)
+
+	req := &pb.LogoutRequest{RefreshToken: refreshToken}
+	if claims, err := auth.ValidateToken(auth.JWKS((*h.Config).JWT().JWKSURL), middlewares.TokenFromRequest(c)); err == nil {
+		req.UserId = strconv.FormatUint(uint64(claims.UserID), 10)
+		req.Jti = claims.ID
+		if claims.ExpiresAt != nil {
+			req.ExpiresAt = claims.ExpiresAt.Unix()
+		}
+	}
+	if _, err := h.UserClient.Logout(c.UserContext(), req); err != nil {
+		return errors.HandleGRPCError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"message": "Successfully logged out",
+	})
+}
+
+func (h *UserHandler) LogoutEverywhere(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(uint)
+	_, err := h.UserClient.LogoutEverywhere(c.UserContext(), &pb.LogoutEverywhereRequest{
+		UserId: strconv.FormatUint(uint64(userID), 10),
+	})
+	if err != nil {
+		return errors.HandleGRPCError(c, err)
+	}
+	clearAuthCookies(c)
+
+	return c.Status(fiber.StatusOK).JSON(contracts.Resp{
+		Success: true,
+		Message: "Logged out on every device",
+	})
+}
+
+// refreshTokenFromRequest reads the refresh_token cookie, or the body of
+// clients without cookies
+func refreshTokenFromRequest(c *fiber.Ctx) (string, error) {
+	if token := c.Cookies(refreshCookie); token != "" {
+		return token, nil
+	}
+	if len(c.Body()) == 0 {
+		return "", nil
+	}
+	var req dto.RefreshRequest
+	if err := validation.BindBody(c, &req); err != nil {
+		return "", err
+	}
+	return req.RefreshToken, nil
+}
+
+const refreshCookie = "refresh_token"
+
+// setAuthCookies stores the tokens in cookies lasting as long as the tokens
+func setAuthCookies(c *fiber.Ctx, accessToken, refreshToken string, expiresIn, refreshExpiresIn int64) {
 	c.Cookie(&fiber.Cookie{
 		Name:     "token",
-		Value:    "",
-		Expires:  time.Now().Add(-time.Hour * 24),
+		Value:    accessToken,
+		Expires:  time.Now().Add(time.Duration(expiresIn) * time.Second),
 		HTTPOnly: true,   // not accessible via JS (important for security)
 		Secure:   true,   // send only over HTTPS
 		SameSite: "None", // "Lax" or "None" for cross-site
 	})
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"success": true,
-		"message": "Successfully logged out",
+	c.Cookie(&fiber.Cookie{
+		Name:     refreshCookie,
+		Value:    refreshToken,
+		Expires:  time.Now().Add(time.Duration(refreshExpiresIn) * time.Second),
+		HTTPOnly: true,
+		Secure:   true,
+		SameSite: "None",
 	})
 }
+
+func clearAuthCookies(c *fiber.Ctx) {
+	setAuthCookies(c, "", "", -24*60*60, -24*60*60)
+}
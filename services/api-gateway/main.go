@@ -1,16 +1,32 @@
 package main
 
 import (
+	"context"
 	"log"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/joho/godotenv"
 	"github.com/wutthichod/sa-connext/services/api-gateway/clients"
+	"github.com/wutthichod/sa-connext/services/api-gateway/graph"
 	"github.com/wutthichod/sa-connext/services/api-gateway/handlers"
+	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/httpcache"
+	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/middlewares"
+	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/openapi"
+	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/ratelimit"
+	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/revocations"
+	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/suspensions"
+	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/usercache"
+	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/versioning"
 	"github.com/wutthichod/sa-connext/shared/config"
+	"github.com/wutthichod/sa-connext/shared/correlation"
+	"github.com/wutthichod/sa-connext/shared/health"
+	"github.com/wutthichod/sa-connext/shared/lifecycle"
 	"github.com/wutthichod/sa-connext/shared/messaging"
+	"github.com/wutthichod/sa-connext/shared/metrics"
+	"github.com/wutthichod/sa-connext/shared/tracing"
 )
 
 func main() {
@@ -21,11 +37,29 @@ func main() {
 		log.Fatal(err)
 	}
 
-	app := fiber.New()
+	ctx := context.Background()
+	provider, err := tracing.Init(ctx, "api-gateway", config.Tracing())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// c.IP() only reads the proxy header on requests from a trusted proxy, so
+	// clients can't pick the IP they are rate limited by
+	app := fiber.New(fiber.Config{
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          config.API().TrustedProxies,
+		ProxyHeader:             config.API().ProxyHeader,
+		EnableIPValidation:      true,
+	})
+
+	// Accept or generate the request ID first, so every later log has it
+	app.Use(correlation.FiberMiddleware())
+	app.Use(tracing.FiberMiddleware())
+	app.Use(metrics.FiberMiddleware())
 
 	// Logger middleware - logs all requests
 	app.Use(logger.New(logger.Config{
-		Format:     "${cyan}[${time}] ${white}${pid} ${red}${status} ${blue}[${method}] ${white}${path}\n",
+		Format:     "${cyan}[${time}] ${white}${pid} ${red}${status} ${blue}[${method}] ${white}${path} ${white}request_id=${respHeader:X-Request-ID}\n",
 		TimeFormat: "02-Jan-2006",
 		TimeZone:   "UTC",
 	}))
@@ -37,10 +71,31 @@ func main() {
 		AllowCredentials: true,
 	}))
 
-	// Create gRPC Client
-	chatClient, _ := clients.NewChatServiceClient(config.App().Chat)
-	userClient, _ := clients.NewUserServiceClient(config.App().User)
-	eventClient := clients.NewEventServiceClient(config.App().Event)
+	// Registered ahead of the rate limiter so scrapes and probes are never throttled
+	app.Get("/metrics", metrics.FiberHandler())
+	checker := health.NewChecker()
+	health.RegisterRoutes(app, checker)
+
+	// Clients from before versioning call the unversioned paths; serve them
+	// from /v1, announcing the sunset, until they have moved over
+	app.Use(versioning.Alias("/v1", versioning.Deprecation{
+		Since:  config.API().LegacyDeprecated,
+		Sunset: config.API().LegacySunset,
+	}, "/users", "/chats", "/events"))
+
+	// Create gRPC clients; each downstream gets its own circuit breaker
+	chatClient, err := clients.NewChatServiceClient(config.App().Chat, config.Resilience())
+	if err != nil {
+		log.Fatalf("Failed to create chat client: %v", err)
+	}
+	userClient, err := clients.NewUserServiceClient(config.App().User, config.Resilience())
+	if err != nil {
+		log.Fatalf("Failed to create user client: %v", err)
+	}
+	eventClient, err := clients.NewEventServiceClient(config.App().Event, config.Resilience())
+	if err != nil {
+		log.Fatalf("Failed to create event client: %v", err)
+	}
 
 	// Initialize QueueConsumer
 	rabbit, err := messaging.NewRabbitMQ(config.RABBITMQ().URI) // your RabbitMQ client
@@ -48,23 +103,100 @@ func main() {
 		log.Fatal(err)
 	}
 
+	checker.Add("user-service", userClient.Check)
+	checker.Add("chat-service", chatClient.Check)
+	checker.Add("event-service", eventClient.Check)
+	checker.Add("rabbitmq", rabbit.Check)
+
+	// Usernames used to name chats; dropped early when user-service reports a change
+	userCache := usercache.New(userClient, time.Minute)
+	if err := userCache.ListenForUpdates(rabbit); err != nil {
+		log.Fatal(err)
+	}
+
+	// Tokens of suspended users are refused on every authenticated route, not
+	// just at login
+	suspended := suspensions.New(userClient)
+	if err := suspended.ListenForChanges(rabbit); err != nil {
+		log.Fatal(err)
+	}
+	app.Use(middlewares.Suspensions(suspended))
+
+	// Tokens revoked at logout stop working before they expire
+	revoked := revocations.New(userClient)
+	if err := revoked.ListenForChanges(rabbit); err != nil {
+		log.Fatal(err)
+	}
+	app.Use(middlewares.TokenRevocations(revoked))
+
+	// Token bucket limits per route group, kept in memory for now. Runs after
+	// the lists above so only tokens that will be accepted get a user bucket.
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), ratelimit.DefaultRules())
+	app.Use(middlewares.RateLimitMiddleware(config, limiter))
+
+	// Cached GET responses; dropped early when users or events change
+	responseCache := httpcache.New(httpcache.NewMemoryStore(10000))
+	if err := responseCache.ListenForInvalidations(rabbit); err != nil {
+		log.Fatal(err)
+	}
+
 	connMgr := messaging.NewConnectionManager()
 	queueName := "chat_gateway"
 	consumer := messaging.NewQueueConsumer(rabbit, connMgr, queueName)
 
 	// Initialize ChatHandler
-	chatHandler := handlers.NewChatHandler(chatClient, userClient, connMgr, consumer, &config)
-	userHandler := handlers.NewUserHandler(userClient, &config)
-	eventHandler := handlers.NewEventHandler(eventClient, &config)
+	chatHandler := handlers.NewChatHandler(chatClient, userCache, connMgr, consumer, &config)
+	userHandler := handlers.NewUserHandler(userClient, responseCache, &config)
+	eventHandler := handlers.NewEventHandler(eventClient, responseCache, &config)
+	graphqlHandler := handlers.NewGraphQLHandler(&graph.Resolver{
+		Users:       userClient,
+		Chats:       chatClient,
+		Events:      eventClient,
+		Connections: connMgr,
+	}, &config)
+	adminHandler := handlers.NewAdminHandler(userClient, eventClient, chatClient, &config)
 
-	// Register Routes
-	chatHandler.RegisterRoutes(app)
-	userHandler.RegisterRoutes(app)
-	eventHandler.RegisterRoutes(app)
+	// Register Routes. A breaking change mounts its handlers under /v2 next
+	// to these, and passes a Deprecation for /v1 once v2 is the default.
+	routes := versioning.Mount(app, "/v1", nil, chatHandler, userHandler, eventHandler, graphqlHandler, adminHandler)
+
+	// Built at startup so a route documented twice fails fast
+	spec, err := openapi.Build("Connext API", "1.0.0", routes)
+	if err != nil {
+		log.Fatal(err)
+	}
+	app.Get("/openapi.json", openapi.Handler(spec))
+	app.Get("/docs", openapi.DocsHandler("Connext API", "/openapi.json"))
 
 	go func() {
 		chatHandler.ListenRabbit()
 	}()
 
-	log.Fatal(app.Listen(config.App().Gateway))
+	lc := lifecycle.New(config.Shutdown().Timeout)
+	lc.Go("http", func() error { return app.Listen(config.App().Gateway) })
+	go suspended.KeepFresh(lc.Context(), time.Minute)
+	go revoked.KeepFresh(lc.Context(), time.Minute)
+	go userCache.KeepClean(lc.Context(), time.Minute)
+
+	// Stop accepting connections and drain HTTP requests first, so clients
+	// told to reconnect by the websocket close can't land on this instance
+	// again. Upgraded websockets are hijacked and don't hold up the drain.
+	lc.OnShutdown("http", app.ShutdownWithContext)
+	lc.OnShutdown("websockets", connMgr.CloseAll)
+	lc.OnShutdown("consumers", rabbit.StopConsumers)
+	lc.OnShutdown("grpc clients", func(ctx context.Context) error {
+		chatClient.Close()
+		userClient.Close()
+		eventClient.Close()
+		return nil
+	})
+	lc.OnShutdown("rabbitmq", func(ctx context.Context) error {
+		rabbit.Close()
+		return nil
+	})
+	lc.OnShutdown("tracing", provider.Shutdown)
+
+	if err := lc.Wait(); err != nil {
+		log.Fatal(err)
+	}
 }
@@ -0,0 +1,147 @@
+// Package dataloader batches and caches lookups made while resolving one
+// request, so a list of N items needing related records costs one backend
+// call instead of N.
+package dataloader
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// BatchFunc loads many keys at once. Keys missing from the result are
+// reported as not found.
+type BatchFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+// Loader collects the keys requested within Wait of each other and loads them
+// in one call. Results are cached for the life of the loader, which should be
+// one request.
+type Loader[K comparable, V any] struct {
+	batch    BatchFunc[K, V]
+	wait     time.Duration
+	maxBatch int
+
+	mu      sync.Mutex
+	cache   map[K]*result[V]
+	pending *pendingBatch[K, V]
+}
+
+type result[V any] struct {
+	done  chan struct{}
+	value V
+	found bool
+	err   error
+}
+
+type pendingBatch[K comparable, V any] struct {
+	keys    []K
+	results map[K]*result[V]
+	timer   *time.Timer
+}
+
+// New creates a loader. maxBatch of 0 means batches are only bounded by wait.
+func New[K comparable, V any](batch BatchFunc[K, V], wait time.Duration, maxBatch int) *Loader[K, V] {
+	return &Loader[K, V]{
+		batch:    batch,
+		wait:     wait,
+		maxBatch: maxBatch,
+		cache:    map[K]*result[V]{},
+	}
+}
+
+// Load returns the value for key. found is false when the batch function
+// did not return it.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (value V, found bool, err error) {
+	res := l.enqueue(ctx, key)
+	select {
+	case <-res.done:
+		return res.value, res.found, res.err
+	case <-ctx.Done():
+		return value, false, ctx.Err()
+	}
+}
+
+// LoadMany returns the values found for keys, keyed by key
+func (l *Loader[K, V]) LoadMany(ctx context.Context, keys []K) (map[K]V, error) {
+	results := make([]*result[V], len(keys))
+	for i, key := range keys {
+		results[i] = l.enqueue(ctx, key)
+	}
+
+	values := make(map[K]V, len(keys))
+	for i, res := range results {
+		select {
+		case <-res.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if res.err != nil {
+			return nil, res.err
+		}
+		if res.found {
+			values[keys[i]] = res.value
+		}
+	}
+	return values, nil
+}
+
+func (l *Loader[K, V]) enqueue(ctx context.Context, key K) *result[V] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if res, ok := l.cache[key]; ok {
+		return res
+	}
+	res := &result[V]{done: make(chan struct{})}
+	l.cache[key] = res
+
+	if l.pending == nil {
+		pending := &pendingBatch[K, V]{results: map[K]*result[V]{}}
+		// The batch runs detached from the first caller, whose context may
+		// end before the others are done with it
+		batchCtx := context.WithoutCancel(ctx)
+		pending.timer = time.AfterFunc(l.wait, func() { l.dispatch(batchCtx, pending) })
+		l.pending = pending
+	}
+	l.pending.keys = append(l.pending.keys, key)
+	l.pending.results[key] = res
+
+	if l.maxBatch > 0 && len(l.pending.keys) >= l.maxBatch {
+		pending := l.pending
+		l.pending = nil
+		if pending.timer.Stop() {
+			go l.dispatch(context.WithoutCancel(ctx), pending)
+		}
+	}
+	return res
+}
+
+func (l *Loader[K, V]) dispatch(ctx context.Context, pending *pendingBatch[K, V]) {
+	l.mu.Lock()
+	if l.pending == pending {
+		l.pending = nil
+	}
+	l.mu.Unlock()
+
+	values, err := l.batch(ctx, pending.keys)
+	for _, key := range pending.keys {
+		res := pending.results[key]
+		if err != nil {
+			res.err = err
+		} else {
+			res.value, res.found = values[key]
+		}
+		close(res.done)
+	}
+
+	// Failed lookups are retried by the next Load rather than cached
+	if err != nil {
+		l.mu.Lock()
+		for _, key := range pending.keys {
+			if l.cache[key] == pending.results[key] {
+				delete(l.cache, key)
+			}
+		}
+		l.mu.Unlock()
+	}
+}
@@ -0,0 +1,148 @@
+package dataloader
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder is a batch function that returns the key doubled, except for
+// keys it is told are missing, and records every batch it is called with
+type recorder struct {
+	mu      sync.Mutex
+	batches [][]int
+	missing map[int]bool
+	err     error
+}
+
+func (r *recorder) load(ctx context.Context, keys []int) (map[int]int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	batch := slices.Clone(keys)
+	slices.Sort(batch)
+	r.batches = append(r.batches, batch)
+	if r.err != nil {
+		return nil, r.err
+	}
+	values := map[int]int{}
+	for _, key := range keys {
+		if !r.missing[key] {
+			values[key] = key * 2
+		}
+	}
+	return values, nil
+}
+
+func (r *recorder) calls() [][]int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.batches)
+}
+
+// loadConcurrently loads every key from its own goroutine, as sibling
+// resolvers do
+func loadConcurrently(t *testing.T, loader *Loader[int, int], keys ...int) {
+	t.Helper()
+	var wg sync.WaitGroup
+	for _, key := range keys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, found, err := loader.Load(context.Background(), key)
+			if err != nil || !found || value != key*2 {
+				t.Errorf("Load(%d) = %d, %v, %v", key, value, found, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestLoaderBatchesConcurrentLoads(t *testing.T) {
+	r := &recorder{}
+	loader := New(r.load, 10*time.Millisecond, 0)
+
+	loadConcurrently(t, loader, 1, 2, 3, 2, 1)
+	if calls := r.calls(); len(calls) != 1 || !slices.Equal(calls[0], []int{1, 2, 3}) {
+		t.Errorf("batches = %v, want one batch of [1 2 3]", calls)
+	}
+
+	// Cached keys are not loaded again
+	loadConcurrently(t, loader, 1, 4)
+	if calls := r.calls(); len(calls) != 2 || !slices.Equal(calls[1], []int{4}) {
+		t.Errorf("batches = %v, want a second batch of [4]", calls)
+	}
+}
+
+func TestLoaderSplitsAtMaxBatch(t *testing.T) {
+	r := &recorder{}
+	loader := New(r.load, 10*time.Millisecond, 2)
+
+	loadConcurrently(t, loader, 1, 2, 3, 4, 5)
+	calls := r.calls()
+	var keys []int
+	for _, batch := range calls {
+		if len(batch) > 2 {
+			t.Errorf("batch %v is larger than the max of 2", batch)
+		}
+		keys = append(keys, batch...)
+	}
+	slices.Sort(keys)
+	if !slices.Equal(keys, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("loaded %v, want every key once", keys)
+	}
+}
+
+func TestLoadMany(t *testing.T) {
+	r := &recorder{missing: map[int]bool{2: true}}
+	loader := New(r.load, time.Millisecond, 0)
+
+	values, err := loader.LoadMany(context.Background(), []int{1, 2, 3})
+	if err != nil {
+		t.Fatalf("LoadMany() error = %v", err)
+	}
+	if len(values) != 2 || values[1] != 2 || values[3] != 6 {
+		t.Errorf("LoadMany() = %v, want 1 and 3 without the missing 2", values)
+	}
+	if _, found, _ := loader.Load(context.Background(), 2); found {
+		t.Error("missing key reported as found")
+	}
+	if calls := r.calls(); len(calls) != 1 {
+		t.Errorf("batches = %v, want the missing key cached too", calls)
+	}
+}
+
+func TestLoaderRetriesFailedKeys(t *testing.T) {
+	failure := errors.New("backend down")
+	r := &recorder{err: failure}
+	loader := New(r.load, time.Millisecond, 0)
+
+	if _, _, err := loader.Load(context.Background(), 1); !errors.Is(err, failure) {
+		t.Fatalf("Load() error = %v, want %v", err, failure)
+	}
+
+	r.mu.Lock()
+	r.err = nil
+	r.mu.Unlock()
+	if value, found, err := loader.Load(context.Background(), 1); err != nil || !found || value != 2 {
+		t.Errorf("Load() after recovery = %d, %v, %v", value, found, err)
+	}
+	if calls := r.calls(); len(calls) != 2 {
+		t.Errorf("batches = %v, want the failed key loaded again", calls)
+	}
+}
+
+func TestLoadReturnsWhenContextEnds(t *testing.T) {
+	loader := New(func(ctx context.Context, keys []int) (map[int]int, error) {
+		time.Sleep(50 * time.Millisecond)
+		return nil, nil
+	}, time.Millisecond, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := loader.Load(ctx, 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("Load() error = %v, want context.Canceled", err)
+	}
+}
@@ -16,23 +16,27 @@ func HandleGRPCError(c *fiber.Ctx, err error) error {
 		return nil
 	}
 
+	httpStatus, errorCode, message := FromGRPC(err)
+	return c.Status(httpStatus).JSON(contracts.Resp{
+		Success:    false,
+		StatusCode: httpStatus,
+		Message:    message,
+		Data: map[string]interface{}{
+			"error_code": errorCode,
+		},
+	})
+}
+
+// FromGRPC maps a gRPC error to the HTTP status, error code and message
+// clients see, for transports that do not answer with contracts.Resp
+func FromGRPC(err error) (httpStatus int, errorCode, message string) {
 	st, ok := status.FromError(err)
 	if !ok {
 		// Not a gRPC status error, return as internal error
-		return c.Status(fiber.StatusInternalServerError).JSON(contracts.Resp{
-			Success:    false,
-			StatusCode: fiber.StatusInternalServerError,
-			Message:    "Internal server error",
-			Data: map[string]interface{}{
-				"error_code": "INTERNAL_ERROR",
-			},
-		})
+		return fiber.StatusInternalServerError, "INTERNAL_ERROR", "Internal server error"
 	}
 
-	// Map gRPC status codes to HTTP status codes
-	var httpStatus int
-	var errorCode string
-	message := st.Message()
+	message = st.Message()
 
 	// Extract error code from message if present
 	// Format: "CODE: message" or just "message"
@@ -75,6 +79,11 @@ func HandleGRPCError(c *fiber.Ctx, err error) error {
 		if errorCode == "" {
 			errorCode = "INTERNAL_ERROR"
 		}
+	case codes.ResourceExhausted:
+		httpStatus = fiber.StatusTooManyRequests
+		if errorCode == "" {
+			errorCode = "TOO_MANY_REQUESTS"
+		}
 	case codes.Unavailable:
 		httpStatus = fiber.StatusServiceUnavailable
 		if errorCode == "" {
@@ -87,12 +96,5 @@ func HandleGRPCError(c *fiber.Ctx, err error) error {
 		}
 	}
 
-	return c.Status(httpStatus).JSON(contracts.Resp{
-		Success:    false,
-		StatusCode: httpStatus,
-		Message:    message,
-		Data: map[string]interface{}{
-			"error_code": errorCode,
-		},
-	})
+	return httpStatus, errorCode, message
 }
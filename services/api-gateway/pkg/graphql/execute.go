@@ -0,0 +1,625 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Request is the standard GraphQL-over-HTTP request body
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+// Response is the standard GraphQL response body. Data is nil when the
+// request could not be executed at all.
+type Response struct {
+	Data   any      `json:"data,omitempty"`
+	Errors []*Error `json:"errors,omitempty"`
+}
+
+// Error is a GraphQL error. Resolver errors that implement
+// interface{ Extensions() map[string]any } have their extensions copied here.
+type Error struct {
+	Message    string         `json:"message"`
+	Path       []any          `json:"path,omitempty"`
+	Extensions map[string]any `json:"extensions,omitempty"`
+}
+
+func (e *Error) Error() string { return e.Message }
+
+func requestError(format string, args ...any) *Response {
+	return &Response{Errors: []*Error{{
+		Message:    fmt.Sprintf(format, args...),
+		Extensions: map[string]any{"code": "GRAPHQL_VALIDATION_FAILED"},
+	}}}
+}
+
+// Execute runs a query or mutation. Subscriptions must use Subscribe.
+func (s *Schema) Execute(ctx context.Context, req Request) *Response {
+	e, resp := s.prepare(req)
+	if resp != nil {
+		return resp
+	}
+
+	var root *Object
+	switch e.operation.Type {
+	case "query":
+		root = s.Query
+	case "mutation":
+		root = s.Mutation
+	case "subscription":
+		return requestError("subscriptions must be sent over the websocket endpoint")
+	}
+	if root == nil {
+		return requestError("%s operations are not supported", e.operation.Type)
+	}
+
+	data := e.executeSelections(ctx, root, nil, e.operation.Selections, nil, e.operation.Type == "mutation")
+	if data == errNull {
+		data = nil
+	}
+	return &Response{Data: data, Errors: e.errors}
+}
+
+// Subscribe starts a subscription. Each event of the root field's stream is
+// delivered as one response; the channel is closed when the stream ends.
+// A non-nil *Response means the subscription could not be started.
+func (s *Schema) Subscribe(ctx context.Context, req Request) (<-chan *Response, *Response) {
+	e, resp := s.prepare(req)
+	if resp != nil {
+		return nil, resp
+	}
+	if e.operation.Type != "subscription" {
+		return nil, requestError("expected a subscription operation, got %s", e.operation.Type)
+	}
+	if s.Subscription == nil {
+		return nil, requestError("subscriptions are not supported")
+	}
+
+	fields := e.collectFields(s.Subscription, e.operation.Selections)
+	if len(fields) != 1 {
+		return nil, requestError("a subscription must select exactly one root field")
+	}
+	group := fields[0]
+	def := s.Subscription.Field(group.name)
+	if def == nil || def.Subscribe == nil {
+		return nil, requestError("%s is not a subscription field", group.name)
+	}
+	args, err := e.coerceArgs(def, group.fields[0])
+	if err != nil {
+		return nil, requestError("%v", err)
+	}
+	events, err := def.Subscribe(ctx, args)
+	if err != nil {
+		return nil, &Response{Errors: []*Error{toError(err, []any{group.key})}}
+	}
+
+	out := make(chan *Response)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				// Each event runs with fresh error state, like its own query
+				run := &executor{schema: s, doc: e.doc, operation: e.operation, variables: e.variables}
+				value := run.resolveField(ctx, s.Subscription, event, group, []any{group.key}, true)
+				data := newFieldMap(1)
+				if value == errNull {
+					value = nil
+				}
+				data.set(group.key, value)
+				select {
+				case out <- &Response{Data: data, Errors: run.errors}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+// executor holds the state of one operation
+type executor struct {
+	schema    *Schema
+	doc       *Document
+	operation *Operation
+	variables map[string]any
+
+	mu     sync.Mutex
+	errors []*Error
+}
+
+// prepare parses and validates req, returning an error response if either fails
+func (s *Schema) prepare(req Request) (*executor, *Response) {
+	doc, err := Parse(req.Query)
+	if err != nil {
+		return nil, requestError("%v", err)
+	}
+
+	var operation *Operation
+	for _, op := range doc.Operations {
+		if req.OperationName == "" || op.Name == req.OperationName {
+			if operation != nil {
+				return nil, requestError("operationName is required when the document has several operations")
+			}
+			operation = op
+		}
+	}
+	if operation == nil {
+		return nil, requestError("unknown operation %q", req.OperationName)
+	}
+
+	e := &executor{schema: s, doc: doc, operation: operation}
+	if e.variables, err = coerceVariables(operation, req.Variables); err != nil {
+		return nil, requestError("%v", err)
+	}
+
+	root := map[string]*Object{"query": s.Query, "mutation": s.Mutation, "subscription": s.Subscription}[operation.Type]
+	if root != nil {
+		if errs := e.validate(root, operation.Selections, 1, map[string]bool{}); len(errs) > 0 {
+			resp := &Response{}
+			for _, err := range errs {
+				resp.Errors = append(resp.Errors, requestError("%v", err).Errors...)
+			}
+			return nil, resp
+		}
+	}
+	return e, nil
+}
+
+func coerceVariables(op *Operation, values map[string]any) (map[string]any, error) {
+	variables := map[string]any{}
+	for _, def := range op.Variables {
+		value, ok := values[def.Name]
+		if !ok && def.Default != nil {
+			value, ok = def.Default, true
+		}
+		if (!ok || value == nil) && strings.HasSuffix(def.Type, "!") {
+			return nil, fmt.Errorf("variable $%s of type %s is required", def.Name, def.Type)
+		}
+		if ok {
+			variables[def.Name] = value
+		}
+	}
+	return variables, nil
+}
+
+// validate checks selections against the schema before anything runs
+func (e *executor) validate(object *Object, selections []Selection, depth int, visiting map[string]bool) []error {
+	if depth > e.schema.maxDepth() {
+		return []error{fmt.Errorf("query is nested deeper than %d levels", e.schema.maxDepth())}
+	}
+
+	var errs []error
+	for _, selection := range selections {
+		switch selection := selection.(type) {
+		case *Field:
+			if selection.Name == "__typename" {
+				continue
+			}
+			def := object.Field(selection.Name)
+			if def == nil {
+				errs = append(errs, fmt.Errorf("cannot query field %q on type %q", selection.Name, object.Name))
+				continue
+			}
+			for name := range selection.Arguments {
+				if !hasArg(def, name) {
+					errs = append(errs, fmt.Errorf("unknown argument %q on field %s.%s", name, object.Name, def.Name))
+				}
+			}
+			child, isObject := named(def.Type).(*Object)
+			switch {
+			case isObject && len(selection.Selections) == 0:
+				errs = append(errs, fmt.Errorf("field %s.%s of type %s must have a selection of subfields", object.Name, def.Name, def.Type))
+			case !isObject && len(selection.Selections) > 0:
+				errs = append(errs, fmt.Errorf("field %s.%s of type %s must not have a selection", object.Name, def.Name, def.Type))
+			case isObject:
+				errs = append(errs, e.validate(child, selection.Selections, depth+1, visiting)...)
+			}
+		case *FragmentSpread:
+			fragment, ok := e.doc.Fragments[selection.Name]
+			if !ok {
+				errs = append(errs, fmt.Errorf("unknown fragment %q", selection.Name))
+				continue
+			}
+			if visiting[fragment.Name] {
+				errs = append(errs, fmt.Errorf("fragment %q spreads itself", fragment.Name))
+				continue
+			}
+			visiting[fragment.Name] = true
+			errs = append(errs, e.validate(object, fragment.Selections, depth, visiting)...)
+			delete(visiting, fragment.Name)
+		case *InlineFragment:
+			errs = append(errs, e.validate(object, selection.Selections, depth, visiting)...)
+		}
+	}
+	return errs
+}
+
+func hasArg(def *FieldDef, name string) bool {
+	for _, arg := range def.Args {
+		if arg.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldGroup is every field in a selection set sharing one response key
+type fieldGroup struct {
+	key    string
+	name   string
+	fields []*Field
+}
+
+// collectFields flattens fragments and skipped fields into ordered groups
+func (e *executor) collectFields(object *Object, selections []Selection) []*fieldGroup {
+	var groups []*fieldGroup
+	index := map[string]*fieldGroup{}
+	var collect func(selections []Selection)
+	collect = func(selections []Selection) {
+		for _, selection := range selections {
+			if !e.included(selection.directives()) {
+				continue
+			}
+			switch selection := selection.(type) {
+			case *Field:
+				key := selection.ResponseKey()
+				if group, ok := index[key]; ok {
+					group.fields = append(group.fields, selection)
+					continue
+				}
+				group := &fieldGroup{key: key, name: selection.Name, fields: []*Field{selection}}
+				index[key] = group
+				groups = append(groups, group)
+			case *FragmentSpread:
+				fragment := e.doc.Fragments[selection.Name]
+				if fragment != nil && fragment.TypeCondition == object.Name {
+					collect(fragment.Selections)
+				}
+			case *InlineFragment:
+				if selection.TypeCondition == "" || selection.TypeCondition == object.Name {
+					collect(selection.Selections)
+				}
+			}
+		}
+	}
+	collect(selections)
+	return groups
+}
+
+// included applies @skip and @include
+func (e *executor) included(directives []*Directive) bool {
+	for _, directive := range directives {
+		if directive.Name != "skip" && directive.Name != "include" {
+			continue
+		}
+		value, _ := e.resolveValue(directive.Arguments["if"]).(bool)
+		if directive.Name == "skip" && value || directive.Name == "include" && !value {
+			return false
+		}
+	}
+	return true
+}
+
+// errNull stands for a null produced by an error that has already been
+// reported. It spreads to the nearest nullable parent.
+var errNull = &struct{ name string }{"null"}
+
+// executeSelections resolves a selection set on source. Fields run
+// concurrently, so dataloaders see sibling lookups together, except for
+// mutations, which run in order.
+func (e *executor) executeSelections(ctx context.Context, object *Object, source any, selections []Selection, path []any, serial bool) any {
+	groups := e.collectFields(object, selections)
+	values := make([]any, len(groups))
+
+	if serial {
+		for i, group := range groups {
+			values[i] = e.resolveField(ctx, object, source, group, appendPath(path, group.key), false)
+		}
+	} else {
+		var wg sync.WaitGroup
+		for i, group := range groups {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				values[i] = e.resolveField(ctx, object, source, group, appendPath(path, group.key), false)
+			}()
+		}
+		wg.Wait()
+	}
+
+	result := newFieldMap(len(groups))
+	for i, group := range groups {
+		value := values[i]
+		if value == errNull {
+			if group.name != "__typename" && isNonNull(object.Field(group.name).Type) {
+				return errNull
+			}
+			value = nil
+		}
+		result.set(group.key, value)
+	}
+	return result
+}
+
+// resolveField runs the field's resolver and completes its value. For
+// subscription events, source is the event.
+func (e *executor) resolveField(ctx context.Context, object *Object, source any, group *fieldGroup, path []any, event bool) any {
+	if group.name == "__typename" {
+		return object.Name
+	}
+	def := object.Field(group.name)
+	args, err := e.coerceArgs(def, group.fields[0])
+	if err != nil {
+		e.report(err, path)
+		return errNull
+	}
+
+	var value any
+	switch {
+	case def.Resolve != nil:
+		value, err = def.Resolve(ctx, source, args)
+	case event:
+		value = source
+	default:
+		value, err = defaultResolve(source, def.Name)
+	}
+	if err != nil {
+		e.report(err, path)
+		return errNull
+	}
+
+	var selections []Selection
+	for _, field := range group.fields {
+		selections = append(selections, field.Selections...)
+	}
+	return e.complete(ctx, def.Type, selections, value, path)
+}
+
+// complete turns a resolved Go value into its response form for t
+func (e *executor) complete(ctx context.Context, t Type, selections []Selection, value any, path []any) any {
+	if nonNull, ok := t.(*NonNull); ok {
+		completed := e.complete(ctx, nonNull.Of, selections, value, path)
+		if completed == nil {
+			e.report(fmt.Errorf("cannot return null for non-nullable field"), path)
+			return errNull
+		}
+		return completed
+	}
+
+	rv := reflect.ValueOf(value)
+	if value == nil || (rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Map || rv.Kind() == reflect.Interface) && rv.IsNil() {
+		return nil
+	}
+
+	switch t := t.(type) {
+	case *Scalar:
+		for rv.Kind() == reflect.Pointer {
+			rv = rv.Elem()
+		}
+		serialized, err := t.Serialize(rv.Interface())
+		if err != nil {
+			e.report(err, path)
+			return errNull
+		}
+		return serialized
+	case *Object:
+		return e.executeSelections(ctx, t, value, selections, path, false)
+	case *List:
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			e.report(fmt.Errorf("expected a list, got %T", value), path)
+			return errNull
+		}
+		items := make([]any, rv.Len())
+		var wg sync.WaitGroup
+		for i := range items {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				items[i] = e.complete(ctx, t.Of, selections, rv.Index(i).Interface(), appendPath(path, i))
+			}()
+		}
+		wg.Wait()
+		for i, item := range items {
+			if item == errNull {
+				if isNonNull(t.Of) {
+					return errNull
+				}
+				items[i] = nil
+			}
+		}
+		return items
+	}
+	e.report(fmt.Errorf("unsupported type %s", t), path)
+	return errNull
+}
+
+// defaultResolve reads name from a map or from a struct field whose json tag
+// or Go name matches it
+func defaultResolve(source any, name string) (any, error) {
+	rv := reflect.ValueOf(source)
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Map:
+		value := rv.MapIndex(reflect.ValueOf(name))
+		if !value.IsValid() {
+			return nil, nil
+		}
+		return value.Interface(), nil
+	case reflect.Struct:
+		t := rv.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			tag, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if tag == name || strings.EqualFold(field.Name, name) {
+				return rv.Field(i).Interface(), nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("no resolver for field %q on %T", name, source)
+}
+
+func (e *executor) coerceArgs(def *FieldDef, field *Field) (Args, error) {
+	args := Args{}
+	for _, arg := range def.Args {
+		value := arg.Default
+		if raw, ok := field.Arguments[arg.Name]; ok {
+			if name, isVar := raw.(Variable); !isVar || e.hasVariable(string(name)) {
+				value = e.resolveValue(raw)
+			}
+		}
+		if value == nil {
+			if isNonNull(arg.Type) {
+				return nil, fmt.Errorf("argument %q of type %s is required", arg.Name, arg.Type)
+			}
+			continue
+		}
+		coerced, err := coerceInput(arg.Type, value)
+		if err != nil {
+			return nil, fmt.Errorf("argument %q: %v", arg.Name, err)
+		}
+		args[arg.Name] = coerced
+	}
+	return args, nil
+}
+
+func (e *executor) hasVariable(name string) bool {
+	_, ok := e.variables[name]
+	return ok
+}
+
+// resolveValue substitutes variables in a literal
+func (e *executor) resolveValue(value Value) any {
+	switch value := value.(type) {
+	case Variable:
+		return e.variables[string(value)]
+	case EnumValue:
+		return string(value)
+	case []Value:
+		list := make([]any, len(value))
+		for i, item := range value {
+			list[i] = e.resolveValue(item)
+		}
+		return list
+	case map[string]Value:
+		object := make(map[string]any, len(value))
+		for key, item := range value {
+			object[key] = e.resolveValue(item)
+		}
+		return object
+	}
+	return value
+}
+
+func coerceInput(t Type, value any) (any, error) {
+	if nonNull, ok := t.(*NonNull); ok {
+		if value == nil {
+			return nil, fmt.Errorf("expected a non-null %s", nonNull.Of)
+		}
+		return coerceInput(nonNull.Of, value)
+	}
+	if value == nil {
+		return nil, nil
+	}
+	switch t := t.(type) {
+	case *Scalar:
+		return t.Coerce(value)
+	case *List:
+		items, ok := value.([]any)
+		if !ok {
+			// A single value is accepted where a list is expected
+			items = []any{value}
+		}
+		coerced := make([]any, len(items))
+		for i, item := range items {
+			var err error
+			if coerced[i], err = coerceInput(t.Of, item); err != nil {
+				return nil, err
+			}
+		}
+		return coerced, nil
+	}
+	return nil, fmt.Errorf("%s cannot be used as an input type", t)
+}
+
+func (e *executor) report(err error, path []any) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.errors = append(e.errors, toError(err, path))
+}
+
+func toError(err error, path []any) *Error {
+	gqlErr := &Error{Message: err.Error(), Path: path}
+	var extended interface{ Extensions() map[string]any }
+	if errors.As(err, &extended) {
+		gqlErr.Extensions = extended.Extensions()
+	}
+	return gqlErr
+}
+
+func appendPath(path []any, segment any) []any {
+	next := make([]any, len(path), len(path)+1)
+	copy(next, path)
+	return append(next, segment)
+}
+
+// fieldMap is a JSON object that keeps its keys in selection order, as the
+// spec requires
+type fieldMap struct {
+	keys   []string
+	values map[string]any
+}
+
+func newFieldMap(size int) *fieldMap {
+	return &fieldMap{keys: make([]string, 0, size), values: make(map[string]any, size)}
+}
+
+func (m *fieldMap) set(key string, value any) {
+	if _, exists := m.values[key]; !exists {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+func (m *fieldMap) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		name, _ := json.Marshal(key)
+		b.Write(name)
+		b.WriteByte(':')
+		value, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
@@ -0,0 +1,280 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testUser struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	FriendID string `json:"-"`
+}
+
+var testUsers = map[string]*testUser{
+	"1": {ID: "1", Name: "Ann", FriendID: "2"},
+	"2": {ID: "2", Name: "Bob", FriendID: "1"},
+}
+
+// extendedError carries a code the way resolver errors do
+type extendedError struct{ code string }
+
+func (e extendedError) Error() string              { return "failed with " + e.code }
+func (e extendedError) Extensions() map[string]any { return map[string]any{"code": e.code} }
+
+// newTestSchema returns a small schema of users. Mutations append to the
+// returned log so their order can be checked.
+func newTestSchema() (*Schema, *[]string) {
+	var mu sync.Mutex
+	var log []string
+
+	user := &Object{Name: "User"}
+	user.Fields = []*FieldDef{
+		{Name: "id", Type: NonNullOf(ID)},
+		{Name: "name", Type: NonNullOf(String)},
+		{Name: "friend", Type: user, Resolve: func(ctx context.Context, source any, args Args) (any, error) {
+			return testUsers[source.(*testUser).FriendID], nil
+		}},
+		{Name: "broken", Type: NonNullOf(String), Resolve: func(ctx context.Context, source any, args Args) (any, error) {
+			return nil, extendedError{code: "NOT_FOUND"}
+		}},
+	}
+
+	query := &Object{Name: "Query", Fields: []*FieldDef{
+		{Name: "user", Type: user, Args: []*Argument{{Name: "id", Type: NonNullOf(ID)}},
+			Resolve: func(ctx context.Context, source any, args Args) (any, error) {
+				return testUsers[args.String("id")], nil
+			}},
+		{Name: "users", Type: ListOfNonNull(user), Resolve: func(ctx context.Context, source any, args Args) (any, error) {
+			return []*testUser{testUsers["1"], testUsers["2"]}, nil
+		}},
+		{Name: "greet", Type: NonNullOf(String), Args: []*Argument{{Name: "name", Type: String, Default: "world"}},
+			Resolve: func(ctx context.Context, source any, args Args) (any, error) {
+				return "hello " + args.String("name"), nil
+			}},
+	}}
+
+	record := func(name string) Resolver {
+		return func(ctx context.Context, source any, args Args) (any, error) {
+			// The first mutation is the slowest, so running them concurrently
+			// would log them out of order
+			if name == "first" {
+				time.Sleep(10 * time.Millisecond)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			log = append(log, name)
+			return name, nil
+		}
+	}
+	mutation := &Object{Name: "Mutation", Fields: []*FieldDef{
+		{Name: "first", Type: String, Resolve: record("first")},
+		{Name: "second", Type: String, Resolve: record("second")},
+	}}
+
+	subscription := &Object{Name: "Subscription", Fields: []*FieldDef{
+		{Name: "counter", Type: NonNullOf(Int), Args: []*Argument{{Name: "to", Type: NonNullOf(Int)}},
+			Subscribe: func(ctx context.Context, args Args) (<-chan any, error) {
+				events := make(chan any)
+				go func() {
+					defer close(events)
+					for i := 1; i <= args.Int("to"); i++ {
+						select {
+						case events <- i:
+						case <-ctx.Done():
+							return
+						}
+					}
+				}()
+				return events, nil
+			}},
+	}}
+
+	return &Schema{Query: query, Mutation: mutation, Subscription: subscription, MaxDepth: 4}, &log
+}
+
+func execute(t *testing.T, schema *Schema, req Request) (string, []*Error) {
+	t.Helper()
+	resp := schema.Execute(context.Background(), req)
+	data, err := json.Marshal(resp.Data)
+	if err != nil {
+		t.Fatalf("failed to marshal data: %v", err)
+	}
+	return string(data), resp.Errors
+}
+
+func TestExecute(t *testing.T) {
+	schema, _ := newTestSchema()
+	tests := []struct {
+		name string
+		req  Request
+		want string
+	}{
+		{
+			name: "fields in selection order",
+			req:  Request{Query: `{ user(id: "1") { name id } }`},
+			want: `{"user":{"name":"Ann","id":"1"}}`,
+		},
+		{
+			name: "aliases and variables",
+			req: Request{
+				Query:     `query($id: ID!) { a: user(id: $id) { name } b: user(id: "2") { name } }`,
+				Variables: map[string]any{"id": "1"},
+			},
+			want: `{"a":{"name":"Ann"},"b":{"name":"Bob"}}`,
+		},
+		{
+			name: "argument defaults",
+			req:  Request{Query: `{ greet x: greet(name: "you") }`},
+			want: `{"greet":"hello world","x":"hello you"}`,
+		},
+		{
+			name: "fragments and typename",
+			req:  Request{Query: `{ users { ...U ... on User { friend { name } } } } fragment U on User { __typename id }`},
+			want: `{"users":[{"__typename":"User","id":"1","friend":{"name":"Bob"}},{"__typename":"User","id":"2","friend":{"name":"Ann"}}]}`,
+		},
+		{
+			name: "skip and include",
+			req: Request{
+				Query:     `query($yes: Boolean!) { user(id: "1") { id @skip(if: $yes) name @include(if: $yes) } }`,
+				Variables: map[string]any{"yes": true},
+			},
+			want: `{"user":{"name":"Ann"}}`,
+		},
+		{
+			name: "missing object is null",
+			req:  Request{Query: `{ user(id: "9") { id } }`},
+			want: `{"user":null}`,
+		},
+		{
+			name: "named operation",
+			req:  Request{Query: `query A { greet } query B { user(id: "2") { id } }`, OperationName: "B"},
+			want: `{"user":{"id":"2"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, errs := execute(t, schema, tt.req)
+			if len(errs) > 0 {
+				t.Fatalf("unexpected errors: %v", errs[0])
+			}
+			if got != tt.want {
+				t.Errorf("data = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecuteResolverErrorNullsNearestNullableParent(t *testing.T) {
+	schema, _ := newTestSchema()
+	got, errs := execute(t, schema, Request{Query: `{ greet user(id: "1") { id broken } }`})
+	if got != `{"greet":"hello world","user":null}` {
+		t.Errorf("data = %s", got)
+	}
+	if len(errs) != 1 {
+		t.Fatalf("got %d errors, want 1", len(errs))
+	}
+	if errs[0].Extensions["code"] != "NOT_FOUND" {
+		t.Errorf("extensions = %v, want code NOT_FOUND", errs[0].Extensions)
+	}
+	if path, _ := json.Marshal(errs[0].Path); string(path) != `["user","broken"]` {
+		t.Errorf("path = %s", path)
+	}
+}
+
+func TestExecuteRunsMutationsInOrder(t *testing.T) {
+	schema, log := newTestSchema()
+	if _, errs := execute(t, schema, Request{Query: `mutation { first second }`}); len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs[0])
+	}
+	if strings.Join(*log, ",") != "first,second" {
+		t.Errorf("mutations ran as %v, want first then second", *log)
+	}
+}
+
+func TestExecuteValidation(t *testing.T) {
+	schema, _ := newTestSchema()
+	tests := []struct {
+		name    string
+		req     Request
+		wantErr string
+	}{
+		{"syntax error", Request{Query: `{ user(`}, "syntax error"},
+		{"unknown field", Request{Query: `{ nope }`}, `cannot query field "nope"`},
+		{"unknown argument", Request{Query: `{ greet(x: 1) }`}, `unknown argument "x"`},
+		{"object without selection", Request{Query: `{ users }`}, "must have a selection"},
+		{"scalar with selection", Request{Query: `{ greet { a } }`}, "must not have a selection"},
+		{"unknown fragment", Request{Query: `{ users { ...F } }`}, `unknown fragment "F"`},
+		{"missing variable", Request{Query: `query($id: ID!) { user(id: $id) { id } }`}, "variable $id of type ID! is required"},
+		{"ambiguous operation", Request{Query: `query A { greet } query B { greet }`}, "operationName is required"},
+		{"unknown operation", Request{Query: `query A { greet }`, OperationName: "C"}, `unknown operation "C"`},
+		{"subscription over execute", Request{Query: `subscription { counter(to: 1) }`}, "websocket"},
+		{
+			name:    "fragment cycle",
+			req:     Request{Query: `{ users { ...A } } fragment A on User { friend { ...B } } fragment B on User { ...A }`},
+			wantErr: `fragment "A" spreads itself`,
+		},
+		{
+			name:    "depth limit",
+			req:     Request{Query: `{ user(id: "1") { friend { friend { friend { friend { id } } } } } }`},
+			wantErr: "nested deeper than 4 levels",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := schema.Execute(context.Background(), tt.req)
+			if resp.Data != nil {
+				t.Errorf("data = %v, want none", resp.Data)
+			}
+			if len(resp.Errors) == 0 || !strings.Contains(resp.Errors[0].Message, tt.wantErr) {
+				t.Fatalf("errors = %v, want one containing %q", resp.Errors, tt.wantErr)
+			}
+			if resp.Errors[0].Extensions["code"] != "GRAPHQL_VALIDATION_FAILED" {
+				t.Errorf("code = %v, want GRAPHQL_VALIDATION_FAILED", resp.Errors[0].Extensions["code"])
+			}
+		})
+	}
+}
+
+func TestExecuteAllowsQueriesUpToTheDepthLimit(t *testing.T) {
+	schema, _ := newTestSchema()
+	got, errs := execute(t, schema, Request{Query: `{ user(id: "1") { friend { friend { id } } } }`})
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs[0])
+	}
+	if got != `{"user":{"friend":{"friend":{"id":"1"}}}}` {
+		t.Errorf("data = %s", got)
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	schema, _ := newTestSchema()
+	stream, failed := schema.Subscribe(context.Background(), Request{Query: `subscription { n: counter(to: 3) }`})
+	if failed != nil {
+		t.Fatalf("Subscribe() failed: %v", failed.Errors[0])
+	}
+	var got []string
+	for resp := range stream {
+		data, _ := json.Marshal(resp.Data)
+		got = append(got, string(data))
+	}
+	if strings.Join(got, " ") != `{"n":1} {"n":2} {"n":3}` {
+		t.Errorf("events = %v", got)
+	}
+
+	if _, failed := schema.Subscribe(context.Background(), Request{Query: `{ greet }`}); failed == nil {
+		t.Error("Subscribe() accepted a query")
+	}
+}
+
+func TestToErrorCopiesExtensions(t *testing.T) {
+	err := toError(errors.Join(errors.New("context"), extendedError{code: "FORBIDDEN"}), []any{"a", 0})
+	if err.Extensions["code"] != "FORBIDDEN" {
+		t.Errorf("extensions = %v", err.Extensions)
+	}
+}
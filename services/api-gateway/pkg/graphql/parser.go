@@ -0,0 +1,704 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Document is a parsed GraphQL request document
+type Document struct {
+	Operations []*Operation
+	Fragments  map[string]*Fragment
+}
+
+// Operation is a query or subscription with its variables and selections
+type Operation struct {
+	Type       string // "query", "mutation" or "subscription"
+	Name       string
+	Variables  []*VariableDefinition
+	Selections []Selection
+}
+
+type VariableDefinition struct {
+	Name    string
+	Type    string // as written, e.g. "ID!" or "[String]"
+	Default Value
+}
+
+type Fragment struct {
+	Name          string
+	TypeCondition string
+	Selections    []Selection
+}
+
+// Selection is a *Field, *FragmentSpread or *InlineFragment
+type Selection interface {
+	directives() []*Directive
+}
+
+type Field struct {
+	Alias      string
+	Name       string
+	Arguments  map[string]Value
+	Directives []*Directive
+	Selections []Selection
+}
+
+// ResponseKey is the name the field's value is returned under
+func (f *Field) ResponseKey() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+	return f.Name
+}
+
+type FragmentSpread struct {
+	Name       string
+	Directives []*Directive
+}
+
+type InlineFragment struct {
+	TypeCondition string
+	Directives    []*Directive
+	Selections    []Selection
+}
+
+type Directive struct {
+	Name      string
+	Arguments map[string]Value
+}
+
+func (f *Field) directives() []*Directive          { return f.Directives }
+func (f *FragmentSpread) directives() []*Directive { return f.Directives }
+func (f *InlineFragment) directives() []*Directive { return f.Directives }
+
+// Value is a literal in the document. Variables are kept as Variable and
+// replaced when arguments are coerced.
+type Value any
+
+type Variable string
+
+type EnumValue string
+
+// Limits applied while parsing, before the schema's own depth validation.
+// Nesting is bounded here because the parser is recursive and an unchecked
+// document of "[[[[..." would exhaust the stack.
+const (
+	maxDocumentSize = 64 << 10
+	maxNesting      = 64
+)
+
+// Parse parses a request document. Type system definitions are not accepted.
+func Parse(source string) (*Document, error) {
+	if len(source) > maxDocumentSize {
+		return nil, fmt.Errorf("syntax error: document is larger than %d bytes", maxDocumentSize)
+	}
+	p := &parser{lexer: lexer{source: source}}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	doc := &Document{Fragments: map[string]*Fragment{}}
+	for p.token.kind != tokenEOF {
+		switch {
+		case p.token.kind == tokenPunct && p.token.value == "{":
+			selections, err := p.selectionSet()
+			if err != nil {
+				return nil, err
+			}
+			doc.Operations = append(doc.Operations, &Operation{Type: "query", Selections: selections})
+		case p.token.kind == tokenName && p.token.value == "fragment":
+			fragment, err := p.fragment()
+			if err != nil {
+				return nil, err
+			}
+			if _, exists := doc.Fragments[fragment.Name]; exists {
+				return nil, fmt.Errorf("fragment %q is defined more than once", fragment.Name)
+			}
+			doc.Fragments[fragment.Name] = fragment
+		case p.token.kind == tokenName:
+			operation, err := p.operation()
+			if err != nil {
+				return nil, err
+			}
+			doc.Operations = append(doc.Operations, operation)
+		default:
+			return nil, p.unexpected()
+		}
+	}
+	if len(doc.Operations) == 0 {
+		return nil, fmt.Errorf("document contains no operation")
+	}
+	return doc, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunct
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+type lexer struct {
+	source string
+	pos    int
+}
+
+func (l *lexer) next() (token, error) {
+	// Whitespace, commas and comments are insignificant
+	for l.pos < len(l.source) {
+		c := l.source[l.pos]
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',' {
+			l.pos++
+			continue
+		}
+		if c == '#' {
+			for l.pos < len(l.source) && l.source[l.pos] != '\n' && l.source[l.pos] != '\r' {
+				l.pos++
+			}
+			continue
+		}
+		break
+	}
+	if l.pos >= len(l.source) {
+		return token{kind: tokenEOF, pos: l.pos}, nil
+	}
+
+	start := l.pos
+	c := l.source[l.pos]
+	switch {
+	case strings.IndexByte("!$&()=:@[]{}|", c) >= 0:
+		l.pos++
+		return token{kind: tokenPunct, value: string(c), pos: start}, nil
+	case c == '.':
+		if strings.HasPrefix(l.source[l.pos:], "...") {
+			l.pos += 3
+			return token{kind: tokenPunct, value: "...", pos: start}, nil
+		}
+	case c == '_' || isLetter(c):
+		for l.pos < len(l.source) && (l.source[l.pos] == '_' || isLetter(l.source[l.pos]) || isDigit(l.source[l.pos])) {
+			l.pos++
+		}
+		return token{kind: tokenName, value: l.source[start:l.pos], pos: start}, nil
+	case c == '-' || isDigit(c):
+		return l.number()
+	case c == '"':
+		return l.string()
+	}
+	return token{}, fmt.Errorf("syntax error at %d: unexpected character %q", start, c)
+}
+
+func (l *lexer) number() (token, error) {
+	start := l.pos
+	kind := tokenInt
+	if l.source[l.pos] == '-' {
+		l.pos++
+	}
+	l.digits()
+	if l.pos < len(l.source) && l.source[l.pos] == '.' {
+		kind = tokenFloat
+		l.pos++
+		l.digits()
+	}
+	if l.pos < len(l.source) && (l.source[l.pos] == 'e' || l.source[l.pos] == 'E') {
+		kind = tokenFloat
+		l.pos++
+		if l.pos < len(l.source) && (l.source[l.pos] == '+' || l.source[l.pos] == '-') {
+			l.pos++
+		}
+		l.digits()
+	}
+	text := l.source[start:l.pos]
+	if text == "-" || strings.HasSuffix(text, ".") {
+		return token{}, fmt.Errorf("syntax error at %d: invalid number %q", start, text)
+	}
+	return token{kind: kind, value: text, pos: start}, nil
+}
+
+func (l *lexer) digits() {
+	for l.pos < len(l.source) && isDigit(l.source[l.pos]) {
+		l.pos++
+	}
+}
+
+func (l *lexer) string() (token, error) {
+	start := l.pos
+	if strings.HasPrefix(l.source[l.pos:], `"""`) {
+		end := strings.Index(l.source[l.pos+3:], `"""`)
+		if end < 0 {
+			return token{}, fmt.Errorf("syntax error at %d: unterminated block string", start)
+		}
+		value := l.source[l.pos+3 : l.pos+3+end]
+		l.pos += end + 6
+		return token{kind: tokenString, value: strings.TrimSpace(value), pos: start}, nil
+	}
+
+	var b strings.Builder
+	l.pos++
+	for l.pos < len(l.source) {
+		c := l.source[l.pos]
+		switch c {
+		case '"':
+			l.pos++
+			return token{kind: tokenString, value: b.String(), pos: start}, nil
+		case '\n', '\r':
+			return token{}, fmt.Errorf("syntax error at %d: unterminated string", start)
+		case '\\':
+			if l.pos+1 >= len(l.source) {
+				return token{}, fmt.Errorf("syntax error at %d: unterminated string", start)
+			}
+			escape := l.source[l.pos+1]
+			l.pos += 2
+			switch escape {
+			case '"', '\\', '/':
+				b.WriteByte(escape)
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case 'u':
+				if l.pos+4 > len(l.source) {
+					return token{}, fmt.Errorf("syntax error at %d: invalid unicode escape", l.pos)
+				}
+				code, err := strconv.ParseUint(l.source[l.pos:l.pos+4], 16, 32)
+				if err != nil {
+					return token{}, fmt.Errorf("syntax error at %d: invalid unicode escape", l.pos)
+				}
+				b.WriteRune(rune(code))
+				l.pos += 4
+			default:
+				return token{}, fmt.Errorf("syntax error at %d: invalid escape \\%c", l.pos-1, escape)
+			}
+		default:
+			r, size := utf8.DecodeRuneInString(l.source[l.pos:])
+			b.WriteRune(r)
+			l.pos += size
+		}
+	}
+	return token{}, fmt.Errorf("syntax error at %d: unterminated string", start)
+}
+
+func isLetter(c byte) bool { return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' }
+func isDigit(c byte) bool  { return c >= '0' && c <= '9' }
+
+type parser struct {
+	lexer lexer
+	token token
+	depth int
+}
+
+// nest is called on entering a selection set, list, object or list type and
+// fails once the document nests deeper than maxNesting. The returned func
+// restores the depth on the way out.
+func (p *parser) nest() (func(), error) {
+	if p.depth >= maxNesting {
+		return nil, fmt.Errorf("syntax error at %d: document is nested deeper than %d levels", p.token.pos, maxNesting)
+	}
+	p.depth++
+	return func() { p.depth-- }, nil
+}
+
+func (p *parser) advance() error {
+	t, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+	p.token = t
+	return nil
+}
+
+func (p *parser) unexpected() error {
+	if p.token.kind == tokenEOF {
+		return fmt.Errorf("syntax error: unexpected end of document")
+	}
+	return fmt.Errorf("syntax error at %d: unexpected %q", p.token.pos, p.token.value)
+}
+
+func (p *parser) peek(punct string) bool {
+	return p.token.kind == tokenPunct && p.token.value == punct
+}
+
+func (p *parser) expect(punct string) error {
+	if !p.peek(punct) {
+		return p.unexpected()
+	}
+	return p.advance()
+}
+
+// skip consumes punct if it is next and reports whether it was
+func (p *parser) skip(punct string) (bool, error) {
+	if !p.peek(punct) {
+		return false, nil
+	}
+	return true, p.advance()
+}
+
+func (p *parser) name() (string, error) {
+	if p.token.kind != tokenName {
+		return "", p.unexpected()
+	}
+	name := p.token.value
+	return name, p.advance()
+}
+
+func (p *parser) operation() (*Operation, error) {
+	opType, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	if opType != "query" && opType != "mutation" && opType != "subscription" {
+		return nil, fmt.Errorf("syntax error: unknown operation type %q", opType)
+	}
+	op := &Operation{Type: opType}
+	if p.token.kind == tokenName {
+		op.Name, _ = p.name()
+	}
+	if p.peek("(") {
+		if op.Variables, err = p.variableDefinitions(); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := p.directives(); err != nil {
+		return nil, err
+	}
+	if op.Selections, err = p.selectionSet(); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+func (p *parser) variableDefinitions() ([]*VariableDefinition, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	var defs []*VariableDefinition
+	for !p.peek(")") {
+		if err := p.expect("$"); err != nil {
+			return nil, err
+		}
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		typ, err := p.typeRef()
+		if err != nil {
+			return nil, err
+		}
+		def := &VariableDefinition{Name: name, Type: typ}
+		if ok, err := p.skip("="); err != nil {
+			return nil, err
+		} else if ok {
+			if def.Default, err = p.value(true); err != nil {
+				return nil, err
+			}
+		}
+		if _, err := p.directives(); err != nil {
+			return nil, err
+		}
+		defs = append(defs, def)
+	}
+	return defs, p.advance()
+}
+
+func (p *parser) typeRef() (string, error) {
+	leave, err := p.nest()
+	if err != nil {
+		return "", err
+	}
+	defer leave()
+
+	var typ string
+	if ok, err := p.skip("["); err != nil {
+		return "", err
+	} else if ok {
+		inner, err := p.typeRef()
+		if err != nil {
+			return "", err
+		}
+		if err := p.expect("]"); err != nil {
+			return "", err
+		}
+		typ = "[" + inner + "]"
+	} else {
+		name, err := p.name()
+		if err != nil {
+			return "", err
+		}
+		typ = name
+	}
+	if ok, err := p.skip("!"); err != nil {
+		return "", err
+	} else if ok {
+		typ += "!"
+	}
+	return typ, nil
+}
+
+func (p *parser) fragment() (*Fragment, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	if name == "on" {
+		return nil, fmt.Errorf("syntax error: fragment cannot be named \"on\"")
+	}
+	if on, err := p.name(); err != nil {
+		return nil, err
+	} else if on != "on" {
+		return nil, fmt.Errorf("syntax error: expected \"on\" after fragment %s", name)
+	}
+	typeCondition, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.directives(); err != nil {
+		return nil, err
+	}
+	selections, err := p.selectionSet()
+	if err != nil {
+		return nil, err
+	}
+	return &Fragment{Name: name, TypeCondition: typeCondition, Selections: selections}, nil
+}
+
+func (p *parser) selectionSet() ([]Selection, error) {
+	leave, err := p.nest()
+	if err != nil {
+		return nil, err
+	}
+	defer leave()
+
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	var selections []Selection
+	for !p.peek("}") {
+		selection, err := p.selection()
+		if err != nil {
+			return nil, err
+		}
+		selections = append(selections, selection)
+	}
+	if len(selections) == 0 {
+		return nil, fmt.Errorf("syntax error at %d: empty selection set", p.token.pos)
+	}
+	return selections, p.advance()
+}
+
+func (p *parser) selection() (Selection, error) {
+	if ok, err := p.skip("..."); err != nil {
+		return nil, err
+	} else if ok {
+		return p.fragmentSelection()
+	}
+
+	field := &Field{}
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	if ok, err := p.skip(":"); err != nil {
+		return nil, err
+	} else if ok {
+		field.Alias = name
+		if name, err = p.name(); err != nil {
+			return nil, err
+		}
+	}
+	field.Name = name
+	if p.peek("(") {
+		if field.Arguments, err = p.arguments(); err != nil {
+			return nil, err
+		}
+	}
+	if field.Directives, err = p.directives(); err != nil {
+		return nil, err
+	}
+	if p.peek("{") {
+		if field.Selections, err = p.selectionSet(); err != nil {
+			return nil, err
+		}
+	}
+	return field, nil
+}
+
+func (p *parser) fragmentSelection() (Selection, error) {
+	if p.token.kind == tokenName && p.token.value != "on" {
+		spread := &FragmentSpread{}
+		spread.Name, _ = p.name()
+		var err error
+		spread.Directives, err = p.directives()
+		return spread, err
+	}
+
+	inline := &InlineFragment{}
+	if p.token.kind == tokenName {
+		_ = p.advance()
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		inline.TypeCondition = name
+	}
+	var err error
+	if inline.Directives, err = p.directives(); err != nil {
+		return nil, err
+	}
+	if inline.Selections, err = p.selectionSet(); err != nil {
+		return nil, err
+	}
+	return inline, nil
+}
+
+func (p *parser) arguments() (map[string]Value, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	args := map[string]Value{}
+	for !p.peek(")") {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		if args[name], err = p.value(false); err != nil {
+			return nil, err
+		}
+	}
+	return args, p.advance()
+}
+
+func (p *parser) directives() ([]*Directive, error) {
+	var directives []*Directive
+	for p.peek("@") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		directive := &Directive{Name: name}
+		if p.peek("(") {
+			if directive.Arguments, err = p.arguments(); err != nil {
+				return nil, err
+			}
+		}
+		directives = append(directives, directive)
+	}
+	return directives, nil
+}
+
+// value parses a literal; variables are not allowed in constant positions
+func (p *parser) value(constant bool) (Value, error) {
+	t := p.token
+	switch t.kind {
+	case tokenInt:
+		n, err := strconv.ParseInt(t.value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("syntax error at %d: %v", t.pos, err)
+		}
+		return n, p.advance()
+	case tokenFloat:
+		f, err := strconv.ParseFloat(t.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("syntax error at %d: %v", t.pos, err)
+		}
+		return f, p.advance()
+	case tokenString:
+		return t.value, p.advance()
+	case tokenName:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		switch t.value {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+		return EnumValue(t.value), nil
+	case tokenPunct:
+		switch t.value {
+		case "$":
+			if constant {
+				return nil, fmt.Errorf("syntax error at %d: variable not allowed here", t.pos)
+			}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			name, err := p.name()
+			return Variable(name), err
+		case "[":
+			leave, err := p.nest()
+			if err != nil {
+				return nil, err
+			}
+			defer leave()
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			list := []Value{}
+			for !p.peek("]") {
+				item, err := p.value(constant)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, item)
+			}
+			return list, p.advance()
+		case "{":
+			leave, err := p.nest()
+			if err != nil {
+				return nil, err
+			}
+			defer leave()
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			object := map[string]Value{}
+			for !p.peek("}") {
+				name, err := p.name()
+				if err != nil {
+					return nil, err
+				}
+				if err := p.expect(":"); err != nil {
+					return nil, err
+				}
+				if object[name], err = p.value(constant); err != nil {
+					return nil, err
+				}
+			}
+			return object, p.advance()
+		}
+	}
+	return nil, p.unexpected()
+}
@@ -0,0 +1,141 @@
+package graphql
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseRejectsDeepNesting(t *testing.T) {
+	tests := map[string]string{
+		"list":          "{ f(a: " + strings.Repeat("[", 10000) + ") }",
+		"object":        "{ f(a: " + strings.Repeat("{a: ", 10000) + ") }",
+		"selection set": strings.Repeat("{ a ", 1000),
+		"variable type": "query($v: " + strings.Repeat("[", 10000) + ") { a }",
+	}
+	for name, query := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse(query)
+			if err == nil || !strings.Contains(err.Error(), "nested deeper than") {
+				t.Fatalf("Parse() error = %v, want nesting error", err)
+			}
+		})
+	}
+}
+
+func TestParseAllowsNestingUpToTheLimit(t *testing.T) {
+	query := "{ f(a: " + strings.Repeat("[", maxNesting-1) + "1" + strings.Repeat("]", maxNesting-1) + ") }"
+	if _, err := Parse(query); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+}
+
+func TestParseRejectsLargeDocuments(t *testing.T) {
+	query := "{ a }" + strings.Repeat(" ", maxDocumentSize)
+	_, err := Parse(query)
+	if err == nil || !strings.Contains(err.Error(), "larger than") {
+		t.Fatalf("Parse() error = %v, want size error", err)
+	}
+}
+
+func TestParse(t *testing.T) {
+	doc, err := Parse(`
+		# leading comment
+		query Chats($first: Int = 10, $ids: [ID!]!) @cached {
+			me: viewer { id }
+			chats(first: $first, filter: {ids: $ids, kind: GROUP, name: "a\"b"}) {
+				...ChatFields
+				... on Chat @include(if: true) { name }
+			}
+		}
+		fragment ChatFields on Chat { id, lastMessageAt }
+		subscription { messages }
+	`)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(doc.Operations) != 2 {
+		t.Fatalf("got %d operations, want 2", len(doc.Operations))
+	}
+
+	op := doc.Operations[0]
+	if op.Type != "query" || op.Name != "Chats" {
+		t.Errorf("operation = %s %s, want query Chats", op.Type, op.Name)
+	}
+	if len(op.Variables) != 2 || op.Variables[0].Type != "Int" || op.Variables[0].Default != int64(10) || op.Variables[1].Type != "[ID!]!" {
+		t.Errorf("unexpected variables %+v %+v", op.Variables[0], op.Variables[1])
+	}
+
+	me := op.Selections[0].(*Field)
+	if me.Alias != "me" || me.Name != "viewer" || me.ResponseKey() != "me" {
+		t.Errorf("aliased field = %+v", me)
+	}
+	chats := op.Selections[1].(*Field)
+	if chats.Arguments["first"] != Variable("first") {
+		t.Errorf("first = %#v, want variable", chats.Arguments["first"])
+	}
+	filter := chats.Arguments["filter"].(map[string]Value)
+	if filter["kind"] != EnumValue("GROUP") || filter["name"] != `a"b` {
+		t.Errorf("filter = %#v", filter)
+	}
+	if ids, ok := filter["ids"].(Variable); !ok || ids != "ids" {
+		t.Errorf("filter.ids = %#v", filter["ids"])
+	}
+	if spread, ok := chats.Selections[0].(*FragmentSpread); !ok || spread.Name != "ChatFields" {
+		t.Errorf("first chats selection = %#v, want spread of ChatFields", chats.Selections[0])
+	}
+	inline, ok := chats.Selections[1].(*InlineFragment)
+	if !ok || inline.TypeCondition != "Chat" || len(inline.Directives) != 1 || inline.Directives[0].Arguments["if"] != true {
+		t.Errorf("second chats selection = %#v, want inline fragment on Chat", chats.Selections[1])
+	}
+
+	fragment := doc.Fragments["ChatFields"]
+	if fragment == nil || fragment.TypeCondition != "Chat" || len(fragment.Selections) != 2 {
+		t.Errorf("fragment = %+v", fragment)
+	}
+	if doc.Operations[1].Type != "subscription" {
+		t.Errorf("second operation type = %s", doc.Operations[1].Type)
+	}
+}
+
+func TestParseShorthandQuery(t *testing.T) {
+	doc, err := Parse(`{ a(n: -1.5e3, s: """ block """, l: [1 2 3], b: false, z: null) }`)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	op := doc.Operations[0]
+	if op.Type != "query" || op.Name != "" {
+		t.Errorf("operation = %q %q, want anonymous query", op.Type, op.Name)
+	}
+	args := op.Selections[0].(*Field).Arguments
+	if args["n"] != -1500.0 || args["s"] != "block" || args["b"] != false || args["z"] != nil {
+		t.Errorf("arguments = %#v", args)
+	}
+	if list := args["l"].([]Value); len(list) != 3 || list[2] != int64(3) {
+		t.Errorf("l = %#v", args["l"])
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := map[string]string{
+		"empty document":        "",
+		"only a fragment":       "fragment F on T { a }",
+		"empty selection set":   "{ }",
+		"unclosed selection":    "{ a",
+		"unknown operation":     "update { a }",
+		"duplicate fragment":    "{ ...F } fragment F on T { a } fragment F on T { b }",
+		"fragment named on":     "{ a } fragment on on T { a }",
+		"variable in default":   "query($a: Int = $b) { a }",
+		"unterminated string":   `{ a(s: "abc) }`,
+		"invalid escape":        `{ a(s: "\q") }`,
+		"bad number":            "{ a(n: 1.) }",
+		"unexpected character":  "{ a % }",
+		"missing argument name": "{ a(: 1) }",
+	}
+	for name, query := range tests {
+		t.Run(name, func(t *testing.T) {
+			if doc, err := Parse(query); err == nil {
+				t.Fatalf("Parse() = %+v, want error", doc)
+			}
+		})
+	}
+}
@@ -0,0 +1,293 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Type is a *Scalar, *Object, *List or *NonNull
+type Type interface {
+	String() string
+}
+
+// Scalar is a leaf type. Serialize turns a resolved Go value into its JSON
+// form, and Coerce turns an argument or variable into the Go value resolvers get.
+type Scalar struct {
+	Name      string
+	Serialize func(value any) (any, error)
+	Coerce    func(value any) (any, error)
+}
+
+func (s *Scalar) String() string { return s.Name }
+
+// Object is an output type with fields
+type Object struct {
+	Name        string
+	Description string
+	Fields      []*FieldDef
+}
+
+func (o *Object) String() string { return o.Name }
+
+// Field returns the field called name, or nil
+func (o *Object) Field(name string) *FieldDef {
+	for _, field := range o.Fields {
+		if field.Name == name {
+			return field
+		}
+	}
+	return nil
+}
+
+type List struct{ Of Type }
+
+func (l *List) String() string { return "[" + l.Of.String() + "]" }
+
+type NonNull struct{ Of Type }
+
+func (n *NonNull) String() string { return n.Of.String() + "!" }
+
+func ListOf(t Type) *List       { return &List{Of: t} }
+func NonNullOf(t Type) *NonNull { return &NonNull{Of: t} }
+
+// ListOfNonNull is the common [T!]! shape: a list that is never null and
+// never holds nulls
+func ListOfNonNull(t Type) *NonNull { return NonNullOf(ListOf(NonNullOf(t))) }
+
+func isNonNull(t Type) bool {
+	_, ok := t.(*NonNull)
+	return ok
+}
+
+// named strips list and non-null wrappers
+func named(t Type) Type {
+	for {
+		switch wrapper := t.(type) {
+		case *NonNull:
+			t = wrapper.Of
+		case *List:
+			t = wrapper.Of
+		default:
+			return t
+		}
+	}
+}
+
+// Resolver produces a field's value from the value of its parent object
+type Resolver func(ctx context.Context, source any, args Args) (any, error)
+
+// Subscriber starts a stream of events for a subscription root field. The
+// stream ends when the channel is closed or ctx is cancelled.
+type Subscriber func(ctx context.Context, args Args) (<-chan any, error)
+
+type FieldDef struct {
+	Name        string
+	Description string
+	Type        Type
+	Args        []*Argument
+	// Resolve may be nil, in which case the value is read from the parent:
+	// a map key or a struct field with a matching json tag
+	Resolve Resolver
+	// Subscribe is set on subscription root fields; Resolve, if any, then
+	// maps each event to the field's value
+	Subscribe Subscriber
+}
+
+type Argument struct {
+	Name    string
+	Type    Type
+	Default any
+}
+
+// Args holds a field's coerced arguments
+type Args map[string]any
+
+// String returns a string or ID argument, or "" when it is absent
+func (a Args) String(name string) string {
+	s, _ := a[name].(string)
+	return s
+}
+
+// Int returns an Int argument, or 0 when it is absent
+func (a Args) Int(name string) int {
+	n, _ := a[name].(int)
+	return n
+}
+
+// Bool returns a Boolean argument, or false when it is absent
+func (a Args) Bool(name string) bool {
+	b, _ := a[name].(bool)
+	return b
+}
+
+// Schema is the set of root types a request executes against
+type Schema struct {
+	Query        *Object
+	Mutation     *Object
+	Subscription *Object
+	// MaxDepth bounds how deeply selections may nest; 0 means 10
+	MaxDepth int
+}
+
+func (s *Schema) maxDepth() int {
+	if s.MaxDepth > 0 {
+		return s.MaxDepth
+	}
+	return 10
+}
+
+// String prints the schema in the GraphQL schema definition language
+func (s *Schema) String() string {
+	var objects []*Object
+	seen := map[*Object]bool{}
+	var visit func(o *Object)
+	visit = func(o *Object) {
+		if o == nil || seen[o] {
+			return
+		}
+		seen[o] = true
+		objects = append(objects, o)
+		for _, field := range o.Fields {
+			if child, ok := named(field.Type).(*Object); ok {
+				visit(child)
+			}
+		}
+	}
+	visit(s.Query)
+	visit(s.Mutation)
+	visit(s.Subscription)
+	sort.SliceStable(objects[1:], func(i, j int) bool { return objects[i+1].Name < objects[j+1].Name })
+
+	var b strings.Builder
+	for i, o := range objects {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		writeDescription(&b, "", o.Description)
+		fmt.Fprintf(&b, "type %s {\n", o.Name)
+		for _, field := range o.Fields {
+			writeDescription(&b, "  ", field.Description)
+			fmt.Fprintf(&b, "  %s", field.Name)
+			if len(field.Args) > 0 {
+				args := make([]string, 0, len(field.Args))
+				for _, arg := range field.Args {
+					args = append(args, arg.Name+": "+arg.Type.String())
+				}
+				fmt.Fprintf(&b, "(%s)", strings.Join(args, ", "))
+			}
+			fmt.Fprintf(&b, ": %s\n", field.Type)
+		}
+		b.WriteString("}\n")
+	}
+	return b.String()
+}
+
+func writeDescription(b *strings.Builder, indent, description string) {
+	if description != "" {
+		fmt.Fprintf(b, "%s%s\n", indent, strconv.Quote(description))
+	}
+}
+
+// Built-in scalars
+var (
+	String = &Scalar{
+		Name:      "String",
+		Serialize: func(v any) (any, error) { return fmt.Sprint(v), nil },
+		Coerce: func(v any) (any, error) {
+			s, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected a string, got %v", v)
+			}
+			return s, nil
+		},
+	}
+	ID = &Scalar{
+		Name:      "ID",
+		Serialize: func(v any) (any, error) { return fmt.Sprint(v), nil },
+		Coerce: func(v any) (any, error) {
+			switch v := v.(type) {
+			case string:
+				return v, nil
+			case int64, int:
+				return fmt.Sprint(v), nil
+			case float64:
+				if v == math.Trunc(v) {
+					return strconv.FormatInt(int64(v), 10), nil
+				}
+			}
+			return nil, fmt.Errorf("expected an ID, got %v", v)
+		},
+	}
+	Int = &Scalar{
+		Name: "Int",
+		Serialize: func(v any) (any, error) {
+			rv := reflect.ValueOf(v)
+			switch rv.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				return rv.Int(), nil
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				return int64(rv.Uint()), nil
+			}
+			return nil, fmt.Errorf("cannot serialize %T as Int", v)
+		},
+		Coerce: func(v any) (any, error) {
+			switch v := v.(type) {
+			case int64:
+				if v >= math.MinInt32 && v <= math.MaxInt32 {
+					return int(v), nil
+				}
+			case float64:
+				if v == math.Trunc(v) && v >= math.MinInt32 && v <= math.MaxInt32 {
+					return int(v), nil
+				}
+			}
+			return nil, fmt.Errorf("expected a 32-bit integer, got %v", v)
+		},
+	}
+	Float = &Scalar{
+		Name: "Float",
+		Serialize: func(v any) (any, error) {
+			rv := reflect.ValueOf(v)
+			switch {
+			case rv.CanFloat():
+				return rv.Float(), nil
+			case rv.CanInt():
+				return float64(rv.Int()), nil
+			case rv.CanUint():
+				return float64(rv.Uint()), nil
+			}
+			return nil, fmt.Errorf("cannot serialize %T as Float", v)
+		},
+		Coerce: func(v any) (any, error) {
+			switch v := v.(type) {
+			case float64:
+				return v, nil
+			case int64:
+				return float64(v), nil
+			}
+			return nil, fmt.Errorf("expected a number, got %v", v)
+		},
+	}
+	Boolean = &Scalar{
+		Name: "Boolean",
+		Serialize: func(v any) (any, error) {
+			b, ok := v.(bool)
+			if !ok {
+				return nil, fmt.Errorf("cannot serialize %T as Boolean", v)
+			}
+			return b, nil
+		},
+		Coerce: func(v any) (any, error) {
+			b, ok := v.(bool)
+			if !ok {
+				return nil, fmt.Errorf("expected a boolean, got %v", v)
+			}
+			return b, nil
+		},
+	}
+)
@@ -0,0 +1,251 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
+)
+
+// Handler serves queries over HTTP: POST with a JSON body, or GET with
+// query, operationName and variables in the query string. withContext builds
+// the context the request executes in, e.g. to attach the caller's identity
+// and per-request dataloaders.
+func Handler(schema *Schema, withContext func(c *fiber.Ctx) context.Context) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var req Request
+		if c.Method() == fiber.MethodGet {
+			req.Query = c.Query("query")
+			req.OperationName = c.Query("operationName")
+			if variables := c.Query("variables"); variables != "" {
+				if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+					return c.Status(fiber.StatusBadRequest).JSON(requestError("variables must be a JSON object"))
+				}
+			}
+		} else if err := json.Unmarshal(c.Body(), &req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(requestError("invalid json format"))
+		}
+		if req.Query == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(requestError("query is required"))
+		}
+
+		return c.JSON(schema.Execute(withContext(c), req))
+	}
+}
+
+// Messages of the graphql-transport-ws protocol used by the graphql-ws client
+const (
+	msgConnectionInit = "connection_init"
+	msgConnectionAck  = "connection_ack"
+	msgPing           = "ping"
+	msgPong           = "pong"
+	msgSubscribe      = "subscribe"
+	msgNext           = "next"
+	msgError          = "error"
+	msgComplete       = "complete"
+
+	// Close codes defined by the protocol
+	closeBadRequest     = 4400
+	closeInitTimeout    = 4408
+	closeDuplicateID    = 4409
+	closeUnauthorized   = 4401
+	closeTooManyInits   = 4429
+	connectionInitLimit = 10 * time.Second
+)
+
+// Subprotocol is the websocket subprotocol clients must request
+const Subprotocol = "graphql-transport-ws"
+
+type wsMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// upgradeContextLocal is the local the upgrade request's context is kept in
+// for the connection, as websocket.Conn has no context of its own
+const upgradeContextLocal = "graphql.upgradeContext"
+
+// WebSocketHandler upgrades the request and serves subscriptions, and
+// queries, over the graphql-transport-ws protocol. Operations run in the
+// upgrade request's context, so they carry its request ID and trace, and are
+// cancelled when the connection closes. withContext is called for each
+// operation, like Handler's is for each request.
+func WebSocketHandler(schema *Schema, withContext func(ctx context.Context, conn *websocket.Conn) context.Context) fiber.Handler {
+	upgrade := websocket.New(func(conn *websocket.Conn) {
+		base, ok := conn.Locals(upgradeContextLocal).(context.Context)
+		if !ok {
+			base = context.Background()
+		}
+		ctx, cancel := context.WithCancel(base)
+		defer cancel()
+		session := &wsSession{
+			schema:        schema,
+			conn:          conn,
+			withContext:   withContext,
+			subscriptions: map[string]context.CancelFunc{},
+		}
+		session.serve(ctx)
+	}, websocket.Config{Subprotocols: []string{Subprotocol}})
+
+	return func(c *fiber.Ctx) error {
+		c.Locals(upgradeContextLocal, c.UserContext())
+		return upgrade(c)
+	}
+}
+
+type wsSession struct {
+	schema      *Schema
+	conn        *websocket.Conn
+	withContext func(ctx context.Context, conn *websocket.Conn) context.Context
+
+	writeMu       sync.Mutex
+	mu            sync.Mutex
+	subscriptions map[string]context.CancelFunc
+	acknowledged  bool
+}
+
+func (s *wsSession) serve(ctx context.Context) {
+	initTimer := time.AfterFunc(connectionInitLimit, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if !s.acknowledged {
+			s.close(closeInitTimeout, "Connection initialisation timeout")
+		}
+	})
+	defer initTimer.Stop()
+
+	for {
+		var msg wsMessage
+		if err := s.conn.ReadJSON(&msg); err != nil {
+			return
+		}
+
+		switch msg.Type {
+		case msgConnectionInit:
+			s.mu.Lock()
+			if s.acknowledged {
+				s.mu.Unlock()
+				s.close(closeTooManyInits, "Too many initialisation requests")
+				return
+			}
+			s.acknowledged = true
+			s.mu.Unlock()
+			s.write(wsMessage{Type: msgConnectionAck})
+		case msgPing:
+			s.write(wsMessage{Type: msgPong})
+		case msgPong:
+		case msgSubscribe:
+			if !s.isAcknowledged() {
+				s.close(closeUnauthorized, "Unauthorized")
+				return
+			}
+			var req Request
+			if msg.ID == "" || json.Unmarshal(msg.Payload, &req) != nil {
+				s.close(closeBadRequest, "Invalid subscribe message")
+				return
+			}
+			if !s.start(ctx, msg.ID, req) {
+				s.close(closeDuplicateID, "Subscriber for "+msg.ID+" already exists")
+				return
+			}
+		case msgComplete:
+			s.stop(msg.ID)
+		default:
+			s.close(closeBadRequest, "Invalid message type")
+			return
+		}
+	}
+}
+
+// start runs one operation; subscriptions keep streaming until completed
+func (s *wsSession) start(ctx context.Context, id string, req Request) bool {
+	s.mu.Lock()
+	if _, exists := s.subscriptions[id]; exists {
+		s.mu.Unlock()
+		return false
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	s.subscriptions[id] = cancel
+	s.mu.Unlock()
+	ctx = s.withContext(ctx, s.conn)
+
+	go func() {
+		defer s.stop(id)
+
+		doc, err := Parse(req.Query)
+		if err == nil && isSubscription(doc, req.OperationName) {
+			stream, failed := s.schema.Subscribe(ctx, req)
+			if failed != nil {
+				s.writeErrors(id, failed.Errors)
+				return
+			}
+			for resp := range stream {
+				s.writePayload(id, msgNext, resp)
+			}
+		} else {
+			resp := s.schema.Execute(ctx, req)
+			if resp.Data == nil && len(resp.Errors) > 0 {
+				s.writeErrors(id, resp.Errors)
+				return
+			}
+			s.writePayload(id, msgNext, resp)
+		}
+		if ctx.Err() == nil {
+			s.write(wsMessage{ID: id, Type: msgComplete})
+		}
+	}()
+	return true
+}
+
+func isSubscription(doc *Document, operationName string) bool {
+	for _, op := range doc.Operations {
+		if operationName == "" || op.Name == operationName {
+			return op.Type == "subscription"
+		}
+	}
+	return false
+}
+
+func (s *wsSession) stop(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cancel, ok := s.subscriptions[id]; ok {
+		cancel()
+		delete(s.subscriptions, id)
+	}
+}
+
+func (s *wsSession) isAcknowledged() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.acknowledged
+}
+
+func (s *wsSession) writeErrors(id string, errs []*Error) {
+	s.writePayload(id, msgError, errs)
+}
+
+func (s *wsSession) writePayload(id, msgType string, payload any) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+	s.write(wsMessage{ID: id, Type: msgType, Payload: raw})
+}
+
+func (s *wsSession) write(msg wsMessage) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	_ = s.conn.WriteJSON(msg)
+}
+
+func (s *wsSession) close(code int, reason string) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	_ = s.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(time.Second))
+	_ = s.conn.Close()
+}
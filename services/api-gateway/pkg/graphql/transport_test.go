@@ -0,0 +1,219 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	fastws "github.com/fasthttp/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
+)
+
+// serveGraphQL serves the test schema over HTTP and graphql-transport-ws. It
+// returns the server's address and how many websocket operations were given
+// a context.
+func serveGraphQL(t *testing.T) (string, *atomic.Int32) {
+	t.Helper()
+	schema, _ := newTestSchema()
+	app := fiber.New()
+	app.Post("/graphql", Handler(schema, func(c *fiber.Ctx) context.Context { return c.UserContext() }))
+	operations := &atomic.Int32{}
+	app.Get("/graphql/ws", WebSocketHandler(schema, func(ctx context.Context, conn *websocket.Conn) context.Context {
+		operations.Add(1)
+		return ctx
+	}))
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	go app.Listener(ln)
+	t.Cleanup(func() { app.Shutdown() })
+	return ln.Addr().String(), operations
+}
+
+func dialGraphQL(t *testing.T, addr string) *fastws.Conn {
+	t.Helper()
+	dialer := fastws.Dialer{Subprotocols: []string{Subprotocol}, HandshakeTimeout: time.Second}
+	conn, res, err := dialer.Dial("ws://"+addr+"/graphql/ws", nil)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	if got := res.Header.Get("Sec-WebSocket-Protocol"); got != Subprotocol {
+		t.Errorf("subprotocol = %q, want %q", got, Subprotocol)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func send(t *testing.T, conn *fastws.Conn, msg wsMessage) {
+	t.Helper()
+	if err := conn.WriteJSON(msg); err != nil {
+		t.Fatalf("failed to write %s: %v", msg.Type, err)
+	}
+}
+
+func receive(t *testing.T, conn *fastws.Conn) wsMessage {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var msg wsMessage
+	if err := conn.ReadJSON(&msg); err != nil {
+		t.Fatalf("failed to read: %v", err)
+	}
+	return msg
+}
+
+// closeCode reads until the server closes the connection and returns the code
+func closeCode(t *testing.T, conn *fastws.Conn) int {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		_, _, err := conn.ReadMessage()
+		var closeErr *fastws.CloseError
+		if errors.As(err, &closeErr) {
+			return closeErr.Code
+		}
+		if err != nil {
+			t.Fatalf("connection ended without a close frame: %v", err)
+		}
+	}
+}
+
+func subscribe(t *testing.T, conn *fastws.Conn, id, query string) {
+	t.Helper()
+	payload, _ := json.Marshal(Request{Query: query})
+	send(t, conn, wsMessage{ID: id, Type: msgSubscribe, Payload: payload})
+}
+
+func initConnection(t *testing.T, conn *fastws.Conn) {
+	t.Helper()
+	send(t, conn, wsMessage{Type: msgConnectionInit})
+	if msg := receive(t, conn); msg.Type != msgConnectionAck {
+		t.Fatalf("got %s, want %s", msg.Type, msgConnectionAck)
+	}
+}
+
+func TestWebSocketQueryAndSubscription(t *testing.T) {
+	addr, operations := serveGraphQL(t)
+	conn := dialGraphQL(t, addr)
+	initConnection(t, conn)
+
+	send(t, conn, wsMessage{Type: msgPing})
+	if msg := receive(t, conn); msg.Type != msgPong {
+		t.Fatalf("got %s, want %s", msg.Type, msgPong)
+	}
+
+	subscribe(t, conn, "q", `{ greet }`)
+	if msg := receive(t, conn); msg.ID != "q" || msg.Type != msgNext || string(msg.Payload) != `{"data":{"greet":"hello world"}}` {
+		t.Errorf("got %s %s %s, want the query result", msg.ID, msg.Type, msg.Payload)
+	}
+	if msg := receive(t, conn); msg.ID != "q" || msg.Type != msgComplete {
+		t.Errorf("got %s %s, want complete", msg.ID, msg.Type)
+	}
+
+	subscribe(t, conn, "s", `subscription { counter(to: 2) }`)
+	var events []string
+	for {
+		msg := receive(t, conn)
+		if msg.Type == msgComplete {
+			break
+		}
+		if msg.Type != msgNext {
+			t.Fatalf("got %s %s, want next", msg.Type, msg.Payload)
+		}
+		events = append(events, string(msg.Payload))
+	}
+	if strings.Join(events, " ") != `{"data":{"counter":1}} {"data":{"counter":2}}` {
+		t.Errorf("events = %v", events)
+	}
+
+	subscribe(t, conn, "e", `{ nope }`)
+	if msg := receive(t, conn); msg.ID != "e" || msg.Type != msgError {
+		t.Errorf("got %s %s, want error", msg.ID, msg.Type)
+	}
+	if got := operations.Load(); got != 3 {
+		t.Errorf("context built for %d operations, want one per operation", got)
+	}
+}
+
+func TestWebSocketProtocolErrors(t *testing.T) {
+	addr, _ := serveGraphQL(t)
+	tests := []struct {
+		name     string
+		messages func(t *testing.T, conn *fastws.Conn)
+		want     int
+	}{
+		{
+			name: "subscribe before init",
+			messages: func(t *testing.T, conn *fastws.Conn) {
+				subscribe(t, conn, "1", `{ greet }`)
+			},
+			want: closeUnauthorized,
+		},
+		{
+			name: "second init",
+			messages: func(t *testing.T, conn *fastws.Conn) {
+				initConnection(t, conn)
+				send(t, conn, wsMessage{Type: msgConnectionInit})
+			},
+			want: closeTooManyInits,
+		},
+		{
+			name: "duplicate id",
+			messages: func(t *testing.T, conn *fastws.Conn) {
+				initConnection(t, conn)
+				subscribe(t, conn, "1", `subscription { counter(to: 1000000) }`)
+				subscribe(t, conn, "1", `{ greet }`)
+			},
+			want: closeDuplicateID,
+		},
+		{
+			name: "unknown message type",
+			messages: func(t *testing.T, conn *fastws.Conn) {
+				initConnection(t, conn)
+				send(t, conn, wsMessage{Type: "start"})
+			},
+			want: closeBadRequest,
+		},
+		{
+			name: "subscribe without id",
+			messages: func(t *testing.T, conn *fastws.Conn) {
+				initConnection(t, conn)
+				subscribe(t, conn, "", `{ greet }`)
+			},
+			want: closeBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := dialGraphQL(t, addr)
+			tt.messages(t, conn)
+			if got := closeCode(t, conn); got != tt.want {
+				t.Errorf("close code = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	addr, _ := serveGraphQL(t)
+	agent := fiber.Post("http://" + addr + "/graphql").JSON(Request{Query: `{ greet(name: "http") }`})
+	status, body, errs := agent.String()
+	if len(errs) > 0 {
+		t.Fatalf("request failed: %v", errs[0])
+	}
+	if status != fiber.StatusOK || body != `{"data":{"greet":"hello http"}}` {
+		t.Errorf("got %d %s", status, body)
+	}
+
+	status, body, _ = fiber.Post("http://" + addr + "/graphql").Body([]byte(`{}`)).String()
+	if status != fiber.StatusBadRequest || !strings.Contains(body, "query is required") {
+		t.Errorf("empty query: got %d %s", status, body)
+	}
+}
@@ -0,0 +1,119 @@
+// Package httpcache caches successful GET responses in the gateway. Entries
+// live for their route's TTL, carry an ETag for conditional requests, and
+// are tagged with the records they show so domain events can drop them early.
+package httpcache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Tags name the records a response was built from
+const (
+	// EventListTag covers every list of events
+	EventListTag = "events"
+	// AttendeesTag covers every list of an event's users
+	AttendeesTag = "attendees"
+)
+
+// EventTag covers responses showing the event with the given ID
+func EventTag(id string) string { return "event:" + id }
+
+// UserTag covers responses showing the user with the given ID
+func UserTag(id string) string { return "user:" + id }
+
+// Entry is a cached response
+type Entry struct {
+	Status      int
+	ContentType string
+	Body        []byte
+	ETag        string
+	Tags        []string
+	ExpiresAt   time.Time
+}
+
+// Store keeps the entries. MemoryStore works for a single gateway instance;
+// a shared implementation (e.g. Redis) can be plugged in for several.
+type Store interface {
+	Get(ctx context.Context, key string) (*Entry, bool, error)
+	Set(ctx context.Context, key string, entry *Entry) error
+	// Invalidate drops every entry carrying one of tags
+	Invalidate(ctx context.Context, tags ...string) error
+}
+
+// Cache guards a store against storing a response that was read from the
+// backend before an invalidation and arrived after it
+type Cache struct {
+	store      Store
+	generation atomic.Uint64
+}
+
+func New(store Store) *Cache {
+	return &Cache{store: store}
+}
+
+// Generation is taken before reading from the backend and passed to Set
+func (c *Cache) Generation() uint64 {
+	return c.generation.Load()
+}
+
+func (c *Cache) Get(ctx context.Context, key string) (*Entry, bool, error) {
+	return c.store.Get(ctx, key)
+}
+
+// Set stores entry unless something was invalidated since generation
+func (c *Cache) Set(ctx context.Context, key string, entry *Entry, generation uint64) error {
+	if c.generation.Load() != generation {
+		return nil
+	}
+	return c.store.Set(ctx, key, entry)
+}
+
+func (c *Cache) Invalidate(ctx context.Context, tags ...string) error {
+	c.generation.Add(1)
+	return c.store.Invalidate(ctx, tags...)
+}
+
+// ETag is a strong validator for body
+func ETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// Matches reports whether an If-None-Match header value matches etag,
+// using the weak comparison RFC 9110 prescribes for it
+func Matches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// Rule configures caching for one route
+type Rule struct {
+	TTL time.Duration
+	// Tags lists what a response of the route shows, from the request
+	Tags func(c *fiber.Ctx) []string
+	// PerUser keeps a separate entry for each caller, for routes whose
+	// responses depend on who asks
+	PerUser bool
+}
+
+// Static tags every response of a route the same
+func Static(tags ...string) func(c *fiber.Ctx) []string {
+	return func(c *fiber.Ctx) []string { return tags }
+}
+
+// Param tags a response with tag applied to the route parameter param
+func Param(tag func(id string) string, param string) func(c *fiber.Ctx) []string {
+	return func(c *fiber.Ctx) []string { return []string{tag(c.Params(param))} }
+}
@@ -0,0 +1,141 @@
+package httpcache
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func newEntry(body string, tags ...string) *Entry {
+	return &Entry{Status: 200, Body: []byte(body), ETag: ETag([]byte(body)), Tags: tags, ExpiresAt: time.Now().Add(time.Minute)}
+}
+
+// cached reports which of keys the cache holds
+func cached(t *testing.T, c *Cache, keys ...string) []string {
+	t.Helper()
+	var found []string
+	for _, key := range keys {
+		if _, ok, err := c.Get(context.Background(), key); err != nil {
+			t.Fatalf("Get(%s): %v", key, err)
+		} else if ok {
+			found = append(found, key)
+		}
+	}
+	return found
+}
+
+func TestInvalidateDropsTaggedEntries(t *testing.T) {
+	ctx := context.Background()
+	c := New(NewMemoryStore(100))
+	c.Set(ctx, "user-1", newEntry("ann", UserTag("1")), c.Generation())
+	c.Set(ctx, "user-2", newEntry("bob", UserTag("2")), c.Generation())
+	c.Set(ctx, "attendees", newEntry("ann,bob", AttendeesTag, UserTag("1"), UserTag("2")), c.Generation())
+	c.Set(ctx, "event-1", newEntry("party", EventTag("1")), c.Generation())
+
+	if err := c.Invalidate(ctx, UserTag("1")); err != nil {
+		t.Fatalf("Invalidate: %v", err)
+	}
+	if got := cached(t, c, "user-1", "user-2", "attendees", "event-1"); !slices.Equal(got, []string{"user-2", "event-1"}) {
+		t.Errorf("cached after invalidating user 1 = %v, want [user-2 event-1]", got)
+	}
+
+	// A key stored again keeps only its new tags
+	c.Set(ctx, "user-2", newEntry("bob", UserTag("3")), c.Generation())
+	c.Invalidate(ctx, UserTag("2"))
+	if got := cached(t, c, "user-2"); len(got) != 1 {
+		t.Error("entry was dropped for a tag it no longer carries")
+	}
+}
+
+func TestSetSkipsResponsesReadBeforeAnInvalidation(t *testing.T) {
+	ctx := context.Background()
+	c := New(NewMemoryStore(100))
+
+	generation := c.Generation()
+	c.Invalidate(ctx, UserTag("1")) // arrives while the backend is being read
+	c.Set(ctx, "user-1", newEntry("stale", UserTag("1")), generation)
+	if got := cached(t, c, "user-1"); len(got) > 0 {
+		t.Error("stored a response read before the invalidation")
+	}
+
+	c.Set(ctx, "user-1", newEntry("fresh", UserTag("1")), c.Generation())
+	if got := cached(t, c, "user-1"); len(got) != 1 {
+		t.Error("did not store a response read after the invalidation")
+	}
+}
+
+func TestMemoryStoreLimits(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore(2)
+
+	expired := newEntry("old")
+	expired.ExpiresAt = time.Now().Add(-time.Second)
+	s.Set(ctx, "expired", expired)
+	if _, ok, _ := s.Get(ctx, "expired"); ok {
+		t.Error("served an expired entry")
+	}
+
+	s.Set(ctx, "a", newEntry("a"))
+	s.Set(ctx, "b", newEntry("b"))
+	if _, ok, _ := s.Get(ctx, "b"); ok {
+		t.Error("stored an entry beyond the limit")
+	}
+	// Replacing an entry doesn't need room
+	s.Set(ctx, "a", newEntry("a2"))
+	if entry, ok, _ := s.Get(ctx, "a"); !ok || string(entry.Body) != "a2" {
+		t.Error("did not replace an existing entry at the limit")
+	}
+
+	// Expired entries are swept to make room
+	s.lastSweep = time.Now().Add(-sweepInterval)
+	s.Set(ctx, "b", newEntry("b"))
+	if _, ok, _ := s.Get(ctx, "b"); !ok {
+		t.Error("expired entries were not swept to make room")
+	}
+}
+
+func TestTagsFor(t *testing.T) {
+	tests := []struct {
+		name  string
+		event string
+		want  []string
+	}{
+		{"profile updated", `{"user_id":"7"}`, []string{UserTag("7"), AttendeesTag}},
+		{"user joined an event", `{"user_id":"7","event_id":"3"}`, []string{UserTag("7"), AttendeesTag, EventListTag, EventTag("3")}},
+		{"event changed", `{"event_id":"3","name":"party"}`, []string{EventListTag, EventTag("3")}},
+		{"unrelated", `{}`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tagsFor([]byte(tt.event))
+			if err != nil {
+				t.Fatalf("tagsFor: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("tagsFor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if _, err := tagsFor([]byte("nope")); err == nil {
+		t.Error("tagsFor accepted a malformed event")
+	}
+}
+
+func TestMatches(t *testing.T) {
+	etag := ETag([]byte("body"))
+	tests := map[string]bool{
+		etag:               true,
+		"W/" + etag:        true,
+		`"other", ` + etag: true,
+		"*":                true,
+		`"other"`:          false,
+		"":                 false,
+		etag[:len(etag)-1]: false,
+	}
+	for header, want := range tests {
+		if got := Matches(header, etag); got != want {
+			t.Errorf("Matches(%q) = %v, want %v", header, got, want)
+		}
+	}
+}
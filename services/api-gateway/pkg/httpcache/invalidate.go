@@ -0,0 +1,67 @@
+package httpcache
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/wutthichod/sa-connext/shared/contracts"
+	"github.com/wutthichod/sa-connext/shared/correlation"
+	"github.com/wutthichod/sa-connext/shared/messaging"
+)
+
+// ListenForInvalidations subscribes to the user and event domain events and
+// drops the entries they make stale. Every gateway instance gets its own
+// queue, since each has its own entries.
+func (c *Cache) ListenForInvalidations(rb *messaging.RabbitMQ) error {
+	bindings := map[string][]string{
+		contracts.UserExchange:  {contracts.UserProfileUpdatedRouting, contracts.UserEventChangedRouting},
+		contracts.EventExchange: {contracts.EventCreatedRouting, contracts.EventUpdatedRouting, contracts.EventDeletedRouting},
+	}
+
+	queue, err := rb.DeclareExclusiveQueue()
+	if err != nil {
+		return err
+	}
+	for exchange, routingKeys := range bindings {
+		if err := rb.DeclareExchange(exchange, "topic", true); err != nil {
+			return err
+		}
+		for _, routingKey := range routingKeys {
+			if err := rb.BindQueue(queue, exchange, routingKey); err != nil {
+				return err
+			}
+		}
+	}
+
+	return rb.ConsumeMessages(queue, func(ctx context.Context, msg []byte) error {
+		tags, err := tagsFor(msg)
+		if err != nil {
+			// A malformed event will never parse, so don't requeue it
+			correlation.Printf(ctx, "httpcache: failed to unmarshal domain event: %v", err)
+			return nil
+		}
+		return c.Invalidate(ctx, tags...)
+	})
+}
+
+// tagsFor maps a domain event to the tags it makes stale. The events share
+// one queue, so they are told apart by their fields.
+func tagsFor(msg []byte) ([]string, error) {
+	var event struct {
+		UserID  string `json:"user_id"`
+		EventID string `json:"event_id"`
+	}
+	if err := json.Unmarshal(msg, &event); err != nil {
+		return nil, err
+	}
+
+	var tags []string
+	if event.UserID != "" {
+		// Profiles and event membership both show up in attendee lists
+		tags = append(tags, UserTag(event.UserID), AttendeesTag)
+	}
+	if event.EventID != "" {
+		tags = append(tags, EventListTag, EventTag(event.EventID))
+	}
+	return tags, nil
+}
@@ -0,0 +1,99 @@
+package httpcache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often expired entries are dropped
+const sweepInterval = time.Minute
+
+// MemoryStore keeps entries in process memory, up to maxEntries of them
+type MemoryStore struct {
+	maxEntries int
+
+	mutex     sync.RWMutex
+	entries   map[string]*Entry
+	tags      map[string]map[string]struct{}
+	lastSweep time.Time
+}
+
+// NewMemoryStore creates a store that skips new entries once it holds
+// maxEntries, until expired ones have been swept
+func NewMemoryStore(maxEntries int) *MemoryStore {
+	return &MemoryStore{
+		maxEntries: maxEntries,
+		entries:    make(map[string]*Entry),
+		tags:       make(map[string]map[string]struct{}),
+		lastSweep:  time.Now(),
+	}
+}
+
+func (s *MemoryStore) Get(ctx context.Context, key string) (*Entry, bool, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	entry, ok := s.entries[key]
+	if !ok || time.Now().After(entry.ExpiresAt) {
+		return nil, false, nil
+	}
+	return entry, true, nil
+}
+
+func (s *MemoryStore) Set(ctx context.Context, key string, entry *Entry) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.lastSweep) >= sweepInterval {
+		s.sweep(now)
+	}
+	if _, exists := s.entries[key]; !exists && len(s.entries) >= s.maxEntries {
+		return nil
+	}
+
+	s.remove(key)
+	s.entries[key] = entry
+	for _, tag := range entry.Tags {
+		if s.tags[tag] == nil {
+			s.tags[tag] = make(map[string]struct{})
+		}
+		s.tags[tag][key] = struct{}{}
+	}
+	return nil
+}
+
+func (s *MemoryStore) Invalidate(ctx context.Context, tags ...string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, tag := range tags {
+		for key := range s.tags[tag] {
+			s.remove(key)
+		}
+	}
+	return nil
+}
+
+// remove drops key and its tag index entries
+func (s *MemoryStore) remove(key string) {
+	entry, ok := s.entries[key]
+	if !ok {
+		return
+	}
+	delete(s.entries, key)
+	for _, tag := range entry.Tags {
+		delete(s.tags[tag], key)
+		if len(s.tags[tag]) == 0 {
+			delete(s.tags, tag)
+		}
+	}
+}
+
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, entry := range s.entries {
+		if now.After(entry.ExpiresAt) {
+			s.remove(key)
+		}
+	}
+	s.lastSweep = now
+}
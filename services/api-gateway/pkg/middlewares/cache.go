@@ -0,0 +1,74 @@
+package middlewares
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/httpcache"
+	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/versioning"
+)
+
+// CacheMiddleware serves GET requests from cache while their entry is fresh
+// and answers If-None-Match with 304 when the ETag still matches. Register
+// it after JWTMiddleware, so a cached response is only shown to callers the
+// route would have let through.
+func CacheMiddleware(cache *httpcache.Cache, rule httpcache.Rule) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if cache == nil || c.Method() != fiber.MethodGet {
+			return c.Next()
+		}
+
+		// Every version serves the same data, so they share entries
+		key := versioning.Strip(c.Path()) + "?" + string(c.Request().URI().QueryString())
+		if rule.PerUser {
+			key += "#" + fmt.Sprint(c.Locals("userID"))
+		}
+		entry, ok, err := cache.Get(c.UserContext(), key)
+		if err != nil {
+			// Don't take the gateway down with the cache's store
+			log.Printf("response cache unavailable: %v", err)
+		}
+		if ok {
+			c.Set("X-Cache", "HIT")
+			return respond(c, entry)
+		}
+
+		generation := cache.Generation()
+		if err := c.Next(); err != nil {
+			return err
+		}
+		c.Set("X-Cache", "MISS")
+		if c.Response().StatusCode() != fiber.StatusOK {
+			return nil
+		}
+
+		body := append([]byte(nil), c.Response().Body()...)
+		entry = &httpcache.Entry{
+			Status:      fiber.StatusOK,
+			ContentType: string(c.Response().Header.ContentType()),
+			Body:        body,
+			ETag:        httpcache.ETag(body),
+			Tags:        rule.Tags(c),
+			ExpiresAt:   time.Now().Add(rule.TTL),
+		}
+		if err := cache.Set(c.UserContext(), key, entry, generation); err != nil {
+			log.Printf("response cache unavailable: %v", err)
+		}
+		return respond(c, entry)
+	}
+}
+
+// respond writes entry, or 304 when the caller already has it
+func respond(c *fiber.Ctx, entry *httpcache.Entry) error {
+	c.Set(fiber.HeaderETag, entry.ETag)
+	// Entries can be dropped before they expire, so clients revalidate
+	c.Set(fiber.HeaderCacheControl, "private, no-cache")
+	if httpcache.Matches(c.Get(fiber.HeaderIfNoneMatch), entry.ETag) {
+		c.Response().ResetBody()
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+	c.Set(fiber.HeaderContentType, entry.ContentType)
+	return c.Status(entry.Status).Send(entry.Body)
+}
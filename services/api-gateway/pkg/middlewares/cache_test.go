@@ -0,0 +1,129 @@
+package middlewares
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/httpcache"
+)
+
+// newCachedApp serves /v1/profile/:id, which answers with the caller and a
+// count of backend reads. The caller comes from the X-User header, standing
+// in for JWTMiddleware.
+func newCachedApp(cache *httpcache.Cache, perUser bool) (*fiber.App, *int) {
+	reads := new(int)
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		if id, err := strconv.ParseUint(c.Get("X-User"), 10, 64); err == nil {
+			c.Locals("userID", uint(id))
+		}
+		return c.Next()
+	})
+	rule := httpcache.Rule{TTL: time.Minute, Tags: httpcache.Param(httpcache.UserTag, "id"), PerUser: perUser}
+	app.Get("/v1/profile/:id", CacheMiddleware(cache, rule), func(c *fiber.Ctx) error {
+		*reads++
+		return c.SendString(fmt.Sprintf("profile %s for %v, read %d", c.Params("id"), c.Locals("userID"), *reads))
+	})
+	return app, reads
+}
+
+func get(t *testing.T, app *fiber.App, path, user, ifNoneMatch string) (int, string, string, string) {
+	t.Helper()
+	req := httptest.NewRequest(fiber.MethodGet, path, nil)
+	if user != "" {
+		req.Header.Set("X-User", user)
+	}
+	if ifNoneMatch != "" {
+		req.Header.Set(fiber.HeaderIfNoneMatch, ifNoneMatch)
+	}
+	res, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("GET %s: %v", path, err)
+	}
+	body, _ := io.ReadAll(res.Body)
+	return res.StatusCode, string(body), res.Header.Get(fiber.HeaderETag), res.Header.Get("X-Cache")
+}
+
+func TestCacheMiddlewarePerUser(t *testing.T) {
+	app, reads := newCachedApp(httpcache.New(httpcache.NewMemoryStore(100)), true)
+
+	_, ann, _, _ := get(t, app, "/v1/profile/1", "1", "")
+	_, bob, _, xCache := get(t, app, "/v1/profile/1", "2", "")
+	if bob == ann || xCache != "MISS" {
+		t.Fatalf("user 2 got %q (%s), user 1's response was %q", bob, xCache, ann)
+	}
+	if _, again, _, xCache := get(t, app, "/v1/profile/1", "1", ""); again != ann || xCache != "HIT" {
+		t.Errorf("user 1 got %q (%s) the second time, want a hit on %q", again, xCache, ann)
+	}
+	if _, anonymous, _, _ := get(t, app, "/v1/profile/1", "", ""); anonymous == ann || anonymous == bob {
+		t.Errorf("a caller without a user was served %q from another user's entry", anonymous)
+	}
+	if *reads != 3 {
+		t.Errorf("backend read %d times, want once per caller", *reads)
+	}
+}
+
+func TestCacheMiddlewareShared(t *testing.T) {
+	app, reads := newCachedApp(httpcache.New(httpcache.NewMemoryStore(100)), false)
+
+	_, first, _, _ := get(t, app, "/v1/profile/1", "1", "")
+	if _, second, _, xCache := get(t, app, "/v1/profile/1", "2", ""); second != first || xCache != "HIT" {
+		t.Errorf("second caller got %q (%s), want the shared entry", second, xCache)
+	}
+	// The path and query string are part of the key
+	get(t, app, "/v1/profile/1?full=1", "1", "")
+	get(t, app, "/v1/profile/2", "1", "")
+	if *reads != 3 {
+		t.Errorf("backend read %d times, want 3", *reads)
+	}
+}
+
+func TestCacheMiddlewareConditionalRequests(t *testing.T) {
+	app, _ := newCachedApp(httpcache.New(httpcache.NewMemoryStore(100)), true)
+
+	status, _, etag, _ := get(t, app, "/v1/profile/1", "1", "")
+	if status != fiber.StatusOK || etag == "" {
+		t.Fatalf("status = %d, etag = %q", status, etag)
+	}
+	if status, body, _, _ := get(t, app, "/v1/profile/1", "1", etag); status != fiber.StatusNotModified || body != "" {
+		t.Errorf("revalidation got %d %q, want an empty 304", status, body)
+	}
+	if status, _, _, _ := get(t, app, "/v1/profile/1", "1", `"stale"`); status != fiber.StatusOK {
+		t.Errorf("stale ETag got %d, want 200", status)
+	}
+	// Another user's ETag is checked against their own entry
+	if status, _, _, _ := get(t, app, "/v1/profile/1", "2", etag); status != fiber.StatusOK {
+		t.Errorf("user 2 with user 1's ETag got %d, want 200", status)
+	}
+}
+
+func TestCacheMiddlewareInvalidation(t *testing.T) {
+	cache := httpcache.New(httpcache.NewMemoryStore(100))
+	app, reads := newCachedApp(cache, true)
+
+	get(t, app, "/v1/profile/1", "1", "")
+	get(t, app, "/v1/profile/1", "2", "")
+	get(t, app, "/v1/profile/2", "1", "")
+	if err := cache.Invalidate(context.Background(), httpcache.UserTag("1")); err != nil {
+		t.Fatalf("Invalidate: %v", err)
+	}
+
+	// Every caller's copy of profile 1 is dropped; profile 2 is kept
+	for _, user := range []string{"1", "2"} {
+		if _, _, _, xCache := get(t, app, "/v1/profile/1", user, ""); xCache != "MISS" {
+			t.Errorf("user %s got %s for an invalidated profile", user, xCache)
+		}
+	}
+	if _, _, _, xCache := get(t, app, "/v1/profile/2", "1", ""); xCache != "HIT" {
+		t.Errorf("untouched profile got %s, want HIT", xCache)
+	}
+	if *reads != 5 {
+		t.Errorf("backend read %d times, want 5", *reads)
+	}
+}
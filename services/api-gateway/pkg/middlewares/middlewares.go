@@ -2,41 +2,108 @@ package middlewares
 
 import (
 	"github.com/gofiber/fiber/v2"
+	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/revocations"
 	"github.com/wutthichod/sa-connext/shared/auth"
 	"github.com/wutthichod/sa-connext/shared/config"
+	"github.com/wutthichod/sa-connext/shared/contracts"
+	grpcerrors "github.com/wutthichod/sa-connext/shared/errors"
 )
 
 // JWTMiddleware checks for JWT in cookie, Authorization header, or query parameter and validates it
 func JWTMiddleware(cfg config.Config) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		// 1. Get JWT from cookie first, then fallback to Authorization header, then query parameter
-		token := c.Cookies("token")
-		if token == "" {
-			// Try Authorization header as fallback
-			authHeader := c.Get("Authorization")
-			if authHeader != "" && len(authHeader) > 7 && authHeader[:7] == "Bearer " {
-				token = authHeader[7:]
-			}
-		}
-		if token == "" {
-			// Try query parameter (useful for WebSocket connections)
-			token = c.Query("token")
-		}
-
+		token := TokenFromRequest(c)
 		if token == "" {
 			return fiber.NewError(fiber.StatusUnauthorized, "missing token")
 		}
 
 		// 2. Validate token
-		claims, err := auth.ValidateToken(cfg.JWT().Token, token)
+		claims, err := validateToken(c, cfg)
 		if err != nil {
 			return fiber.NewError(fiber.StatusUnauthorized, "invalid or expired token")
 		}
+		if isRevoked(c, claims) {
+			return fiber.NewError(fiber.StatusUnauthorized, "token has been revoked")
+		}
+		if isSuspended(c, claims) {
+			return accountSuspended(c)
+		}
 
 		// 3. Store user info in Locals for next handlers
 		c.Locals("userID", claims.UserID)
+		c.Locals("role", claims.Role)
+		c.Locals("emailVerified", claims.EmailVerified)
 
 		// 4. Continue to next handler
 		return c.Next()
 	}
 }
+
+const claimsKey = "claims"
+
+type tokenValidation struct {
+	claims *auth.Claims
+	err    error
+}
+
+// validateToken checks the request's token once, however many middlewares
+// ask, and keeps the outcome in Locals
+func validateToken(c *fiber.Ctx, cfg config.Config) (*auth.Claims, error) {
+	if v, ok := c.Locals(claimsKey).(tokenValidation); ok {
+		return v.claims, v.err
+	}
+	claims, err := auth.ValidateToken(auth.JWKS(cfg.JWT().JWKSURL), TokenFromRequest(c))
+	c.Locals(claimsKey, tokenValidation{claims: claims, err: err})
+	return claims, err
+}
+
+const revocationsKey = "revocations"
+
+func isRevoked(c *fiber.Ctx, claims *auth.Claims) bool {
+	revoked, _ := c.Locals(revocationsKey).(*revocations.List)
+	return revoked.IsRevoked(claims)
+}
+
+// TokenRevocations makes JWTMiddleware turn away revoked tokens. Register it
+// with app.Use ahead of the routes.
+func TokenRevocations(list *revocations.List) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals(revocationsKey, list)
+		return c.Next()
+	}
+}
+
+// RequireRole lets only users whose token carries role through. Register it
+// after JWTMiddleware.
+func RequireRole(role string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if got, _ := c.Locals("role").(string); got != role {
+			return c.Status(fiber.StatusForbidden).JSON(contracts.Resp{
+				Success:    false,
+				StatusCode: fiber.StatusForbidden,
+				Message:    "this requires the " + role + " role",
+				Data:       map[string]interface{}{"error_code": grpcerrors.CodeForbidden},
+			})
+		}
+		return c.Next()
+	}
+}
+
+// TokenFromRequest reads the JWT from the cookie, the Authorization header or
+// the query string, in that order
+func TokenFromRequest(c *fiber.Ctx) string {
+	token := c.Cookies("token")
+	if token == "" {
+		// Try Authorization header as fallback
+		authHeader := c.Get("Authorization")
+		if authHeader != "" && len(authHeader) > 7 && authHeader[:7] == "Bearer " {
+			token = authHeader[7:]
+		}
+	}
+	if token == "" {
+		// Try query parameter (useful for WebSocket connections)
+		token = c.Query("token")
+	}
+	return token
+}
@@ -0,0 +1,54 @@
+package middlewares
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/ratelimit"
+	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/versioning"
+	"github.com/wutthichod/sa-connext/shared/config"
+	"github.com/wutthichod/sa-connext/shared/contracts"
+	"github.com/wutthichod/sa-connext/shared/correlation"
+)
+
+// RateLimitMiddleware limits requests per route group. Callers with a valid
+// token are limited by user ID, everyone else by client IP. Register it after
+// Suspensions and TokenRevocations, so refused tokens count against the IP.
+func RateLimitMiddleware(cfg config.Config, limiter *ratelimit.Limiter) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Rules are written without a version prefix so they cover every version
+		res, rule, err := limiter.Allow(c.Context(), versioning.Strip(c.Path()), rateLimitIdentity(cfg, c))
+		if err != nil {
+			// Don't take the gateway down with the limiter's store
+			correlation.Printf(c.UserContext(), "rate limiter unavailable: %v", err)
+			return c.Next()
+		}
+		if rule.Group == "" {
+			return c.Next()
+		}
+
+		c.Set("X-RateLimit-Limit", strconv.Itoa(rule.Limit.Burst))
+		c.Set("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		if !res.Allowed {
+			retryAfter := int(math.Ceil(res.RetryAfter.Seconds()))
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(max(retryAfter, 1)))
+			return c.Status(fiber.StatusTooManyRequests).JSON(contracts.Resp{
+				Success:    false,
+				StatusCode: fiber.StatusTooManyRequests,
+				Message:    "too many requests, please retry later",
+			})
+		}
+		return c.Next()
+	}
+}
+
+// rateLimitIdentity is the user ID of a token JWTMiddleware would accept, or
+// else the client IP. The validation is reused by JWTMiddleware.
+func rateLimitIdentity(cfg config.Config, c *fiber.Ctx) string {
+	if claims, err := validateToken(c, cfg); err == nil && !isRevoked(c, claims) && !isSuspended(c, claims) {
+		return fmt.Sprintf("user:%d", claims.UserID)
+	}
+	return "ip:" + c.IP()
+}
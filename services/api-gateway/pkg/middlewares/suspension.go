@@ -0,0 +1,37 @@
+package middlewares
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/suspensions"
+	"github.com/wutthichod/sa-connext/shared/auth"
+	"github.com/wutthichod/sa-connext/shared/contracts"
+)
+
+// accountSuspendedCode is the error code user-service also uses at login
+const accountSuspendedCode = "ACCOUNT_SUSPENDED"
+
+const suspensionsKey = "suspensions"
+
+// Suspensions makes JWTMiddleware turn away the tokens of suspended users,
+// which would otherwise work until they expire. Register it with app.Use
+// ahead of the routes.
+func Suspensions(list *suspensions.List) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals(suspensionsKey, list)
+		return c.Next()
+	}
+}
+
+func isSuspended(c *fiber.Ctx, claims *auth.Claims) bool {
+	suspended, _ := c.Locals(suspensionsKey).(*suspensions.List)
+	return suspended.IsSuspended(claims.UserID)
+}
+
+func accountSuspended(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusForbidden).JSON(contracts.Resp{
+		Success:    false,
+		StatusCode: fiber.StatusForbidden,
+		Message:    "this account has been suspended",
+		Data:       map[string]interface{}{"error_code": accountSuspendedCode},
+	})
+}
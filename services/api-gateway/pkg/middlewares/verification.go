@@ -0,0 +1,43 @@
+package middlewares
+
+import (
+	"slices"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wutthichod/sa-connext/shared/config"
+	"github.com/wutthichod/sa-connext/shared/contracts"
+)
+
+// Actions config.Verification can hold back until a user verifies their email
+const (
+	ActionDirectMessage = "dm"
+	ActionCreateGroup   = "create_group"
+	ActionCreateEvent   = "create_event"
+)
+
+const emailNotVerifiedCode = "EMAIL_NOT_VERIFIED"
+
+// Restricted reports whether the configured policy holds the action back
+// from the caller until they verify their email. For actions a route can't
+// refuse up front, such as messaging a chat that turns out to be direct.
+func Restricted(c *fiber.Ctx, cfg config.Config, action string) bool {
+	verified, _ := c.Locals("emailVerified").(bool)
+	return !verified && slices.Contains(cfg.Verification().Restricted, action)
+}
+
+// RequireVerified refuses the action to users who haven't verified their
+// email, if the configured policy restricts it. Register it after
+// JWTMiddleware.
+func RequireVerified(cfg config.Config, action string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !Restricted(c, cfg, action) {
+			return c.Next()
+		}
+		return c.Status(fiber.StatusForbidden).JSON(contracts.Resp{
+			Success:    false,
+			StatusCode: fiber.StatusForbidden,
+			Message:    "verify your email to do this",
+			Data:       map[string]interface{}{"error_code": emailNotVerifiedCode},
+		})
+	}
+}
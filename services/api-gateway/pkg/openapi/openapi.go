@@ -0,0 +1,283 @@
+// Package openapi builds an OpenAPI 3 document for the gateway from route
+// metadata and the DTO types each route accepts and returns.
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wutthichod/sa-connext/shared/contracts"
+)
+
+const Version = "3.0.3"
+
+// Route describes one gateway endpoint. Path uses Fiber syntax, e.g. "/chats/:id/messages".
+type Route struct {
+	Method      string
+	Path        string
+	Summary     string
+	Tag         string
+	Auth        bool // behind the JWT middleware
+	Request     any  // zero value of the JSON body DTO, nil when there is no body
+	Response    any  // zero value of the contracts.Resp data, nil when there is none
+	Status      int  // success status, defaults to 200
+	Unwrapped   bool // Response is the whole body rather than contracts.Resp data
+	Query       []Param
+	WebSocket   bool // upgraded to a websocket, documented as a 101 response
+	Deprecated  bool
+	Description string
+}
+
+// Param documents a query parameter
+type Param struct {
+	Name        string
+	Description string
+	Required    bool
+}
+
+// Document is the root of an OpenAPI 3 document
+type Document struct {
+	OpenAPI    string                          `json:"openapi"`
+	Info       Info                            `json:"info"`
+	Paths      map[string]map[string]Operation `json:"paths"`
+	Components Components                      `json:"components"`
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	Responses       map[string]Response       `json:"responses"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	In           string `json:"in,omitempty"`
+	Name         string `json:"name,omitempty"`
+}
+
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+	Deprecated  bool                  `json:"deprecated,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Ref         string               `json:"$ref,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+var pathParam = regexp.MustCompile(`:([A-Za-z0-9_]+)`)
+
+// Path converts a Fiber route path to OpenAPI syntax, dropping a trailing slash
+func Path(route string) string {
+	if len(route) > 1 {
+		route = strings.TrimSuffix(route, "/")
+	}
+	return pathParam.ReplaceAllString(route, "{$1}")
+}
+
+// Build generates the document for routes. It fails on two routes with the
+// same method and path, which would otherwise silently overwrite each other.
+func Build(title, version string, routes []Route) (*Document, error) {
+	schemas := newRegistry()
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    Info{Title: title, Version: version},
+		Paths:   map[string]map[string]Operation{},
+		Components: Components{
+			Schemas: schemas.schemas,
+			Responses: map[string]Response{
+				"Error": {
+					Description: "Error envelope; data.error_code and data.fields carry the details",
+					Content:     jsonContent(schemas.schemaOf(reflect.TypeOf(contracts.Resp{}))),
+				},
+			},
+			SecuritySchemes: map[string]SecurityScheme{
+				"cookieAuth": {Type: "apiKey", In: "cookie", Name: "token"},
+				"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+	}
+
+	for _, route := range routes {
+		path := Path(route.Path)
+		method := strings.ToLower(route.Method)
+		if _, exists := doc.Paths[path][method]; exists {
+			return nil, fmt.Errorf("openapi: %s %s is documented twice", route.Method, path)
+		}
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = map[string]Operation{}
+		}
+		doc.Paths[path][method] = schemas.operation(route, path)
+	}
+	return doc, nil
+}
+
+func (r *registry) operation(route Route, path string) Operation {
+	op := Operation{
+		OperationID: operationID(route.Method, path),
+		Summary:     route.Summary,
+		Description: route.Description,
+		Deprecated:  route.Deprecated,
+		Responses:   map[string]Response{"default": {Ref: "#/components/responses/Error"}},
+	}
+	if route.Tag != "" {
+		op.Tags = []string{route.Tag}
+	}
+	if route.Auth {
+		op.Security = []map[string][]string{{"cookieAuth": {}}, {"bearerAuth": {}}}
+	}
+
+	for _, match := range pathParam.FindAllStringSubmatch(route.Path, -1) {
+		op.Parameters = append(op.Parameters, Parameter{
+			Name:     match[1],
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "string"},
+		})
+	}
+	for _, query := range route.Query {
+		op.Parameters = append(op.Parameters, Parameter{
+			Name:        query.Name,
+			In:          "query",
+			Description: query.Description,
+			Required:    query.Required,
+			Schema:      &Schema{Type: "string"},
+		})
+	}
+
+	if route.Request != nil {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  jsonContent(r.schemaOf(reflect.TypeOf(route.Request))),
+		}
+	}
+
+	if route.WebSocket {
+		op.Responses["101"] = Response{Description: "Switching to the websocket protocol"}
+		return op
+	}
+
+	status := route.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	op.Responses[fmt.Sprint(status)] = Response{
+		Description: http.StatusText(status),
+		Content:     jsonContent(r.responseSchema(route)),
+	}
+	return op
+}
+
+// responseSchema wraps the route's data in the contracts.Resp envelope
+func (r *registry) responseSchema(route Route) *Schema {
+	if route.Unwrapped && route.Response != nil {
+		return r.schemaOf(reflect.TypeOf(route.Response))
+	}
+	envelope := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"success":     {Type: "boolean"},
+			"status_code": {Type: "integer"},
+			"message":     {Type: "string"},
+		},
+		Required: []string{"success"},
+	}
+	if route.Response != nil {
+		envelope.Properties["data"] = r.schemaOf(reflect.TypeOf(route.Response))
+	}
+	return envelope
+}
+
+func jsonContent(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{fiber.MIMEApplicationJSON: {Schema: schema}}
+}
+
+// operationID turns "GET /chats/{id}/messages" into "getChatsIdMessages"
+func operationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	upper := true
+	for _, r := range path {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			if upper {
+				b.WriteString(strings.ToUpper(string(r)))
+			} else {
+				b.WriteRune(r)
+			}
+			upper = false
+		default:
+			upper = true
+		}
+	}
+	return b.String()
+}
+
+// Handler serves doc as JSON
+func Handler(doc *Document) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return c.JSON(doc)
+	}
+}
+
+// DocsHandler serves a Swagger UI page that renders the document at specURL
+func DocsHandler(title, specURL string) fiber.Handler {
+	page := fmt.Sprintf(docsPage, title, specURL)
+	return func(c *fiber.Ctx) error {
+		c.Type("html")
+		return c.SendString(page)
+	}
+}
+
+const docsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>%s</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: %q, dom_id: "#swagger-ui", withCredentials: true });
+  </script>
+</body>
+</html>
+`
@@ -0,0 +1,165 @@
+package openapi
+
+import (
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Schema is the subset of the OpenAPI schema object the gateway DTOs need
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	UniqueItems          bool               `json:"uniqueItems,omitempty"`
+}
+
+// registry collects named struct schemas under components/schemas
+type registry struct {
+	schemas map[string]*Schema
+}
+
+func newRegistry() *registry {
+	return &registry{schemas: map[string]*Schema{}}
+}
+
+// schemaOf returns the schema for t, registering named structs as components
+func (r *registry) schemaOf(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: r.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: r.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return r.structSchema(t)
+		}
+		name := componentName(t)
+		if _, ok := r.schemas[name]; !ok {
+			// Reserve the name first so self-referencing types terminate
+			r.schemas[name] = &Schema{}
+			*r.schemas[name] = *r.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	default:
+		// interfaces such as contracts.Resp.Data can hold anything
+		return &Schema{}
+	}
+}
+
+// componentName qualifies the type with its package, so dto.User and the
+// protobuf user.User do not collide
+func componentName(t reflect.Type) string {
+	return path.Base(t.PkgPath()) + "." + t.Name()
+}
+
+func (r *registry) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := r.schemaOf(field.Type)
+		if applyRules(property, field.Tag.Get("validate")) {
+			schema.Required = append(schema.Required, name)
+		}
+		schema.Properties[name] = property
+	}
+	return schema
+}
+
+// applyRules maps validate tag rules onto schema constraints and reports
+// whether the field is required. Rules after "dive" describe the elements.
+func applyRules(schema *Schema, tag string) bool {
+	if tag == "" {
+		return false
+	}
+	rules, elementRules, dive := strings.Cut(tag, ",dive")
+	if dive && schema.Items != nil && schema.Items.Ref == "" {
+		applyRules(schema.Items, strings.TrimPrefix(elementRules, ","))
+	}
+
+	required := false
+	for _, rule := range strings.Split(rules, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			required = true
+		case "min", "max", "len":
+			n, err := strconv.Atoi(param)
+			if err != nil {
+				continue
+			}
+			setBound(schema, name, n)
+		case "email":
+			schema.Format = "email"
+		case "rfc3339":
+			schema.Format = "date-time"
+		case "base64":
+			schema.Format = "byte"
+		case "numeric":
+			schema.Pattern = "^[0-9]+$"
+		case "alphanum":
+			schema.Pattern = "^[A-Za-z0-9]+$"
+		case "objectid":
+			schema.Pattern = "^[0-9a-fA-F]{24}$"
+		case "unique":
+			schema.UniqueItems = true
+		case "oneof":
+			schema.Enum = strings.Fields(param)
+		}
+	}
+	return required
+}
+
+func setBound(schema *Schema, rule string, n int) {
+	lower, upper := &schema.MinLength, &schema.MaxLength
+	if schema.Type == "array" {
+		lower, upper = &schema.MinItems, &schema.MaxItems
+	}
+	switch rule {
+	case "min":
+		*lower = &n
+	case "max":
+		*upper = &n
+	case "len":
+		*lower, *upper = &n, &n
+	}
+}
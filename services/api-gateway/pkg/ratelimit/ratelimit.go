@@ -0,0 +1,80 @@
+package ratelimit
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// Limit describes a token bucket: Burst tokens at most, refilled at Rate
+// tokens per second
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// PerMinute allows n requests a minute with bursts of up to burst requests
+func PerMinute(n, burst int) Limit {
+	return Limit{Rate: float64(n) / 60, Burst: burst}
+}
+
+// Result is the outcome of taking a token
+type Result struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration // how long until a token is available when not allowed
+}
+
+// Store keeps the buckets. MemoryStore works for a single gateway instance;
+// a shared implementation (e.g. Redis) can be plugged in for several.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// Rule applies a limit to every path starting with Prefix. Rules sharing a
+// Group share their buckets.
+type Rule struct {
+	Group  string
+	Prefix string
+	Limit  Limit
+}
+
+// Limiter picks the rule for a request path and takes a token from the
+// caller's bucket in that rule's group
+type Limiter struct {
+	store Store
+	rules []Rule
+}
+
+// NewLimiter checks rules in order, so more specific prefixes go first
+func NewLimiter(store Store, rules []Rule) *Limiter {
+	return &Limiter{store: store, rules: rules}
+}
+
+// Allow takes a token for identity on path. Paths matching no rule are
+// not limited.
+func (l *Limiter) Allow(ctx context.Context, path, identity string) (Result, Rule, error) {
+	for _, rule := range l.rules {
+		if strings.HasPrefix(path, rule.Prefix) {
+			res, err := l.store.Take(ctx, rule.Group+":"+identity, rule.Limit)
+			return res, rule, err
+		}
+	}
+	return Result{Allowed: true}, Rule{}, nil
+}
+
+// DefaultRules are the gateway's limits. Login, registration and event
+// joining are tight since they are the targets of password and joining code
+// guessing.
+func DefaultRules() []Rule {
+	return []Rule{
+		{Group: "auth", Prefix: "/users/login", Limit: PerMinute(10, 5)},
+		{Group: "auth", Prefix: "/users/register", Limit: PerMinute(10, 5)},
+		{Group: "auth-refresh", Prefix: "/users/refresh", Limit: PerMinute(30, 10)},
+		{Group: "auth", Prefix: "/users/verify-email", Limit: PerMinute(10, 5)},
+		{Group: "verification-resend", Prefix: "/users/me/verification", Limit: PerMinute(2, 2)},
+		{Group: "event-join", Prefix: "/events/join", Limit: PerMinute(10, 5)},
+		{Group: "chat-send", Prefix: "/chats/send", Limit: PerMinute(60, 20)},
+		{Group: "default", Prefix: "/", Limit: PerMinute(300, 100)},
+	}
+}
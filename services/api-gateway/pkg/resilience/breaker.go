@@ -0,0 +1,123 @@
+package resilience
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/wutthichod/sa-connext/shared/metrics"
+)
+
+type state int
+
+const (
+	closed state = iota
+	halfOpen
+	open
+)
+
+func (s state) String() string {
+	switch s {
+	case halfOpen:
+		return "half-open"
+	case open:
+		return "open"
+	default:
+		return "closed"
+	}
+}
+
+// Breaker stops calls to a downstream service after threshold consecutive
+// failures. Once cooldown has passed it lets a single trial call through,
+// which closes it again on success or reopens it on failure.
+type Breaker struct {
+	service   string
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	state    state
+	failures int
+	openedAt time.Time
+}
+
+// NewBreaker creates a closed breaker. A threshold of 0 or less disables it.
+func NewBreaker(service string, threshold int, cooldown time.Duration) *Breaker {
+	metrics.BreakerState.WithLabelValues(service).Set(float64(closed))
+	return &Breaker{
+		service:   service,
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// Allow reports whether a call may go ahead. Every allowed call must be
+// followed by Record.
+func (b *Breaker) Allow() bool {
+	if b.threshold <= 0 {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case open:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.transition(halfOpen)
+		return true
+	case halfOpen:
+		// The trial call is still in flight
+		return false
+	default:
+		return true
+	}
+}
+
+// Record reports the outcome of an allowed call
+func (b *Breaker) Record(failed bool) {
+	if b.threshold <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !failed {
+		b.failures = 0
+		if b.state != closed {
+			b.transition(closed)
+		}
+		return
+	}
+
+	b.failures++
+	if b.state == halfOpen || b.failures >= b.threshold {
+		b.openedAt = b.now()
+		if b.state != open {
+			b.transition(open)
+		}
+	}
+}
+
+// Release ends an allowed call that says nothing about the service's health,
+// e.g. one the caller cancelled. A trial call released this way is retried
+// by the next caller.
+func (b *Breaker) Release() {
+	if b.threshold <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == halfOpen {
+		b.transition(open)
+	}
+}
+
+func (b *Breaker) transition(to state) {
+	log.Printf("circuit breaker for %s: %s -> %s", b.service, b.state, to)
+	b.state = to
+	metrics.BreakerState.WithLabelValues(b.service).Set(float64(to))
+}
@@ -0,0 +1,134 @@
+package resilience
+
+import (
+	"testing"
+	"time"
+)
+
+// clock is a manual time source for the breaker
+type clock struct{ now time.Time }
+
+func (c *clock) Now() time.Time          { return c.now }
+func (c *clock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func newTestBreaker(threshold int, cooldown time.Duration) (*Breaker, *clock) {
+	c := &clock{now: time.Unix(1700000000, 0)}
+	b := NewBreaker("test", threshold, cooldown)
+	b.now = c.Now
+	return b, c
+}
+
+// fail records n failed calls, each of which must be allowed
+func fail(t *testing.T, b *Breaker, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		if !b.Allow() {
+			t.Fatalf("call %d was refused", i+1)
+		}
+		b.Record(true)
+	}
+}
+
+func TestBreakerOpensAtThreshold(t *testing.T) {
+	b, _ := newTestBreaker(3, time.Minute)
+
+	fail(t, b, 2)
+	if b.state != closed {
+		t.Fatalf("state = %s after 2 failures, want closed", b.state)
+	}
+	fail(t, b, 1)
+	if b.state != open {
+		t.Fatalf("state = %s after 3 failures, want open", b.state)
+	}
+	if b.Allow() {
+		t.Error("open breaker allowed a call")
+	}
+}
+
+func TestBreakerSuccessResetsFailures(t *testing.T) {
+	b, _ := newTestBreaker(3, time.Minute)
+
+	fail(t, b, 2)
+	b.Allow()
+	b.Record(false)
+	fail(t, b, 2)
+	if b.state != closed {
+		t.Errorf("state = %s, want closed: failures were not consecutive", b.state)
+	}
+}
+
+func TestBreakerHalfOpen(t *testing.T) {
+	tests := []struct {
+		name  string
+		trial func(b *Breaker)
+		want  state
+	}{
+		{name: "trial succeeds", trial: func(b *Breaker) { b.Record(false) }, want: closed},
+		{name: "trial fails", trial: func(b *Breaker) { b.Record(true) }, want: open},
+		{name: "trial cancelled", trial: func(b *Breaker) { b.Release() }, want: open},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, c := newTestBreaker(2, time.Minute)
+			fail(t, b, 2)
+
+			c.Advance(59 * time.Second)
+			if b.Allow() {
+				t.Fatal("allowed a call before the cooldown ended")
+			}
+
+			c.Advance(time.Second)
+			if !b.Allow() {
+				t.Fatal("refused the trial call after the cooldown")
+			}
+			if b.state != halfOpen {
+				t.Fatalf("state = %s during the trial, want half-open", b.state)
+			}
+			if b.Allow() {
+				t.Fatal("allowed a second call while the trial is in flight")
+			}
+
+			tt.trial(b)
+			if b.state != tt.want {
+				t.Errorf("state = %s after the trial, want %s", b.state, tt.want)
+			}
+		})
+	}
+}
+
+func TestBreakerReopensForAFullCooldown(t *testing.T) {
+	b, c := newTestBreaker(1, time.Minute)
+	fail(t, b, 1)
+
+	c.Advance(time.Minute)
+	fail(t, b, 1) // the trial
+	c.Advance(30 * time.Second)
+	if b.Allow() {
+		t.Error("allowed a call before a full cooldown after the failed trial")
+	}
+}
+
+func TestBreakerReleasedTrialIsRetried(t *testing.T) {
+	b, c := newTestBreaker(1, time.Minute)
+	fail(t, b, 1)
+
+	c.Advance(time.Minute)
+	b.Allow()
+	b.Release()
+	// Cancelling doesn't restart the cooldown, so the next caller tries
+	if !b.Allow() {
+		t.Fatal("refused a new trial after the last one was released")
+	}
+	b.Record(false)
+	if b.state != closed {
+		t.Errorf("state = %s, want closed", b.state)
+	}
+}
+
+func TestBreakerDisabled(t *testing.T) {
+	b, _ := newTestBreaker(0, time.Minute)
+	fail(t, b, 100)
+	if b.state != closed {
+		t.Errorf("state = %s, want a disabled breaker to stay closed", b.state)
+	}
+}
@@ -0,0 +1,120 @@
+// Package resilience guards the gateway's calls to downstream services with
+// per-attempt deadlines, retries for idempotent methods and a circuit breaker
+// per service, all configured through config.Resilience.
+package resilience
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"github.com/wutthichod/sa-connext/shared/config"
+	"github.com/wutthichod/sa-connext/shared/metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryClientInterceptor applies cfg to every call on a connection to
+// service. Only the full method names in idempotent, e.g.
+// "/users.UserService/GetUserById", are retried.
+func UnaryClientInterceptor(service string, cfg config.Resilience, idempotent ...string) grpc.UnaryClientInterceptor {
+	breaker := NewBreaker(service, cfg.BreakerThreshold, cfg.BreakerCooldown)
+	retryable := make(map[string]bool, len(idempotent))
+	for _, method := range idempotent {
+		retryable[method] = true
+	}
+
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		attempts := 1
+		if retryable[method] && cfg.MaxAttempts > 1 {
+			attempts = cfg.MaxAttempts
+		}
+
+		var err error
+		for attempt := 0; attempt < attempts; attempt++ {
+			if attempt > 0 {
+				if !sleep(ctx, backoff(cfg, attempt)) {
+					return err
+				}
+				metrics.DownstreamRetries.WithLabelValues(service).Inc()
+			}
+
+			if !breaker.Allow() {
+				return status.Errorf(codes.Unavailable, "SERVICE_UNAVAILABLE: %s is temporarily unavailable, try again later", service)
+			}
+			err = invoke(ctx, cfg.Timeout, method, req, reply, cc, invoker, opts...)
+			switch {
+			case ctx.Err() != nil:
+				// The caller gave up, which says nothing about the service
+				breaker.Release()
+				return err
+			case isFailure(err):
+				breaker.Record(true)
+			default:
+				breaker.Record(false)
+				return err
+			}
+			if !isTransient(err) {
+				return err
+			}
+		}
+		return err
+	}
+}
+
+// invoke makes one attempt, bounded by timeout
+func invoke(ctx context.Context, timeout time.Duration, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// isFailure reports whether err counts against the service's breaker.
+// Errors about the request itself, such as NotFound, do not.
+func isFailure(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Internal, codes.Unknown, codes.ResourceExhausted:
+		return true
+	default:
+		return false
+	}
+}
+
+// isTransient reports whether an idempotent call that failed with err may
+// succeed if tried again
+func isTransient(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted:
+		return true
+	default:
+		return false
+	}
+}
+
+// backoff is the wait before the given retry: exponential with full jitter
+func backoff(cfg config.Resilience, retry int) time.Duration {
+	if cfg.BackoffBase <= 0 {
+		return 0
+	}
+	d := cfg.BackoffBase << (retry - 1)
+	if cfg.BackoffMax > 0 && (d > cfg.BackoffMax || d <= 0) {
+		d = cfg.BackoffMax
+	}
+	return rand.N(d) + 1
+}
+
+// sleep waits for d unless ctx ends first
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
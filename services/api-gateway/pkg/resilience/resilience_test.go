@@ -0,0 +1,179 @@
+package resilience
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wutthichod/sa-connext/shared/config"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	getMethod    = "/test.Service/Get"
+	createMethod = "/test.Service/Create"
+)
+
+var testConfig = config.Resilience{
+	Timeout:          time.Second,
+	MaxAttempts:      3,
+	BackoffBase:      time.Millisecond,
+	BackoffMax:       2 * time.Millisecond,
+	BreakerThreshold: 5,
+	BreakerCooldown:  time.Minute,
+}
+
+// invoker fails with the given codes in turn, then succeeds, and counts
+// the attempts it saw
+type invoker struct {
+	codes    []codes.Code
+	attempts int
+}
+
+func (i *invoker) invoke(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+	i.attempts++
+	if i.attempts <= len(i.codes) {
+		return status.Error(i.codes[i.attempts-1], "failed")
+	}
+	return nil
+}
+
+func TestInterceptorRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		codes        []codes.Code
+		wantAttempts int
+		wantCode     codes.Code
+	}{
+		{
+			name:         "idempotent call recovers",
+			method:       getMethod,
+			codes:        []codes.Code{codes.Unavailable},
+			wantAttempts: 2,
+			wantCode:     codes.OK,
+		},
+		{
+			name:         "idempotent call gives up after max attempts",
+			method:       getMethod,
+			codes:        []codes.Code{codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Unavailable},
+			wantAttempts: 3,
+			wantCode:     codes.ResourceExhausted,
+		},
+		{
+			name:         "idempotent call with a permanent error",
+			method:       getMethod,
+			codes:        []codes.Code{codes.NotFound},
+			wantAttempts: 1,
+			wantCode:     codes.NotFound,
+		},
+		{
+			name:         "idempotent call with an internal error",
+			method:       getMethod,
+			codes:        []codes.Code{codes.Internal},
+			wantAttempts: 1,
+			wantCode:     codes.Internal,
+		},
+		{
+			name:         "non-idempotent call is never retried",
+			method:       createMethod,
+			codes:        []codes.Code{codes.Unavailable},
+			wantAttempts: 1,
+			wantCode:     codes.Unavailable,
+		},
+		{
+			name:         "non-idempotent call is never retried on timeout",
+			method:       createMethod,
+			codes:        []codes.Code{codes.DeadlineExceeded},
+			wantAttempts: 1,
+			wantCode:     codes.DeadlineExceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			intercept := UnaryClientInterceptor("test", testConfig, getMethod)
+			inv := &invoker{codes: tt.codes}
+			err := intercept(context.Background(), tt.method, nil, nil, nil, inv.invoke)
+			if inv.attempts != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", inv.attempts, tt.wantAttempts)
+			}
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("code = %v, want %v", status.Code(err), tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestInterceptorFailsFastWhenBreakerIsOpen(t *testing.T) {
+	cfg := testConfig
+	cfg.BreakerThreshold = 2
+	intercept := UnaryClientInterceptor("test", cfg)
+
+	failing := &invoker{codes: []codes.Code{codes.Unavailable, codes.Unavailable}}
+	for i := 0; i < 2; i++ {
+		intercept(context.Background(), createMethod, nil, nil, nil, failing.invoke)
+	}
+
+	inv := &invoker{}
+	err := intercept(context.Background(), createMethod, nil, nil, nil, inv.invoke)
+	if inv.attempts != 0 {
+		t.Errorf("open breaker let %d calls through", inv.attempts)
+	}
+	if status.Code(err) != codes.Unavailable || !strings.Contains(err.Error(), "SERVICE_UNAVAILABLE") {
+		t.Errorf("error = %v, want SERVICE_UNAVAILABLE", err)
+	}
+}
+
+func TestInterceptorIgnoresRequestErrorsAndCancellation(t *testing.T) {
+	cfg := testConfig
+	cfg.BreakerThreshold = 1
+	intercept := UnaryClientInterceptor("test", cfg, getMethod)
+
+	// Errors about the request don't count against the service
+	intercept(context.Background(), getMethod, nil, nil, nil, (&invoker{codes: []codes.Code{codes.NotFound}}).invoke)
+
+	// Nor does a caller giving up mid-call
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelled := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		cancel()
+		return status.Error(codes.Canceled, "cancelled")
+	}
+	intercept(ctx, getMethod, nil, nil, nil, cancelled)
+
+	inv := &invoker{}
+	if err := intercept(context.Background(), getMethod, nil, nil, nil, inv.invoke); err != nil || inv.attempts != 1 {
+		t.Errorf("call after request errors: err = %v, attempts = %d; want it let through", err, inv.attempts)
+	}
+}
+
+func TestInterceptorBoundsEachAttempt(t *testing.T) {
+	cfg := testConfig
+	cfg.Timeout = 10 * time.Millisecond
+	intercept := UnaryClientInterceptor("test", cfg)
+
+	var deadline time.Time
+	intercept(context.Background(), createMethod, nil, nil, nil, func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		deadline, _ = ctx.Deadline()
+		return nil
+	})
+	if deadline.IsZero() || time.Until(deadline) > cfg.Timeout {
+		t.Errorf("attempt deadline = %v, want within %v", deadline, cfg.Timeout)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	cfg := config.Resilience{BackoffBase: 10 * time.Millisecond, BackoffMax: 25 * time.Millisecond}
+	for retry, limit := range map[int]time.Duration{1: 10 * time.Millisecond, 2: 20 * time.Millisecond, 3: 25 * time.Millisecond, 40: 25 * time.Millisecond} {
+		for i := 0; i < 100; i++ {
+			if d := backoff(cfg, retry); d <= 0 || d > limit {
+				t.Fatalf("backoff(%d) = %v, want in (0, %v]", retry, d, limit)
+			}
+		}
+	}
+	if d := backoff(config.Resilience{}, 1); d != 0 {
+		t.Errorf("backoff without a base = %v, want 0", d)
+	}
+}
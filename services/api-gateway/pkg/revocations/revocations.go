@@ -0,0 +1,163 @@
+// Package revocations lets the gateway turn away access tokens that were
+// revoked before they expired: single tokens revoked at logout, and every
+// token below a user's token version after they logged out everywhere.
+// user-service announces each revocation as it happens, and a periodic
+// resync covers messages that were lost or sent before the gateway started.
+package revocations
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/wutthichod/sa-connext/services/api-gateway/clients"
+	"github.com/wutthichod/sa-connext/shared/auth"
+	"github.com/wutthichod/sa-connext/shared/contracts"
+	"github.com/wutthichod/sa-connext/shared/correlation"
+	"github.com/wutthichod/sa-connext/shared/messaging"
+	pb "github.com/wutthichod/sa-connext/shared/proto/user"
+)
+
+type List struct {
+	client   *clients.UserServiceClient
+	mutex    sync.RWMutex
+	tokens   map[string]time.Time // jti to when the token expires
+	versions map[uint]uint
+}
+
+func New(client *clients.UserServiceClient) *List {
+	return &List{client: client, tokens: make(map[string]time.Time), versions: make(map[uint]uint)}
+}
+
+// IsRevoked reports whether the token was revoked as of the last update
+func (l *List) IsRevoked(claims *auth.Claims) bool {
+	if l == nil {
+		return false
+	}
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	if claims.TokenVersion < l.versions[claims.UserID] {
+		return true
+	}
+	_, ok := l.tokens[claims.ID]
+	return ok && claims.ID != ""
+}
+
+// Refresh merges user-service's list into this one. Revocations are never
+// undone, so merging rather than replacing keeps those announced while the
+// request was in flight, which the snapshot may predate.
+func (l *List) Refresh(ctx context.Context) error {
+	res, err := l.client.GetRevocations(ctx, &pb.GetRevocationsRequest{})
+	if err != nil {
+		return err
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	now := time.Now()
+	l.dropExpired(now)
+	for _, token := range res.Tokens {
+		if expiresAt := time.Unix(token.ExpiresAt, 0); expiresAt.After(now) {
+			l.tokens[token.Jti] = expiresAt
+		}
+	}
+	for id, version := range res.TokenVersions {
+		if userID, err := strconv.ParseUint(id, 10, 64); err == nil && uint(version) > l.versions[uint(userID)] {
+			l.versions[uint(userID)] = uint(version)
+		}
+	}
+	return nil
+}
+
+// KeepFresh refreshes the list now and then every interval until ctx is
+// done. Until the first refresh succeeds only announced revocations apply.
+func (l *List) KeepFresh(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := l.Refresh(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("revocations: failed to refresh: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ListenForChanges applies revocations as user-service announces them. Every
+// gateway instance gets its own queue.
+func (l *List) ListenForChanges(rb *messaging.RabbitMQ) error {
+	if err := rb.DeclareExchange(contracts.UserExchange, "topic", true); err != nil {
+		return err
+	}
+	queue, err := rb.DeclareExclusiveQueue()
+	if err != nil {
+		return err
+	}
+	if err := rb.BindQueue(queue, contracts.UserExchange, contracts.UserTokenRevokedRouting); err != nil {
+		return err
+	}
+	if err := rb.BindQueue(queue, contracts.UserExchange, contracts.UserLogoutEverywhereRouting); err != nil {
+		return err
+	}
+
+	// Both events carry user_id; a jti means one token was revoked, a
+	// token_version that the user logged out everywhere
+	return rb.ConsumeMessages(queue, func(ctx context.Context, msg []byte) error {
+		var event struct {
+			contracts.UserTokenRevokedEvent
+			TokenVersion uint `json:"token_version"`
+		}
+		if err := json.Unmarshal(msg, &event); err != nil {
+			correlation.Printf(ctx, "revocations: dropping malformed event: %v", err)
+			return nil
+		}
+		userID, err := strconv.ParseUint(event.UserID, 10, 64)
+		if err != nil {
+			correlation.Printf(ctx, "revocations: dropping event for user %q", event.UserID)
+			return nil
+		}
+		if event.JTI != "" {
+			l.revokeToken(event.JTI, event.ExpiresAt)
+		}
+		if event.TokenVersion > 0 {
+			l.raiseVersion(uint(userID), event.TokenVersion)
+		}
+		return nil
+	})
+}
+
+func (l *List) revokeToken(jti string, expiresAt time.Time) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	now := time.Now()
+	// Expired entries are dropped here as well as on refresh, so the list
+	// can't grow between resyncs
+	l.dropExpired(now)
+	if expiresAt.After(now) {
+		l.tokens[jti] = expiresAt
+	}
+}
+
+// dropExpired forgets tokens that are no longer accepted anyway. The caller
+// holds the lock.
+func (l *List) dropExpired(now time.Time) {
+	for id, exp := range l.tokens {
+		if !exp.After(now) {
+			delete(l.tokens, id)
+		}
+	}
+}
+
+// raiseVersion never lowers a version, in case events arrive out of order
+func (l *List) raiseVersion(userID, version uint) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if version > l.versions[userID] {
+		l.versions[userID] = version
+	}
+}
@@ -0,0 +1,104 @@
+// Package suspensions tracks the users an admin has suspended, so the gateway
+// can turn their tokens away before they expire. Changes arrive as user
+// events; a periodic resync covers events that were lost or sent before the
+// gateway started.
+package suspensions
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/wutthichod/sa-connext/services/api-gateway/clients"
+	"github.com/wutthichod/sa-connext/shared/contracts"
+	"github.com/wutthichod/sa-connext/shared/correlation"
+	"github.com/wutthichod/sa-connext/shared/messaging"
+	pb "github.com/wutthichod/sa-connext/shared/proto/user"
+)
+
+type List struct {
+	client *clients.UserServiceClient
+	mutex  sync.RWMutex
+	ids    map[uint]struct{}
+}
+
+func New(client *clients.UserServiceClient) *List {
+	return &List{client: client, ids: make(map[uint]struct{})}
+}
+
+// IsSuspended reports whether the user was suspended as of the last update
+func (l *List) IsSuspended(userID uint) bool {
+	if l == nil {
+		return false
+	}
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	_, ok := l.ids[userID]
+	return ok
+}
+
+// Refresh replaces the list with user-service's
+func (l *List) Refresh(ctx context.Context) error {
+	res, err := l.client.GetSuspendedUserIds(ctx, &pb.GetSuspendedUserIdsRequest{})
+	if err != nil {
+		return err
+	}
+	ids := make(map[uint]struct{}, len(res.UserIds))
+	for _, id := range res.UserIds {
+		if userID, err := strconv.ParseUint(id, 10, 64); err == nil {
+			ids[uint(userID)] = struct{}{}
+		}
+	}
+	l.mutex.Lock()
+	l.ids = ids
+	l.mutex.Unlock()
+	return nil
+}
+
+// KeepFresh refreshes the list now and then every interval until ctx is done.
+// Until the first refresh succeeds nobody is treated as suspended.
+func (l *List) KeepFresh(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := l.Refresh(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("suspensions: failed to refresh: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ListenForChanges picks up suspensions and reinstatements as user-service
+// announces them. Every gateway instance gets its own queue.
+func (l *List) ListenForChanges(rb *messaging.RabbitMQ) error {
+	if err := rb.DeclareExchange(contracts.UserExchange, "topic", true); err != nil {
+		return err
+	}
+	queue, err := rb.DeclareExclusiveQueue()
+	if err != nil {
+		return err
+	}
+	for _, routingKey := range []string{contracts.UserSuspendedRouting, contracts.UserReinstatedRouting} {
+		if err := rb.BindQueue(queue, contracts.UserExchange, routingKey); err != nil {
+			return err
+		}
+	}
+
+	// Suspensions are rare, so rather than telling the two events apart the
+	// whole list is read again, which also keeps quick suspend/reinstate
+	// pairs in order
+	return rb.ConsumeMessages(queue, func(ctx context.Context, msg []byte) error {
+		if err := l.Refresh(ctx); err != nil {
+			// The periodic refresh catches up; requeueing would only retry
+			// against the same unavailable service
+			correlation.Printf(ctx, "suspensions: failed to refresh after a change: %v", err)
+		}
+		return nil
+	})
+}
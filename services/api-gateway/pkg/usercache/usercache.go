@@ -0,0 +1,163 @@
+package usercache
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/wutthichod/sa-connext/services/api-gateway/clients"
+	"github.com/wutthichod/sa-connext/shared/contracts"
+	"github.com/wutthichod/sa-connext/shared/correlation"
+	"github.com/wutthichod/sa-connext/shared/messaging"
+	pb "github.com/wutthichod/sa-connext/shared/proto/user"
+)
+
+// batchSize matches the most IDs user-service accepts per GetUsersByIds call
+const batchSize = 200
+
+// UnknownName is shown for users that no longer exist or could not be loaded
+const UnknownName = "Unknown"
+
+type entry struct {
+	name      string // empty when the user does not exist or has no username
+	expiresAt time.Time
+}
+
+// Cache is a short-lived, in-memory cache of usernames. Misses are fetched
+// in batches, and entries are dropped as soon as user-service announces a
+// profile change.
+type Cache struct {
+	client  *clients.UserServiceClient
+	ttl     time.Duration
+	mutex   sync.RWMutex
+	entries map[string]entry
+}
+
+func New(client *clients.UserServiceClient, ttl time.Duration) *Cache {
+	return &Cache{
+		client:  client,
+		ttl:     ttl,
+		entries: make(map[string]entry),
+	}
+}
+
+// GetNames maps ids to usernames, using UnknownName for users that cannot be
+// resolved. Lookup errors are logged rather than failing the caller.
+func (c *Cache) GetNames(ctx context.Context, ids []string) map[string]string {
+	names := make(map[string]string, len(ids))
+	var missing []string
+
+	now := time.Now()
+	c.mutex.RLock()
+	for _, id := range ids {
+		if _, seen := names[id]; seen || slices.Contains(missing, id) {
+			continue
+		}
+		cached, ok := c.entries[id]
+		if !ok || now.After(cached.expiresAt) {
+			missing = append(missing, id)
+			continue
+		}
+		names[id] = cached.name
+	}
+	c.mutex.RUnlock()
+
+	if err := c.fetch(ctx, missing, names); err != nil {
+		correlation.Printf(ctx, "usercache: failed to fetch users: %v", err)
+	}
+
+	for _, id := range ids {
+		if names[id] == "" {
+			names[id] = UnknownName
+		}
+	}
+	return names
+}
+
+// fetch loads the names of ids from user-service into names and the cache
+func (c *Cache) fetch(ctx context.Context, ids []string, names map[string]string) error {
+	for start := 0; start < len(ids); start += batchSize {
+		batch := ids[start:min(start+batchSize, len(ids))]
+		res, err := c.client.GetUsersByIds(ctx, &pb.GetUsersByIdsRequest{UserIds: batch})
+		if err != nil {
+			return err
+		}
+
+		fetched := make(map[string]string, len(res.Users))
+		for _, user := range res.Users {
+			fetched[user.UserId] = user.Username
+			names[user.UserId] = user.Username
+		}
+
+		expiresAt := time.Now().Add(c.ttl)
+		c.mutex.Lock()
+		for _, id := range batch {
+			// Remember unknown IDs too, so they don't hit user-service every time
+			c.entries[id] = entry{name: fetched[id], expiresAt: expiresAt}
+		}
+		c.mutex.Unlock()
+	}
+	return nil
+}
+
+// Sweep drops expired entries, which are otherwise only replaced when the
+// same user is looked up again
+func (c *Cache) Sweep() {
+	now := time.Now()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for id, cached := range c.entries {
+		if now.After(cached.expiresAt) {
+			delete(c.entries, id)
+		}
+	}
+}
+
+// KeepClean sweeps the cache every interval until ctx is done
+func (c *Cache) KeepClean(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.Sweep()
+		}
+	}
+}
+
+// Invalidate drops the cached profile of a single user
+func (c *Cache) Invalidate(userID string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.entries, userID)
+}
+
+// ListenForUpdates subscribes to profile update events so stale entries are
+// dropped before their TTL runs out. Every gateway instance gets its own queue.
+func (c *Cache) ListenForUpdates(rb *messaging.RabbitMQ) error {
+	if err := rb.DeclareExchange(contracts.UserExchange, "topic", true); err != nil {
+		return err
+	}
+	queue, err := rb.DeclareExclusiveQueue()
+	if err != nil {
+		return err
+	}
+	if err := rb.BindQueue(queue, contracts.UserExchange, contracts.UserProfileUpdatedRouting); err != nil {
+		return err
+	}
+
+	return rb.ConsumeMessages(queue, func(ctx context.Context, msg []byte) error {
+		var event contracts.UserProfileUpdatedEvent
+		if err := json.Unmarshal(msg, &event); err != nil {
+			// A malformed event will never parse, so don't requeue it
+			correlation.Printf(ctx, "usercache: failed to unmarshal profile update: %v", err)
+			return nil
+		}
+		c.Invalidate(event.UserID)
+		return nil
+	})
+}
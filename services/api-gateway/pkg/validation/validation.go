@@ -0,0 +1,180 @@
+// Package validation binds request bodies into DTOs and enforces their
+// `validate` tags, so handlers reject bad input before calling a service.
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/wutthichod/sa-connext/shared/contracts"
+	grpcerrors "github.com/wutthichod/sa-connext/shared/errors"
+)
+
+// FieldError describes one rule a field failed
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// Error is returned by BindBody when the body is malformed or invalid
+type Error struct {
+	Message string
+	Fields  []FieldError // empty when the body could not be parsed at all
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	// Report fields by their JSON name, which is what clients send
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+	v.RegisterValidation("rfc3339", func(fl validator.FieldLevel) bool {
+		_, err := time.Parse(time.RFC3339, fl.Field().String())
+		return err == nil
+	})
+	v.RegisterValidation("objectid", func(fl validator.FieldLevel) bool {
+		value := fl.Field().String()
+		return len(value) == 24 && strings.Trim(value, "0123456789abcdefABCDEF") == ""
+	})
+	return v
+}
+
+// BindBody parses the JSON body into out, a pointer to a DTO, and validates it
+func BindBody(c *fiber.Ctx, out any) error {
+	if err := c.BodyParser(out); err != nil {
+		return &Error{Message: "invalid json format"}
+	}
+	return Struct(out)
+}
+
+// Struct validates a DTO that was filled in some other way
+func Struct(dto any) error {
+	err := validate.Struct(dto)
+	if err == nil {
+		return nil
+	}
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return &Error{Message: err.Error()}
+	}
+
+	fields := make([]FieldError, 0, len(validationErrors))
+	for _, fe := range validationErrors {
+		fields = append(fields, FieldError{
+			Field:   fieldPath(fe),
+			Rule:    fe.Tag(),
+			Param:   fe.Param(),
+			Message: message(fe),
+		})
+	}
+	return &Error{Message: "validation failed", Fields: fields}
+}
+
+// Respond writes err as a 400 listing every failing field. Errors that did
+// not come from BindBody or Struct are passed back to Fiber.
+func Respond(c *fiber.Ctx, err error) error {
+	var validationErr *Error
+	if !errors.As(err, &validationErr) {
+		return err
+	}
+
+	data := map[string]any{"error_code": grpcerrors.CodeInvalidInput}
+	if len(validationErr.Fields) > 0 {
+		data["error_code"] = grpcerrors.CodeValidationError
+		data["fields"] = validationErr.Fields
+	}
+	return c.Status(fiber.StatusBadRequest).JSON(contracts.Resp{
+		Success:    false,
+		StatusCode: fiber.StatusBadRequest,
+		Message:    validationErr.Message,
+		Data:       data,
+	})
+}
+
+// fieldPath drops the DTO's own name, e.g. "contact.email" rather than
+// "RegisterRequest.contact.email"
+func fieldPath(fe validator.FieldError) string {
+	_, path, found := strings.Cut(fe.Namespace(), ".")
+	if !found {
+		return fe.Field()
+	}
+	return path
+}
+
+func message(fe validator.FieldError) string {
+	isList := fe.Kind() == reflect.Slice || fe.Kind() == reflect.Map
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "required_without":
+		return fmt.Sprintf("is required when %s is not set", snakeCase(fe.Param()))
+	case "email":
+		return "must be a valid email address"
+	case "min":
+		if isList {
+			return fmt.Sprintf("must have at least %s items", fe.Param())
+		}
+		return fmt.Sprintf("must be at least %s characters", fe.Param())
+	case "max":
+		if isList {
+			return fmt.Sprintf("must have at most %s items", fe.Param())
+		}
+		return fmt.Sprintf("must be at most %s characters", fe.Param())
+	case "len":
+		return fmt.Sprintf("must be exactly %s characters", fe.Param())
+	case "oneof":
+		return fmt.Sprintf("must be one of: %s", strings.ReplaceAll(fe.Param(), " ", ", "))
+	case "numeric":
+		return "must be a number"
+	case "alphanum":
+		return "must contain only letters and digits"
+	case "base64":
+		return "must be base64 encoded"
+	case "rfc3339":
+		return "must be an RFC3339 timestamp, e.g. 2025-01-02T15:04:05Z"
+	case "objectid":
+		return "must be a 24 character hex ID"
+	case "unique":
+		return "must not contain duplicates"
+	default:
+		return fmt.Sprintf("failed the %s rule", fe.Tag())
+	}
+}
+
+// snakeCase turns a Go field name from a rule parameter into the JSON name
+// used by the DTOs, e.g. "SendAt" into "send_at"
+func snakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,110 @@
+// Package versioning mounts the gateway's public routes under version
+// prefixes such as /v1 and marks old versions and paths as deprecated with
+// the Deprecation (RFC 9745) and Sunset (RFC 8594) headers.
+package versioning
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wutthichod/sa-connext/services/api-gateway/pkg/openapi"
+)
+
+// Registrar is a handler that can register and describe its routes
+type Registrar interface {
+	RegisterRoutes(router fiber.Router)
+	Docs() []openapi.Route
+}
+
+// Deprecation describes when a version or path stopped being recommended
+type Deprecation struct {
+	Since     time.Time // sent as the Deprecation header
+	Sunset    time.Time // sent as the Sunset header; zero when no removal date is set
+	Successor string    // path prefix of the replacement, linked with rel="successor-version"
+}
+
+// Mount registers the routes of every handler under prefix, e.g. "/v1", and
+// returns their documentation with the prefix applied. A non-nil deprecation
+// is announced on every response of the version.
+func Mount(app *fiber.App, prefix string, deprecation *Deprecation, handlers ...Registrar) []openapi.Route {
+	var router fiber.Router = app.Group(prefix)
+	if deprecation != nil {
+		router = app.Group(prefix, Deprecate(*deprecation))
+	}
+
+	var docs []openapi.Route
+	for _, handler := range handlers {
+		handler.RegisterRoutes(router)
+		for _, route := range handler.Docs() {
+			route.Path = prefix + route.Path
+			route.Deprecated = deprecation != nil
+			docs = append(docs, route)
+		}
+	}
+	return docs
+}
+
+// Deprecate sets the deprecation headers on every response it wraps
+func Deprecate(d Deprecation) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		d.setHeaders(c)
+		return c.Next()
+	}
+}
+
+func (d Deprecation) setHeaders(c *fiber.Ctx) {
+	c.Set("Deprecation", fmt.Sprintf("@%d", d.Since.Unix()))
+	if !d.Sunset.IsZero() {
+		c.Set("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+	}
+	if d.Successor != "" {
+		c.Append(fiber.HeaderLink, fmt.Sprintf(`<%s>; rel="successor-version"`, d.Successor+Strip(c.Path())))
+	}
+}
+
+// Alias serves requests to the unversioned prefixes, e.g. "/users", from the
+// routes under version, so clients that predate versioning keep working
+// through a transition period. Aliased responses carry the deprecation
+// headers pointing at the versioned path.
+func Alias(version string, deprecation Deprecation, prefixes ...string) fiber.Handler {
+	deprecation.Successor = version
+
+	return func(c *fiber.Ctx) error {
+		path := c.Path()
+		if !hasAnyPrefix(path, prefixes) {
+			return c.Next()
+		}
+		deprecation.setHeaders(c)
+		c.Path(version + path)
+		return c.Next()
+	}
+}
+
+// Strip removes a leading version segment such as "/v1", so policies keyed
+// by path apply to every version of a route
+func Strip(path string) string {
+	rest, ok := strings.CutPrefix(path, "/v")
+	if !ok {
+		return path
+	}
+	digits := len(rest) - len(strings.TrimLeft(rest, "0123456789"))
+	if digits == 0 || (digits < len(rest) && rest[digits] != '/') {
+		return path
+	}
+	if digits == len(rest) {
+		return "/"
+	}
+	return rest[digits:]
+}
+
+func hasAnyPrefix(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -16,10 +16,75 @@ type Chat struct {
 	UpdatedAt     time.Time          `bson:"updated_at"`
 }
 
+// Message types
+const (
+	MessageTypeText = "text"
+	MessageTypePoll = "poll"
+)
+
 type Message struct {
+	ID        primitive.ObjectID  `bson:"_id,omitempty" json:"_id"`
+	ChatID    primitive.ObjectID  `bson:"chat_id" json:"chat_id"`
+	SenderID  string              `bson:"sender_id" json:"sender_id"`
+	Message   string              `bson:"message" json:"message"`
+	Type      string              `bson:"type,omitempty" json:"type,omitempty"` // empty means text
+	PollID    *primitive.ObjectID `bson:"poll_id,omitempty" json:"poll_id,omitempty"`
+	Encrypted bool                `bson:"encrypted,omitempty" json:"encrypted,omitempty"` // Message holds opaque ciphertext
+	CreatedAt time.Time           `bson:"created_at" json:"created_at"`
+}
+
+// Scheduled message statuses
+const (
+	ScheduledStatusPending   = "pending"
+	ScheduledStatusSending   = "sending"
+	ScheduledStatusSent      = "sent"
+	ScheduledStatusCancelled = "cancelled"
+	ScheduledStatusFailed    = "failed"
+)
+
+type ScheduledMessage struct {
+	ID        primitive.ObjectID  `bson:"_id,omitempty" json:"_id"`
+	ChatID    primitive.ObjectID  `bson:"chat_id" json:"chat_id"`
+	SenderID  string              `bson:"sender_id" json:"sender_id"`
+	Message   string              `bson:"message" json:"message"`
+	SendAt    time.Time           `bson:"send_at" json:"send_at"`
+	Status    string              `bson:"status" json:"status"`
+	MessageID *primitive.ObjectID `bson:"message_id,omitempty" json:"message_id,omitempty"` // set once delivered
+	Error     string              `bson:"error,omitempty" json:"error,omitempty"`
+	CreatedAt time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time           `bson:"updated_at" json:"updated_at"`
+}
+
+type Poll struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
+	ChatID         primitive.ObjectID `bson:"chat_id" json:"chat_id"`
+	MessageID      primitive.ObjectID `bson:"message_id" json:"message_id"`
+	CreatorID      string             `bson:"creator_id" json:"creator_id"`
+	Question       string             `bson:"question" json:"question"`
+	Options        []PollOption       `bson:"options" json:"options"`
+	MultipleChoice bool               `bson:"multiple_choice" json:"multiple_choice"`
+	Anonymous      bool               `bson:"anonymous" json:"anonymous"`
+	ClosesAt       *time.Time         `bson:"closes_at,omitempty" json:"closes_at,omitempty"`
+	Closed         bool               `bson:"closed" json:"closed"`
+	ClosedAt       *time.Time         `bson:"closed_at,omitempty" json:"closed_at,omitempty"`
+	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// IsClosed reports whether the poll no longer accepts votes
+func (p *Poll) IsClosed(now time.Time) bool {
+	return p.Closed || (p.ClosesAt != nil && !now.Before(*p.ClosesAt))
+}
+
+type PollOption struct {
+	ID   string `bson:"id" json:"id"`
+	Text string `bson:"text" json:"text"`
+}
+
+// PollVote holds one user's current choice; there is at most one per poll and user
+type PollVote struct {
 	ID        primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
-	ChatID    primitive.ObjectID `bson:"chat_id" json:"chat_id"`
-	SenderID  string             `bson:"sender_id" json:"sender_id"`
-	Message   string             `bson:"message" json:"message"`
-	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	PollID    primitive.ObjectID `bson:"poll_id" json:"poll_id"`
+	UserID    string             `bson:"user_id" json:"user_id"`
+	OptionIDs []string           `bson:"option_ids" json:"option_ids"`
+	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
 }
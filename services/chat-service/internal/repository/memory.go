@@ -0,0 +1,365 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/wutthichod/sa-connext/services/chat-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryRepository implements ChatRepository in process memory. It is meant
+// for local runs and tests; data is lost when the process exits.
+type memoryRepository struct {
+	mutex     sync.RWMutex
+	chats     map[primitive.ObjectID]*models.Chat
+	messages  map[primitive.ObjectID]*models.Message
+	scheduled map[primitive.ObjectID]*models.ScheduledMessage
+	polls     map[primitive.ObjectID]*models.Poll
+	votes     map[primitive.ObjectID]map[string]*models.PollVote // pollId -> userId -> vote
+}
+
+// NewMemoryRepository creates an empty in-memory repository
+func NewMemoryRepository() ChatRepository {
+	return &memoryRepository{
+		chats:     make(map[primitive.ObjectID]*models.Chat),
+		messages:  make(map[primitive.ObjectID]*models.Message),
+		scheduled: make(map[primitive.ObjectID]*models.ScheduledMessage),
+		polls:     make(map[primitive.ObjectID]*models.Poll),
+		votes:     make(map[primitive.ObjectID]map[string]*models.PollVote),
+	}
+}
+
+func (r *memoryRepository) CreateChat(ctx context.Context, chat *models.Chat) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if chat.ID.IsZero() {
+		chat.ID = primitive.NewObjectID()
+	}
+	r.chats[chat.ID] = copyChat(chat)
+	return nil
+}
+
+func (r *memoryRepository) GetChatByID(ctx context.Context, id primitive.ObjectID) (*models.Chat, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	chat, ok := r.chats[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return copyChat(chat), nil
+}
+
+func (r *memoryRepository) FindDirectChat(ctx context.Context, userA, userB string) (*models.Chat, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	for _, chat := range r.sortedChats() {
+		if !chat.IsGroup && slices.Contains(chat.Participants, userA) && slices.Contains(chat.Participants, userB) {
+			return copyChat(chat), nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memoryRepository) GetChatsForUser(ctx context.Context, userID string) ([]*models.Chat, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	var chats []*models.Chat
+	for _, chat := range r.sortedChats() {
+		if chat.IsGroup || slices.Contains(chat.Participants, userID) {
+			chats = append(chats, copyChat(chat))
+		}
+	}
+	return chats, nil
+}
+
+func (r *memoryRepository) AddParticipant(ctx context.Context, chatID primitive.ObjectID, userID string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if chat, ok := r.chats[chatID]; ok {
+		chat.Participants = append(chat.Participants, userID)
+	}
+	return nil
+}
+
+func (r *memoryRepository) TouchChat(ctx context.Context, chatID primitive.ObjectID, at time.Time) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if chat, ok := r.chats[chatID]; ok {
+		chat.LastMessageAt = &at
+		chat.UpdatedAt = at
+	}
+	return nil
+}
+
+func (r *memoryRepository) CreateMessage(ctx context.Context, message *models.Message) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if message.ID.IsZero() {
+		message.ID = primitive.NewObjectID()
+	}
+	stored := *message
+	r.messages[message.ID] = &stored
+	return nil
+}
+
+func (r *memoryRepository) GetMessages(ctx context.Context, filter MessageFilter) ([]*models.Message, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	var messages []*models.Message
+	for _, message := range r.messages {
+		if filter.matches(message) {
+			m := *message
+			messages = append(messages, &m)
+		}
+	}
+	sort.SliceStable(messages, func(i, j int) bool {
+		if messages[i].CreatedAt.Equal(messages[j].CreatedAt) {
+			return messages[i].ID.Hex() < messages[j].ID.Hex()
+		}
+		return messages[i].CreatedAt.Before(messages[j].CreatedAt)
+	})
+	return messages, nil
+}
+
+func (r *memoryRepository) DeleteMessages(ctx context.Context, filter MessageFilter) (int64, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	var deleted int64
+	for id, message := range r.messages {
+		if filter.matches(message) {
+			delete(r.messages, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (f MessageFilter) matches(message *models.Message) bool {
+	if f.ChatID != nil && message.ChatID != *f.ChatID {
+		return false
+	}
+	return f.SenderID == "" || message.SenderID == f.SenderID
+}
+
+func (r *memoryRepository) CreateScheduledMessage(ctx context.Context, scheduled *models.ScheduledMessage) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if scheduled.ID.IsZero() {
+		scheduled.ID = primitive.NewObjectID()
+	}
+	stored := *scheduled
+	r.scheduled[scheduled.ID] = &stored
+	return nil
+}
+
+func (r *memoryRepository) GetScheduledMessage(ctx context.Context, id primitive.ObjectID, senderID string) (*models.ScheduledMessage, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	scheduled, ok := r.scheduled[id]
+	if !ok || scheduled.SenderID != senderID {
+		return nil, ErrNotFound
+	}
+	s := *scheduled
+	return &s, nil
+}
+
+func (r *memoryRepository) GetPendingScheduledMessages(ctx context.Context, senderID string, chatID *primitive.ObjectID) ([]*models.ScheduledMessage, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	var result []*models.ScheduledMessage
+	for _, scheduled := range r.scheduled {
+		if scheduled.SenderID != senderID || scheduled.Status != models.ScheduledStatusPending {
+			continue
+		}
+		if chatID != nil && scheduled.ChatID != *chatID {
+			continue
+		}
+		s := *scheduled
+		result = append(result, &s)
+	}
+	sortBySendAt(result)
+	return result, nil
+}
+
+func (r *memoryRepository) UpdatePendingScheduledMessage(ctx context.Context, id primitive.ObjectID, senderID string, update ScheduledMessageUpdate) (*models.ScheduledMessage, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	scheduled, ok := r.scheduled[id]
+	if !ok || scheduled.SenderID != senderID || scheduled.Status != models.ScheduledStatusPending {
+		return nil, ErrNotFound
+	}
+	if update.Message != nil {
+		scheduled.Message = *update.Message
+	}
+	if update.SendAt != nil {
+		scheduled.SendAt = *update.SendAt
+	}
+	scheduled.UpdatedAt = time.Now()
+	s := *scheduled
+	return &s, nil
+}
+
+func (r *memoryRepository) CancelPendingScheduledMessage(ctx context.Context, id primitive.ObjectID, senderID string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	scheduled, ok := r.scheduled[id]
+	if !ok || scheduled.SenderID != senderID || scheduled.Status != models.ScheduledStatusPending {
+		return ErrNotFound
+	}
+	scheduled.Status = models.ScheduledStatusCancelled
+	scheduled.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r *memoryRepository) ClaimDueScheduledMessage(ctx context.Context, now, staleBefore time.Time) (*models.ScheduledMessage, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	var due []*models.ScheduledMessage
+	for _, scheduled := range r.scheduled {
+		pending := scheduled.Status == models.ScheduledStatusPending && !scheduled.SendAt.After(now)
+		stale := scheduled.Status == models.ScheduledStatusSending && !scheduled.UpdatedAt.After(staleBefore)
+		if pending || stale {
+			due = append(due, scheduled)
+		}
+	}
+	if len(due) == 0 {
+		return nil, ErrNotFound
+	}
+	sortBySendAt(due)
+	claimed := due[0]
+	claimed.Status = models.ScheduledStatusSending
+	claimed.UpdatedAt = now
+	s := *claimed
+	return &s, nil
+}
+
+func (r *memoryRepository) FinishScheduledMessage(ctx context.Context, id primitive.ObjectID, result ScheduledMessageResult) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	scheduled, ok := r.scheduled[id]
+	if !ok {
+		return nil
+	}
+	scheduled.Status = result.Status
+	scheduled.UpdatedAt = time.Now()
+	if result.MessageID != nil {
+		messageID := *result.MessageID
+		scheduled.MessageID = &messageID
+	}
+	if result.Error != "" {
+		scheduled.Error = result.Error
+	}
+	return nil
+}
+
+func (r *memoryRepository) CreatePoll(ctx context.Context, poll *models.Poll) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if poll.ID.IsZero() {
+		poll.ID = primitive.NewObjectID()
+	}
+	r.polls[poll.ID] = copyPoll(poll)
+	return nil
+}
+
+func (r *memoryRepository) GetPollByID(ctx context.Context, id primitive.ObjectID) (*models.Poll, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	poll, ok := r.polls[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return copyPoll(poll), nil
+}
+
+func (r *memoryRepository) ClosePoll(ctx context.Context, id primitive.ObjectID, at time.Time) (bool, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	poll, ok := r.polls[id]
+	if !ok || poll.Closed {
+		return false, nil
+	}
+	poll.Closed = true
+	poll.ClosedAt = &at
+	return true, nil
+}
+
+func (r *memoryRepository) GetExpiredOpenPolls(ctx context.Context, now time.Time) ([]*models.Poll, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	var polls []*models.Poll
+	for _, poll := range r.polls {
+		if !poll.Closed && poll.ClosesAt != nil && !poll.ClosesAt.After(now) {
+			polls = append(polls, copyPoll(poll))
+		}
+	}
+	return polls, nil
+}
+
+func (r *memoryRepository) SetVote(ctx context.Context, pollID primitive.ObjectID, userID string, optionIDs []string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	votes, ok := r.votes[pollID]
+	if !ok {
+		votes = make(map[string]*models.PollVote)
+		r.votes[pollID] = votes
+	}
+	vote, ok := votes[userID]
+	if !ok {
+		vote = &models.PollVote{ID: primitive.NewObjectID(), PollID: pollID, UserID: userID}
+		votes[userID] = vote
+	}
+	vote.OptionIDs = slices.Clone(optionIDs)
+	vote.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r *memoryRepository) DeleteVote(ctx context.Context, pollID primitive.ObjectID, userID string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.votes[pollID], userID)
+	return nil
+}
+
+func (r *memoryRepository) GetVotes(ctx context.Context, pollID primitive.ObjectID) ([]*models.PollVote, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	var votes []*models.PollVote
+	for _, vote := range r.votes[pollID] {
+		v := *vote
+		v.OptionIDs = slices.Clone(vote.OptionIDs)
+		votes = append(votes, &v)
+	}
+	sort.Slice(votes, func(i, j int) bool { return votes[i].ID.Hex() < votes[j].ID.Hex() })
+	return votes, nil
+}
+
+// sortedChats returns chats in creation order, like a collection scan
+func (r *memoryRepository) sortedChats() []*models.Chat {
+	chats := make([]*models.Chat, 0, len(r.chats))
+	for _, chat := range r.chats {
+		chats = append(chats, chat)
+	}
+	sort.Slice(chats, func(i, j int) bool { return chats[i].ID.Hex() < chats[j].ID.Hex() })
+	return chats
+}
+
+func sortBySendAt(scheduled []*models.ScheduledMessage) {
+	sort.Slice(scheduled, func(i, j int) bool { return scheduled[i].SendAt.Before(scheduled[j].SendAt) })
+}
+
+func copyChat(chat *models.Chat) *models.Chat {
+	c := *chat
+	c.Participants = slices.Clone(chat.Participants)
+	return &c
+}
+
+func copyPoll(poll *models.Poll) *models.Poll {
+	p := *poll
+	p.Options = slices.Clone(poll.Options)
+	return &p
+}
@@ -0,0 +1,324 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/wutthichod/sa-connext/services/chat-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// mongoRepository implements ChatRepository on top of MongoDB
+type mongoRepository struct {
+	db *mongo.Database
+}
+
+// NewMongoRepository creates a repository backed by the given database
+func NewMongoRepository(db *mongo.Database) ChatRepository {
+	return &mongoRepository{db: db}
+}
+
+func (r *mongoRepository) chats() *mongo.Collection     { return r.db.Collection("chats") }
+func (r *mongoRepository) messages() *mongo.Collection  { return r.db.Collection("messages") }
+func (r *mongoRepository) scheduled() *mongo.Collection { return r.db.Collection("scheduled_messages") }
+func (r *mongoRepository) polls() *mongo.Collection     { return r.db.Collection("polls") }
+func (r *mongoRepository) pollVotes() *mongo.Collection { return r.db.Collection("poll_votes") }
+
+func (r *mongoRepository) CreateChat(ctx context.Context, chat *models.Chat) error {
+	res, err := r.chats().InsertOne(ctx, chat)
+	if err != nil {
+		return err
+	}
+	chat.ID = res.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *mongoRepository) GetChatByID(ctx context.Context, id primitive.ObjectID) (*models.Chat, error) {
+	return findOne[models.Chat](ctx, r.chats(), bson.M{"_id": id})
+}
+
+func (r *mongoRepository) FindDirectChat(ctx context.Context, userA, userB string) (*models.Chat, error) {
+	filter := bson.M{
+		"is_group": false,
+		"participants": bson.M{
+			"$all": []string{userA, userB},
+		},
+	}
+	return findOne[models.Chat](ctx, r.chats(), filter)
+}
+
+func (r *mongoRepository) GetChatsForUser(ctx context.Context, userID string) ([]*models.Chat, error) {
+	filter := bson.M{
+		"$or": []bson.M{
+			{"is_group": true},
+			{"participants": userID},
+		},
+	}
+	return findAll[models.Chat](ctx, r.chats(), filter)
+}
+
+func (r *mongoRepository) AddParticipant(ctx context.Context, chatID primitive.ObjectID, userID string) error {
+	update := bson.M{
+		"$push": bson.M{
+			"participants": userID,
+		},
+	}
+	_, err := r.chats().UpdateByID(ctx, chatID, update)
+	return err
+}
+
+func (r *mongoRepository) TouchChat(ctx context.Context, chatID primitive.ObjectID, at time.Time) error {
+	update := bson.M{
+		"$set": bson.M{
+			"last_message_at": at,
+			"updated_at":      at,
+		},
+	}
+	_, err := r.chats().UpdateByID(ctx, chatID, update)
+	return err
+}
+
+func (r *mongoRepository) CreateMessage(ctx context.Context, message *models.Message) error {
+	res, err := r.messages().InsertOne(ctx, message)
+	if err != nil {
+		return err
+	}
+	message.ID = res.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *mongoRepository) GetMessages(ctx context.Context, filter MessageFilter) ([]*models.Message, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	return findAll[models.Message](ctx, r.messages(), filter.query(), opts)
+}
+
+func (r *mongoRepository) DeleteMessages(ctx context.Context, filter MessageFilter) (int64, error) {
+	res, err := r.messages().DeleteMany(ctx, filter.query())
+	if err != nil {
+		return 0, err
+	}
+	return res.DeletedCount, nil
+}
+
+func (r *mongoRepository) CreateScheduledMessage(ctx context.Context, scheduled *models.ScheduledMessage) error {
+	res, err := r.scheduled().InsertOne(ctx, scheduled)
+	if err != nil {
+		return err
+	}
+	scheduled.ID = res.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *mongoRepository) GetScheduledMessage(ctx context.Context, id primitive.ObjectID, senderID string) (*models.ScheduledMessage, error) {
+	return findOne[models.ScheduledMessage](ctx, r.scheduled(), bson.M{"_id": id, "sender_id": senderID})
+}
+
+func (r *mongoRepository) GetPendingScheduledMessages(ctx context.Context, senderID string, chatID *primitive.ObjectID) ([]*models.ScheduledMessage, error) {
+	filter := bson.M{
+		"sender_id": senderID,
+		"status":    models.ScheduledStatusPending,
+	}
+	if chatID != nil {
+		filter["chat_id"] = *chatID
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "send_at", Value: 1}})
+	return findAll[models.ScheduledMessage](ctx, r.scheduled(), filter, opts)
+}
+
+func (r *mongoRepository) UpdatePendingScheduledMessage(ctx context.Context, id primitive.ObjectID, senderID string, update ScheduledMessageUpdate) (*models.ScheduledMessage, error) {
+	set := bson.M{"updated_at": time.Now()}
+	if update.Message != nil {
+		set["message"] = *update.Message
+	}
+	if update.SendAt != nil {
+		set["send_at"] = *update.SendAt
+	}
+
+	// Matching on status also guards against racing with the scheduler claiming it
+	filter := bson.M{
+		"_id":       id,
+		"sender_id": senderID,
+		"status":    models.ScheduledStatusPending,
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var updated models.ScheduledMessage
+	err := r.scheduled().FindOneAndUpdate(ctx, filter, bson.M{"$set": set}, opts).Decode(&updated)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+func (r *mongoRepository) CancelPendingScheduledMessage(ctx context.Context, id primitive.ObjectID, senderID string) error {
+	filter := bson.M{
+		"_id":       id,
+		"sender_id": senderID,
+		"status":    models.ScheduledStatusPending,
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":     models.ScheduledStatusCancelled,
+			"updated_at": time.Now(),
+		},
+	}
+	res, err := r.scheduled().UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoRepository) ClaimDueScheduledMessage(ctx context.Context, now, staleBefore time.Time) (*models.ScheduledMessage, error) {
+	filter := bson.M{
+		"$or": []bson.M{
+			{"status": models.ScheduledStatusPending, "send_at": bson.M{"$lte": now}},
+			{"status": models.ScheduledStatusSending, "updated_at": bson.M{"$lte": staleBefore}},
+		},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":     models.ScheduledStatusSending,
+			"updated_at": now,
+		},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "send_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var scheduled models.ScheduledMessage
+	err := r.scheduled().FindOneAndUpdate(ctx, filter, update, opts).Decode(&scheduled)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &scheduled, nil
+}
+
+func (r *mongoRepository) FinishScheduledMessage(ctx context.Context, id primitive.ObjectID, result ScheduledMessageResult) error {
+	set := bson.M{
+		"status":     result.Status,
+		"updated_at": time.Now(),
+	}
+	if result.MessageID != nil {
+		set["message_id"] = *result.MessageID
+	}
+	if result.Error != "" {
+		set["error"] = result.Error
+	}
+	_, err := r.scheduled().UpdateByID(ctx, id, bson.M{"$set": set})
+	return err
+}
+
+func (r *mongoRepository) CreatePoll(ctx context.Context, poll *models.Poll) error {
+	res, err := r.polls().InsertOne(ctx, poll)
+	if err != nil {
+		return err
+	}
+	poll.ID = res.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *mongoRepository) GetPollByID(ctx context.Context, id primitive.ObjectID) (*models.Poll, error) {
+	return findOne[models.Poll](ctx, r.polls(), bson.M{"_id": id})
+}
+
+func (r *mongoRepository) ClosePoll(ctx context.Context, id primitive.ObjectID, at time.Time) (bool, error) {
+	update := bson.M{
+		"$set": bson.M{
+			"closed":    true,
+			"closed_at": at,
+		},
+	}
+	res, err := r.polls().UpdateOne(ctx, bson.M{"_id": id, "closed": false}, update)
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount > 0, nil
+}
+
+func (r *mongoRepository) GetExpiredOpenPolls(ctx context.Context, now time.Time) ([]*models.Poll, error) {
+	filter := bson.M{
+		"closed":    false,
+		"closes_at": bson.M{"$lte": now},
+	}
+	return findAll[models.Poll](ctx, r.polls(), filter)
+}
+
+func (r *mongoRepository) SetVote(ctx context.Context, pollID primitive.ObjectID, userID string, optionIDs []string) error {
+	filter := bson.M{"poll_id": pollID, "user_id": userID}
+	update := bson.M{
+		"$set": bson.M{
+			"option_ids": optionIDs,
+			"updated_at": time.Now(),
+		},
+	}
+	_, err := r.pollVotes().UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	return err
+}
+
+func (r *mongoRepository) DeleteVote(ctx context.Context, pollID primitive.ObjectID, userID string) error {
+	_, err := r.pollVotes().DeleteOne(ctx, bson.M{"poll_id": pollID, "user_id": userID})
+	return err
+}
+
+func (r *mongoRepository) GetVotes(ctx context.Context, pollID primitive.ObjectID) ([]*models.PollVote, error) {
+	return findAll[models.PollVote](ctx, r.pollVotes(), bson.M{"poll_id": pollID})
+}
+
+// findOne decodes a single document, mapping "no documents" to ErrNotFound
+func findOne[T any](ctx context.Context, collection *mongo.Collection, filter bson.M) (*T, error) {
+	var doc T
+	err := collection.FindOne(ctx, filter).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// findAll decodes every document matching filter
+func findAll[T any](ctx context.Context, collection *mongo.Collection, filter bson.M, opts ...*options.FindOptions) ([]*T, error) {
+	cur, err := collection.Find(ctx, filter, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var docs []*T
+	for cur.Next(ctx) {
+		var doc T
+		if err := cur.Decode(&doc); err != nil {
+			return nil, err
+		}
+		docs = append(docs, &doc)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+func (f MessageFilter) query() bson.M {
+	query := bson.M{}
+	if f.ChatID != nil {
+		query["chat_id"] = *f.ChatID
+	}
+	if f.SenderID != "" {
+		query["sender_id"] = f.SenderID
+	}
+	return query
+}
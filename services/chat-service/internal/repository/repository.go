@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/wutthichod/sa-connext/services/chat-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrNotFound is returned when a lookup or a conditional update matches nothing
+var ErrNotFound = errors.New("not found")
+
+// ChatRepository defines the storage operations behind ChatService
+type ChatRepository interface {
+	// Chats
+	CreateChat(ctx context.Context, chat *models.Chat) error
+	GetChatByID(ctx context.Context, id primitive.ObjectID) (*models.Chat, error)
+	FindDirectChat(ctx context.Context, userA, userB string) (*models.Chat, error)
+	GetChatsForUser(ctx context.Context, userID string) ([]*models.Chat, error)
+	AddParticipant(ctx context.Context, chatID primitive.ObjectID, userID string) error
+	TouchChat(ctx context.Context, chatID primitive.ObjectID, at time.Time) error
+
+	// Messages
+	CreateMessage(ctx context.Context, message *models.Message) error
+	GetMessages(ctx context.Context, filter MessageFilter) ([]*models.Message, error)
+	DeleteMessages(ctx context.Context, filter MessageFilter) (int64, error)
+
+	// Scheduled messages
+	CreateScheduledMessage(ctx context.Context, scheduled *models.ScheduledMessage) error
+	GetScheduledMessage(ctx context.Context, id primitive.ObjectID, senderID string) (*models.ScheduledMessage, error)
+	GetPendingScheduledMessages(ctx context.Context, senderID string, chatID *primitive.ObjectID) ([]*models.ScheduledMessage, error)
+	UpdatePendingScheduledMessage(ctx context.Context, id primitive.ObjectID, senderID string, update ScheduledMessageUpdate) (*models.ScheduledMessage, error)
+	CancelPendingScheduledMessage(ctx context.Context, id primitive.ObjectID, senderID string) error
+	// ClaimDueScheduledMessage also reclaims messages left in sending since
+	// before staleBefore, e.g. by a replica that crashed mid-delivery
+	ClaimDueScheduledMessage(ctx context.Context, now, staleBefore time.Time) (*models.ScheduledMessage, error)
+	FinishScheduledMessage(ctx context.Context, id primitive.ObjectID, result ScheduledMessageResult) error
+
+	// Polls
+	CreatePoll(ctx context.Context, poll *models.Poll) error
+	GetPollByID(ctx context.Context, id primitive.ObjectID) (*models.Poll, error)
+	ClosePoll(ctx context.Context, id primitive.ObjectID, at time.Time) (bool, error)
+	GetExpiredOpenPolls(ctx context.Context, now time.Time) ([]*models.Poll, error)
+	SetVote(ctx context.Context, pollID primitive.ObjectID, userID string, optionIDs []string) error
+	DeleteVote(ctx context.Context, pollID primitive.ObjectID, userID string) error
+	GetVotes(ctx context.Context, pollID primitive.ObjectID) ([]*models.PollVote, error)
+}
+
+// MessageFilter selects messages by chat, sender or both; empty fields match
+// everything
+type MessageFilter struct {
+	ChatID   *primitive.ObjectID
+	SenderID string
+}
+
+// ScheduledMessageUpdate holds the editable fields of a pending scheduled
+// message; nil fields are left unchanged
+type ScheduledMessageUpdate struct {
+	Message *string
+	SendAt  *time.Time
+}
+
+// ScheduledMessageResult records the outcome of a delivery attempt
+type ScheduledMessageResult struct {
+	Status    string
+	MessageID *primitive.ObjectID
+	Error     string
+}
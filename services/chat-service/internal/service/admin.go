@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/wutthichod/sa-connext/services/chat-service/internal/repository"
+	"github.com/wutthichod/sa-connext/shared/correlation"
+	grpcerrors "github.com/wutthichod/sa-connext/shared/errors"
+	pb "github.com/wutthichod/sa-connext/shared/proto/chat"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PurgeMessages deletes abusive content for a platform admin; the gateway
+// has already checked the admin's role. Polls posted by the messages are
+// left in place, but nothing links to them any more.
+func (s *ChatService) PurgeMessages(ctx context.Context, req *pb.PurgeMessagesRequest) (*pb.PurgeMessagesResponse, error) {
+	if req.ChatId == "" && req.SenderId == "" {
+		return nil, grpcerrors.InvalidInput("chat_id or sender_id is required", nil)
+	}
+
+	filter := repository.MessageFilter{SenderID: req.SenderId}
+	if req.ChatId != "" {
+		chatObjId, err := primitive.ObjectIDFromHex(req.ChatId)
+		if err != nil {
+			return nil, grpcerrors.InvalidInput("invalid chat ID format", map[string]string{
+				"field": "chat_id",
+				"value": req.ChatId,
+			})
+		}
+		if _, err := s.repo.GetChatByID(ctx, chatObjId); err == repository.ErrNotFound {
+			return nil, grpcerrors.NotFound("Chat")
+		} else if err != nil {
+			return nil, err
+		}
+		filter.ChatID = &chatObjId
+	}
+
+	deleted, err := s.repo.DeleteMessages(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete messages: %v", err)
+	}
+	correlation.Printf(ctx, "Admin %s purged %d messages (chat=%q, sender=%q)", req.AdminId, deleted, req.ChatId, req.SenderId)
+	return &pb.PurgeMessagesResponse{Success: true, DeletedCount: deleted}, nil
+}
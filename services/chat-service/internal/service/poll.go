@@ -0,0 +1,347 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wutthichod/sa-connext/services/chat-service/internal/models"
+	"github.com/wutthichod/sa-connext/services/chat-service/internal/repository"
+	"github.com/wutthichod/sa-connext/shared/correlation"
+	grpcerrors "github.com/wutthichod/sa-connext/shared/errors"
+	"github.com/wutthichod/sa-connext/shared/metrics"
+	pb "github.com/wutthichod/sa-connext/shared/proto/chat"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	minPollOptions = 2
+	maxPollOptions = 10
+)
+
+// pollUpdate is pushed to every participant whenever a poll's tally changes
+type pollUpdate struct {
+	Type string   `json:"type"`
+	Poll *pb.Poll `json:"poll"`
+}
+
+func (s *ChatService) CreatePoll(ctx context.Context, req *pb.CreatePollRequest) (*pb.CreatePollResponse, error) {
+	question := strings.TrimSpace(req.Question)
+	if question == "" {
+		return nil, grpcerrors.InvalidInput("question is required", map[string]string{"field": "question"})
+	}
+	if len(req.Options) < minPollOptions || len(req.Options) > maxPollOptions {
+		return nil, grpcerrors.InvalidInput(fmt.Sprintf("a poll needs between %d and %d options", minPollOptions, maxPollOptions), map[string]string{
+			"field": "options",
+		})
+	}
+	pollOptions := make([]models.PollOption, 0, len(req.Options))
+	for i, text := range req.Options {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return nil, grpcerrors.InvalidInput("poll options cannot be empty", map[string]string{
+				"field": "options",
+				"index": strconv.Itoa(i),
+			})
+		}
+		pollOptions = append(pollOptions, models.PollOption{ID: strconv.Itoa(i), Text: text})
+	}
+
+	var closesAt *time.Time
+	if req.ClosesAt != "" {
+		t, err := time.Parse(time.RFC3339, req.ClosesAt)
+		if err != nil || !t.After(time.Now()) {
+			return nil, grpcerrors.InvalidInput("closes_at must be a future RFC3339 time", map[string]string{
+				"field": "closes_at",
+				"value": req.ClosesAt,
+			})
+		}
+		t = t.UTC()
+		closesAt = &t
+	}
+
+	chatObjId, err := primitive.ObjectIDFromHex(req.ChatId)
+	if err != nil {
+		return nil, grpcerrors.InvalidInput("invalid chat ID format", map[string]string{
+			"field": "chat_id",
+			"value": req.ChatId,
+		})
+	}
+
+	existingChat, err := s.repo.GetChatByID(ctx, chatObjId)
+	if err == repository.ErrNotFound {
+		return nil, grpcerrors.NotFound("Chat")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !existingChat.IsGroup {
+		return nil, grpcerrors.InvalidInput("polls are only available in group chats", map[string]string{"field": "chat_id"})
+	}
+	if !slices.Contains(existingChat.Participants, req.SenderId) {
+		return nil, grpcerrors.PermissionDenied("sender is not a participant in this chat")
+	}
+
+	now := time.Now()
+	poll := &models.Poll{
+		ID:             primitive.NewObjectID(),
+		ChatID:         existingChat.ID,
+		MessageID:      primitive.NewObjectID(),
+		CreatorID:      req.SenderId,
+		Question:       question,
+		Options:        pollOptions,
+		MultipleChoice: req.MultipleChoice,
+		Anonymous:      req.Anonymous,
+		ClosesAt:       closesAt,
+		CreatedAt:      now,
+	}
+	message := &models.Message{
+		ID:        poll.MessageID,
+		ChatID:    existingChat.ID,
+		SenderID:  req.SenderId,
+		Message:   question,
+		Type:      models.MessageTypePoll,
+		PollID:    &poll.ID,
+		CreatedAt: now,
+	}
+
+	if err := s.repo.CreatePoll(ctx, poll); err != nil {
+		return nil, fmt.Errorf("failed to create poll: %v", err)
+	}
+	if err := s.repo.CreateMessage(ctx, message); err != nil {
+		return nil, fmt.Errorf("failed to save poll message: %v", err)
+	}
+
+	if err := s.repo.TouchChat(ctx, existingChat.ID, now); err != nil {
+		return nil, fmt.Errorf("failed to update chat: %v", err)
+	}
+
+	if err := s.publishToParticipants(ctx, existingChat.Participants, req.SenderId, message); err != nil {
+		return nil, err
+	}
+	metrics.MessagesSent.WithLabelValues(models.MessageTypePoll).Inc()
+
+	return &pb.CreatePollResponse{
+		PollId:    poll.ID.Hex(),
+		MessageId: message.ID.Hex(),
+	}, nil
+}
+
+func (s *ChatService) GetPoll(ctx context.Context, req *pb.GetPollRequest) (*pb.GetPollResponse, error) {
+	poll, _, err := s.findPollForParticipant(ctx, req.PollId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	pbPoll, err := s.tallyPoll(ctx, poll, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetPollResponse{Success: true, Poll: pbPoll}, nil
+}
+
+func (s *ChatService) Vote(ctx context.Context, req *pb.VoteRequest) (*pb.VoteResponse, error) {
+	poll, chat, err := s.findPollForParticipant(ctx, req.PollId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if poll.IsClosed(time.Now()) {
+		return nil, grpcerrors.InvalidInput("poll is closed", map[string]string{"field": "poll_id"})
+	}
+
+	// Validate and de-duplicate the chosen options
+	var optionIDs []string
+	for _, optionID := range req.OptionIds {
+		if !slices.ContainsFunc(poll.Options, func(o models.PollOption) bool { return o.ID == optionID }) {
+			return nil, grpcerrors.InvalidInput("unknown poll option", map[string]string{
+				"field": "option_ids",
+				"value": optionID,
+			})
+		}
+		if !slices.Contains(optionIDs, optionID) {
+			optionIDs = append(optionIDs, optionID)
+		}
+	}
+	if !poll.MultipleChoice && len(optionIDs) > 1 {
+		return nil, grpcerrors.InvalidInput("this poll allows a single choice", map[string]string{"field": "option_ids"})
+	}
+
+	if len(optionIDs) == 0 {
+		if err := s.repo.DeleteVote(ctx, poll.ID, req.UserId); err != nil {
+			return nil, fmt.Errorf("failed to retract vote: %v", err)
+		}
+	} else {
+		if err := s.repo.SetVote(ctx, poll.ID, req.UserId, optionIDs); err != nil {
+			return nil, fmt.Errorf("failed to save vote: %v", err)
+		}
+	}
+
+	pbPoll, err := s.broadcastPoll(ctx, poll, chat.Participants)
+	if err != nil {
+		return nil, err
+	}
+	pbPoll.MyOptionIds = optionIDs
+	return &pb.VoteResponse{Success: true, Poll: pbPoll}, nil
+}
+
+func (s *ChatService) ClosePoll(ctx context.Context, req *pb.ClosePollRequest) (*pb.ClosePollResponse, error) {
+	poll, chat, err := s.findPollForParticipant(ctx, req.PollId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if poll.CreatorID != req.UserId {
+		return nil, grpcerrors.PermissionDenied("only the poll creator can close it")
+	}
+
+	closed, err := s.markPollClosed(ctx, poll)
+	if err != nil {
+		return nil, err
+	}
+
+	var pbPoll *pb.Poll
+	if closed {
+		pbPoll, err = s.broadcastPoll(ctx, poll, chat.Participants)
+	} else {
+		pbPoll, err = s.tallyPoll(ctx, poll, "")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ClosePollResponse{Success: true, Poll: pbPoll}, nil
+}
+
+// closeExpiredPolls closes open polls whose close time has passed and pushes
+// the final tally to participants
+func (s *ChatService) closeExpiredPolls(ctx context.Context) {
+	polls, err := s.repo.GetExpiredOpenPolls(ctx, time.Now())
+	if err != nil {
+		correlation.Printf(ctx, "failed to find expired polls: %v", err)
+		return
+	}
+
+	for _, poll := range polls {
+		closed, err := s.markPollClosed(ctx, poll)
+		if err != nil || !closed {
+			continue
+		}
+
+		chat, err := s.repo.GetChatByID(ctx, poll.ChatID)
+		if err != nil {
+			correlation.Printf(ctx, "failed to load chat for poll %s: %v", poll.ID.Hex(), err)
+			continue
+		}
+		if _, err := s.broadcastPoll(ctx, poll, chat.Participants); err != nil {
+			correlation.Printf(ctx, "failed to publish closed poll %s: %v", poll.ID.Hex(), err)
+		}
+	}
+}
+
+// markPollClosed closes the poll and reports whether this call closed it
+func (s *ChatService) markPollClosed(ctx context.Context, poll *models.Poll) (bool, error) {
+	now := time.Now()
+	closed, err := s.repo.ClosePoll(ctx, poll.ID, now)
+	if err != nil {
+		return false, fmt.Errorf("failed to close poll: %v", err)
+	}
+	poll.Closed = true
+	if !closed {
+		return false, nil
+	}
+	poll.ClosedAt = &now
+	return true, nil
+}
+
+// findPollForParticipant loads a poll and its chat, checking that userID
+// takes part in the chat
+func (s *ChatService) findPollForParticipant(ctx context.Context, pollID, userID string) (*models.Poll, *models.Chat, error) {
+	pollObjId, err := primitive.ObjectIDFromHex(pollID)
+	if err != nil {
+		return nil, nil, grpcerrors.InvalidInput("invalid poll ID format", map[string]string{
+			"field": "poll_id",
+			"value": pollID,
+		})
+	}
+
+	poll, err := s.repo.GetPollByID(ctx, pollObjId)
+	if err == repository.ErrNotFound {
+		return nil, nil, grpcerrors.NotFound("Poll")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	chat, err := s.findChatForParticipant(ctx, poll.ChatID.Hex(), userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return poll, chat, nil
+}
+
+// broadcastPoll recomputes the tally and pushes it to all participants
+func (s *ChatService) broadcastPoll(ctx context.Context, poll *models.Poll, participants []string) (*pb.Poll, error) {
+	pbPoll, err := s.tallyPoll(ctx, poll, "")
+	if err != nil {
+		return nil, err
+	}
+	if err := s.publishToParticipants(ctx, participants, "", pollUpdate{Type: "poll_update", Poll: pbPoll}); err != nil {
+		return nil, err
+	}
+	return pbPoll, nil
+}
+
+// tallyPoll counts the current votes. Voter IDs are only included for
+// named polls, and userID (if set) fills in the caller's own choices.
+func (s *ChatService) tallyPoll(ctx context.Context, poll *models.Poll, userID string) (*pb.Poll, error) {
+	votes, err := s.repo.GetVotes(ctx, poll.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load poll votes: %v", err)
+	}
+
+	counts := make(map[string]int32)
+	voters := make(map[string][]string)
+	var totalVoters int32
+	var myOptionIDs []string
+	for _, vote := range votes {
+		totalVoters++
+		for _, optionID := range vote.OptionIDs {
+			counts[optionID]++
+			if !poll.Anonymous {
+				voters[optionID] = append(voters[optionID], vote.UserID)
+			}
+		}
+		if vote.UserID == userID {
+			myOptionIDs = vote.OptionIDs
+		}
+	}
+	pbOptions := make([]*pb.PollOption, 0, len(poll.Options))
+	for _, option := range poll.Options {
+		pbOptions = append(pbOptions, &pb.PollOption{
+			OptionId: option.ID,
+			Text:     option.Text,
+			Votes:    counts[option.ID],
+			VoterIds: voters[option.ID],
+		})
+	}
+
+	var closesAt string
+	if poll.ClosesAt != nil {
+		closesAt = poll.ClosesAt.Format(time.RFC3339)
+	}
+	return &pb.Poll{
+		PollId:         poll.ID.Hex(),
+		ChatId:         poll.ChatID.Hex(),
+		MessageId:      poll.MessageID.Hex(),
+		CreatorId:      poll.CreatorID,
+		Question:       poll.Question,
+		Options:        pbOptions,
+		MultipleChoice: poll.MultipleChoice,
+		Anonymous:      poll.Anonymous,
+		ClosesAt:       closesAt,
+		Closed:         poll.IsClosed(time.Now()),
+		TotalVoters:    totalVoters,
+		MyOptionIds:    myOptionIDs,
+	}, nil
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/wutthichod/sa-connext/services/chat-service/internal/models"
+	"github.com/wutthichod/sa-connext/services/chat-service/internal/repository"
+	grpcerrors "github.com/wutthichod/sa-connext/shared/errors"
+	pb "github.com/wutthichod/sa-connext/shared/proto/chat"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (s *ChatService) ScheduleMessage(ctx context.Context, req *pb.ScheduleMessageRequest) (*pb.ScheduleMessageResponse, error) {
+	if strings.TrimSpace(req.Message) == "" {
+		return nil, grpcerrors.InvalidInput("message is required", map[string]string{"field": "message"})
+	}
+	sendAt, err := parseSendAt(req.SendAt)
+	if err != nil {
+		return nil, err
+	}
+
+	chatObjId, err := primitive.ObjectIDFromHex(req.ChatId)
+	if err != nil {
+		return nil, grpcerrors.InvalidInput("invalid chat ID format", map[string]string{
+			"field": "chat_id",
+			"value": req.ChatId,
+		})
+	}
+
+	existingChat, err := s.repo.GetChatByID(ctx, chatObjId)
+	if err == repository.ErrNotFound {
+		return nil, grpcerrors.NotFound("Chat")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify sender is a participant in the chat
+	if !slices.Contains(existingChat.Participants, req.SenderId) {
+		return nil, grpcerrors.PermissionDenied("sender is not a participant in this chat")
+	}
+	if req.DirectRestricted && !existingChat.IsGroup {
+		return nil, errDirectRestricted
+	}
+
+	now := time.Now()
+	scheduled := &models.ScheduledMessage{
+		ChatID:    existingChat.ID,
+		SenderID:  req.SenderId,
+		Message:   req.Message,
+		SendAt:    sendAt,
+		Status:    models.ScheduledStatusPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := s.repo.CreateScheduledMessage(ctx, scheduled); err != nil {
+		return nil, fmt.Errorf("failed to save scheduled message: %v", err)
+	}
+
+	return &pb.ScheduleMessageResponse{
+		ScheduledMessageId: scheduled.ID.Hex(),
+		Status:             scheduled.Status,
+	}, nil
+}
+
+func (s *ChatService) GetScheduledMessages(ctx context.Context, req *pb.GetScheduledMessagesRequest) (*pb.GetScheduledMessagesResponse, error) {
+	var chatObjId *primitive.ObjectID
+	if req.ChatId != "" {
+		chat, err := s.findChatForParticipant(ctx, req.ChatId, req.SenderId)
+		if err != nil {
+			return nil, err
+		}
+		chatObjId = &chat.ID
+	}
+
+	pending, err := s.repo.GetPendingScheduledMessages(ctx, req.SenderId, chatObjId)
+	if err != nil {
+		return &pb.GetScheduledMessagesResponse{Success: false}, err
+	}
+
+	var scheduledMessages []*pb.ScheduledMessage
+	for _, scheduled := range pending {
+		scheduledMessages = append(scheduledMessages, toPbScheduledMessage(scheduled))
+	}
+
+	return &pb.GetScheduledMessagesResponse{
+		Success:           true,
+		ScheduledMessages: scheduledMessages,
+	}, nil
+}
+
+func (s *ChatService) UpdateScheduledMessage(ctx context.Context, req *pb.UpdateScheduledMessageRequest) (*pb.UpdateScheduledMessageResponse, error) {
+	scheduledObjId, err := primitive.ObjectIDFromHex(req.ScheduledMessageId)
+	if err != nil {
+		return nil, grpcerrors.InvalidInput("invalid scheduled message ID format", map[string]string{
+			"field": "scheduled_message_id",
+			"value": req.ScheduledMessageId,
+		})
+	}
+
+	var update repository.ScheduledMessageUpdate
+	if strings.TrimSpace(req.Message) != "" {
+		update.Message = &req.Message
+	}
+	if req.SendAt != "" {
+		sendAt, err := parseSendAt(req.SendAt)
+		if err != nil {
+			return nil, err
+		}
+		update.SendAt = &sendAt
+	}
+
+	// Only pending messages owned by the sender can be edited
+	updated, err := s.repo.UpdatePendingScheduledMessage(ctx, scheduledObjId, req.SenderId, update)
+	if err == repository.ErrNotFound {
+		return nil, s.scheduledMessageNotPending(ctx, scheduledObjId, req.SenderId)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update scheduled message: %v", err)
+	}
+
+	return &pb.UpdateScheduledMessageResponse{
+		Success:          true,
+		ScheduledMessage: toPbScheduledMessage(updated),
+	}, nil
+}
+
+func (s *ChatService) CancelScheduledMessage(ctx context.Context, req *pb.CancelScheduledMessageRequest) (*pb.CancelScheduledMessageResponse, error) {
+	scheduledObjId, err := primitive.ObjectIDFromHex(req.ScheduledMessageId)
+	if err != nil {
+		return nil, grpcerrors.InvalidInput("invalid scheduled message ID format", map[string]string{
+			"field": "scheduled_message_id",
+			"value": req.ScheduledMessageId,
+		})
+	}
+
+	err = s.repo.CancelPendingScheduledMessage(ctx, scheduledObjId, req.SenderId)
+	if err == repository.ErrNotFound {
+		return nil, s.scheduledMessageNotPending(ctx, scheduledObjId, req.SenderId)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel scheduled message: %v", err)
+	}
+
+	return &pb.CancelScheduledMessageResponse{Success: true}, nil
+}
+
+// scheduledMessageNotPending explains why an edit or cancel matched nothing:
+// either the message doesn't belong to the sender, or it already left the
+// pending state.
+func (s *ChatService) scheduledMessageNotPending(ctx context.Context, id primitive.ObjectID, senderID string) error {
+	existing, err := s.repo.GetScheduledMessage(ctx, id, senderID)
+	if err == repository.ErrNotFound {
+		return grpcerrors.NotFound("Scheduled message")
+	}
+	if err != nil {
+		return err
+	}
+	return grpcerrors.InvalidInput(fmt.Sprintf("scheduled message is already %s", existing.Status), map[string]string{
+		"field": "status",
+		"value": existing.Status,
+	})
+}
+
+func parseSendAt(value string) (time.Time, error) {
+	sendAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, grpcerrors.InvalidInput("invalid send_at format, must be RFC3339", map[string]string{
+			"field": "send_at",
+			"value": value,
+		})
+	}
+	if !sendAt.After(time.Now()) {
+		return time.Time{}, grpcerrors.InvalidInput("send_at must be in the future", map[string]string{
+			"field": "send_at",
+			"value": value,
+		})
+	}
+	return sendAt.UTC(), nil
+}
+
+func toPbScheduledMessage(scheduled *models.ScheduledMessage) *pb.ScheduledMessage {
+	return &pb.ScheduledMessage{
+		ScheduledMessageId: scheduled.ID.Hex(),
+		ChatId:             scheduled.ChatID.Hex(),
+		SenderId:           scheduled.SenderID,
+		Message:            scheduled.Message,
+		SendAt:             scheduled.SendAt.Format(time.RFC3339),
+		Status:             scheduled.Status,
+		CreatedAt:          scheduled.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:          scheduled.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/wutthichod/sa-connext/services/chat-service/internal/models"
+	"github.com/wutthichod/sa-connext/services/chat-service/internal/repository"
+	"github.com/wutthichod/sa-connext/shared/correlation"
+	pb "github.com/wutthichod/sa-connext/shared/proto/chat"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// sendingLease is how long a claimed message may stay in sending before
+// another run assumes its scheduler died and delivers it again. Deliveries
+// take well under a second, so a reclaimed message is almost always one
+// that was never sent; in the rare case it was, it arrives twice.
+const sendingLease = 5 * time.Minute
+
+// Scheduler periodically delivers scheduled messages that are due
+// through the regular SendMessage path, and closes expired polls.
+type Scheduler struct {
+	service  *ChatService
+	interval time.Duration
+}
+
+func NewScheduler(service *ChatService, interval time.Duration) *Scheduler {
+	return &Scheduler{service: service, interval: interval}
+}
+
+// Run polls for due messages until ctx is cancelled
+func (sc *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(sc.interval)
+	defer ticker.Stop()
+
+	log.Printf("Scheduler started, polling every %v", sc.interval)
+	for {
+		sc.deliverDue(ctx)
+		sc.service.closeExpiredPolls(ctx)
+
+		select {
+		case <-ctx.Done():
+			log.Println("Scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// deliverDue sends every pending message whose send time has passed
+func (sc *Scheduler) deliverDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		// Claiming atomically moves the oldest due message from pending to
+		// sending, so an edit, a cancel or another replica cannot pick it up
+		// at the same time. Messages whose lease has run out are claimed
+		// again.
+		now := time.Now()
+		scheduled, err := sc.service.repo.ClaimDueScheduledMessage(ctx, now, now.Add(-sendingLease))
+		if err == repository.ErrNotFound {
+			return
+		}
+		if err != nil {
+			log.Printf("Scheduler: failed to claim scheduled message: %v", err)
+			return
+		}
+		// Each delivery is its own action, traced under a fresh request ID.
+		// A claimed message is delivered even if shutdown begins meanwhile,
+		// so it is only left in sending if the process dies.
+		sc.deliver(correlation.WithID(context.WithoutCancel(ctx), correlation.NewID()), scheduled)
+	}
+}
+
+func (sc *Scheduler) deliver(ctx context.Context, scheduled *models.ScheduledMessage) {
+	var result repository.ScheduledMessageResult
+
+	res, err := sc.service.SendMessage(ctx, &pb.SendMessageRequest{
+		SenderId: scheduled.SenderID,
+		ChatId:   scheduled.ChatID.Hex(),
+		Message:  scheduled.Message,
+	})
+	if err != nil {
+		correlation.Printf(ctx, "Scheduler: failed to send scheduled message %s: %v", scheduled.ID.Hex(), err)
+		result.Status = models.ScheduledStatusFailed
+		result.Error = err.Error()
+	} else {
+		result.Status = models.ScheduledStatusSent
+		if messageID, err := primitive.ObjectIDFromHex(res.MessageId); err == nil {
+			result.MessageID = &messageID
+		}
+	}
+
+	if err := sc.service.repo.FinishScheduledMessage(ctx, scheduled.ID, result); err != nil {
+		correlation.Printf(ctx, "Scheduler: failed to update scheduled message %s: %v", scheduled.ID.Hex(), err)
+	}
+}
@@ -4,43 +4,44 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"slices"
 	"time"
 
 	"github.com/wutthichod/sa-connext/services/chat-service/internal/models"
+	"github.com/wutthichod/sa-connext/services/chat-service/internal/repository"
 	"github.com/wutthichod/sa-connext/shared/contracts"
-	"github.com/wutthichod/sa-connext/shared/messaging"
+	"github.com/wutthichod/sa-connext/shared/correlation"
+	grpcerrors "github.com/wutthichod/sa-connext/shared/errors"
+	"github.com/wutthichod/sa-connext/shared/metrics"
 	pb "github.com/wutthichod/sa-connext/shared/proto/chat"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
+// Publisher delivers messages to the broker; *messaging.RabbitMQ implements it
+type Publisher interface {
+	PublishMessage(ctx context.Context, exchange, routingKey string, message interface{}) error
+}
+
+// errDirectRestricted refuses direct messages from senders the gateway says
+// have to verify their email first
+var errDirectRestricted = grpcerrors.EmailNotVerified("verify your email to send direct messages")
+
 type ChatService struct {
 	pb.UnimplementedChatServiceServer
-	db  *mongo.Database
-	rmq *messaging.RabbitMQ
+	repo      repository.ChatRepository
+	publisher Publisher
 }
 
-func NewChatService(db *mongo.Database, rmq *messaging.RabbitMQ) *ChatService {
-	return &ChatService{db: db, rmq: rmq}
+func NewChatService(repo repository.ChatRepository, publisher Publisher) *ChatService {
+	return &ChatService{repo: repo, publisher: publisher}
 }
 
 func (s *ChatService) CreateChat(ctx context.Context, req *pb.CreateChatRequest) (*pb.CreateChatResponse, error) {
-	chatCollection := s.db.Collection("chats")
-	filter := bson.M{
-		"participants": bson.M{
-			"$all": []string{req.SenderId, req.RecipientId},
-		},
-	}
-
-	var existingChat models.Chat
-	err := chatCollection.FindOne(ctx, filter).Decode(&existingChat)
-	if err != nil && err != mongo.ErrNoDocuments {
+	existingChat, err := s.repo.FindDirectChat(ctx, req.SenderId, req.RecipientId)
+	if err != nil && err != repository.ErrNotFound {
 		return nil, fmt.Errorf("failed to check existing chat: %v", err)
-	} else if err == mongo.ErrNoDocuments {
-		newChat := &models.Chat{
+	} else if err == repository.ErrNotFound {
+		existingChat = &models.Chat{
 			IsGroup:      false,
 			Name:         "",
 			Participants: []string{req.SenderId, req.RecipientId},
@@ -48,12 +49,9 @@ func (s *ChatService) CreateChat(ctx context.Context, req *pb.CreateChatRequest)
 			UpdatedAt:    time.Now(),
 		}
 
-		res, err := chatCollection.InsertOne(ctx, newChat)
-		if err != nil {
+		if err := s.repo.CreateChat(ctx, existingChat); err != nil {
 			return nil, fmt.Errorf("failed to create chat: %v", err)
 		}
-
-		existingChat.ID = res.InsertedID.(primitive.ObjectID)
 	}
 	return &pb.CreateChatResponse{
 		SenderId:    req.SenderId,
@@ -63,8 +61,6 @@ func (s *ChatService) CreateChat(ctx context.Context, req *pb.CreateChatRequest)
 }
 
 func (s *ChatService) CreateGroup(ctx context.Context, req *pb.CreateGroupRequest) (*pb.CreateGroupResponse, error) {
-	chatCollection := s.db.Collection("chats")
-
 	newGroup := &models.Chat{
 		IsGroup:      true,
 		Name:         req.GetGroupName(),
@@ -73,34 +69,25 @@ func (s *ChatService) CreateGroup(ctx context.Context, req *pb.CreateGroupReques
 		UpdatedAt:    time.Now(),
 	}
 
-	res, err := chatCollection.InsertOne(ctx, newGroup)
-	if err != nil {
+	if err := s.repo.CreateChat(ctx, newGroup); err != nil {
 		return nil, fmt.Errorf("failed to create group chat: %v", err)
 	}
-	chatID := res.InsertedID.(primitive.ObjectID)
 
 	return &pb.CreateGroupResponse{
-		ChatId:   chatID.Hex(),
+		ChatId:   newGroup.ID.Hex(),
 		SenderId: req.SenderId,
 	}, nil
 }
 
 func (s *ChatService) JoinGroup(ctx context.Context, req *pb.JoinGroupRequest) (*pb.JoinGroupResponse, error) {
-	chatCollection := s.db.Collection("chats")
-
 	// Check if group exists
 	chatObjId, err := primitive.ObjectIDFromHex(req.ChatId)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse chat_id to object id: %v", err)
 	}
 
-	filter := bson.M{
-		"_id": chatObjId,
-	}
-
-	var existingGroup models.Chat
-	err = chatCollection.FindOne(ctx, filter).Decode(&existingGroup)
-	if err == mongo.ErrNoDocuments {
+	existingGroup, err := s.repo.GetChatByID(ctx, chatObjId)
+	if err == repository.ErrNotFound {
 		return nil, fmt.Errorf("chat not found: %v", err)
 	}
 	if err != nil {
@@ -118,13 +105,7 @@ func (s *ChatService) JoinGroup(ctx context.Context, req *pb.JoinGroupRequest) (
 	}
 
 	// Add user to participants
-	update := bson.M{
-		"$push": bson.M{
-			"participants": req.UserId,
-		},
-	}
-	_, err = chatCollection.UpdateByID(ctx, existingGroup.ID, update)
-	if err != nil {
+	if err := s.repo.AddParticipant(ctx, existingGroup.ID, req.UserId); err != nil {
 		return nil, fmt.Errorf("failed to add user to group chat: %v", err)
 	}
 
@@ -134,76 +115,44 @@ func (s *ChatService) JoinGroup(ctx context.Context, req *pb.JoinGroupRequest) (
 }
 
 func (s *ChatService) SendMessage(ctx context.Context, req *pb.SendMessageRequest) (*pb.SendMessageResponse, error) {
-	chatCollection := s.db.Collection("chats")
-	messageCollection := s.db.Collection("messages")
-
-	// Check if chat exist
-	chatObjId, err := primitive.ObjectIDFromHex(req.ChatId)
+	existingChat, err := s.findChatForParticipant(ctx, req.ChatId, req.SenderId)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse chat_id to object id: %v", err)
+		return nil, err
 	}
 
-	filter := bson.M{"_id": chatObjId}
-
-	var existingChat models.Chat
-	err = chatCollection.FindOne(ctx, filter).Decode(&existingChat)
-	if err == mongo.ErrNoDocuments {
-		return nil, fmt.Errorf("chat not found: %v", err)
-	}
-	if err != nil {
-		return nil, err
+	if req.DirectRestricted && !existingChat.IsGroup {
+		return nil, errDirectRestricted
 	}
 
-	// Verify sender is a participant in the chat
-	senderIsParticipant := slices.Contains(existingChat.Participants, req.SenderId)
-	if !senderIsParticipant {
-		return nil, fmt.Errorf("sender is not a participant in this chat")
+	// Encrypted payloads are stored as-is and never inspected; they are only
+	// supported between the two members of a direct chat
+	if req.Encrypted && existingChat.IsGroup {
+		return nil, grpcerrors.InvalidInput("encrypted messages are only supported in direct chats", map[string]string{
+			"field": "encrypted",
+		})
 	}
 
 	message := &models.Message{
 		ChatID:    existingChat.ID,
 		SenderID:  req.SenderId,
 		Message:   req.Message,
+		Encrypted: req.Encrypted,
 		CreatedAt: time.Now(),
 	}
 
-	msgRes, err := messageCollection.InsertOne(ctx, message)
-	if err != nil {
+	if err := s.repo.CreateMessage(ctx, message); err != nil {
 		return nil, fmt.Errorf("failed to save message: %v", err)
 	}
 
-	filter = bson.M{"_id": existingChat.ID}
-	now := time.Now()
-	update := bson.M{
-		"$set": bson.M{
-			"last_message_at": now,
-			"updated_at":      now,
-		},
-	}
-	_, err = chatCollection.UpdateOne(ctx, filter, update)
-	if err != nil {
+	if err := s.repo.TouchChat(ctx, existingChat.ID, time.Now()); err != nil {
 		return nil, fmt.Errorf("failed to update chat: %v", err)
 	}
 
-	message.ID = msgRes.InsertedID.(primitive.ObjectID)
-
 	// Publish to RabbitMQ
-	for _, recipientID := range existingChat.Participants {
-		if recipientID == req.SenderId {
-			continue
-		}
-		messageData, err := json.Marshal(message)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal message data: %v", err)
-		}
-		msg := contracts.AmqpMessage{
-			OwnerID: recipientID,
-			Data:    messageData,
-		}
-		if err := s.rmq.PublishMessage(ctx, "chat", "chat.gateway", msg); err != nil {
-			log.Printf("failed to publish message to RabbitMQ: %v", err)
-		}
+	if err := s.publishToParticipants(ctx, existingChat.Participants, req.SenderId, message); err != nil {
+		return nil, err
 	}
+	metrics.MessagesSent.WithLabelValues(models.MessageTypeText).Inc()
 	return &pb.SendMessageResponse{
 		MessageId: message.ID.Hex(),
 		Status:    "sent",
@@ -211,37 +160,24 @@ func (s *ChatService) SendMessage(ctx context.Context, req *pb.SendMessageReques
 }
 
 func (s *ChatService) GetChats(ctx context.Context, req *pb.GetChatsRequest) (*pb.GetChatsResponse, error) {
-	chatCollection := s.db.Collection("chats")
-
-	filter := bson.M{
-		"$or": []bson.M{
-			{"is_group": true},
-			{"participants": req.UserId},
-		},
-	}
-
-	var chats []*pb.Chat
-	cur, err := chatCollection.Find(ctx, filter)
+	existingChats, err := s.repo.GetChatsForUser(ctx, req.UserId)
 	if err != nil {
 		return &pb.GetChatsResponse{
 			Success: false,
 			Chats:   nil,
 		}, err
 	}
-	defer cur.Close(ctx)
-
-	for cur.Next(ctx) {
-		var chat models.Chat
-		if err := cur.Decode(&chat); err != nil {
-			return &pb.GetChatsResponse{
-				Success: false,
-				Chats:   nil,
-			}, err
-		}
+
+	var chats []*pb.Chat
+	for _, chat := range existingChats {
+		// Every group is listed so it can be joined, but only members see
+		// who else is in it
 		var otherParticipantIDs []string
-		for _, participantID := range chat.Participants {
-			if participantID != req.UserId {
-				otherParticipantIDs = append(otherParticipantIDs, participantID)
+		if slices.Contains(chat.Participants, req.UserId) {
+			for _, participantID := range chat.Participants {
+				if participantID != req.UserId {
+					otherParticipantIDs = append(otherParticipantIDs, participantID)
+				}
 			}
 		}
 		var lastMessageAt string
@@ -259,10 +195,6 @@ func (s *ChatService) GetChats(ctx context.Context, req *pb.GetChatsRequest) (*p
 		})
 	}
 
-	if err := cur.Err(); err != nil {
-		return &pb.GetChatsResponse{Success: false, Chats: nil}, err
-	}
-
 	return &pb.GetChatsResponse{
 		Success: true,
 		Chats:   chats,
@@ -270,40 +202,91 @@ func (s *ChatService) GetChats(ctx context.Context, req *pb.GetChatsRequest) (*p
 }
 
 func (s *ChatService) GetMessagesByChatId(ctx context.Context, req *pb.GetMessagesByChatIdRequest) (*pb.GetMessagesByChatIdResponse, error) {
-	messageCollection := s.db.Collection("messages")
-
-	chatObjId, err := primitive.ObjectIDFromHex(req.ChatId)
+	existingChat, err := s.findChatForParticipant(ctx, req.ChatId, req.UserId)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse string to object id: %v", err)
-	}
-	filter := bson.M{
-		"chat_id": chatObjId,
+		return nil, err
 	}
 
-	var messages []*pb.Message
-	cur, err := messageCollection.Find(ctx, filter)
+	existingMessages, err := s.repo.GetMessages(ctx, repository.MessageFilter{ChatID: &existingChat.ID})
 	if err != nil {
 		return &pb.GetMessagesByChatIdResponse{
 			Success:  false,
 			Messages: nil,
 		}, err
 	}
-	defer cur.Close(ctx)
 
-	for cur.Next(ctx) {
-		var message models.Message
-		if err := cur.Decode(&message); err != nil {
-			return nil, fmt.Errorf("failed to decode message: %v", err)
-		}
-		messages = append(messages, &pb.Message{
-			MessageId: message.ID.Hex(),
-			SenderId:  message.SenderID,
-			Message:   message.Message,
-			CreatedAt: message.CreatedAt.Format(time.RFC3339),
-		})
+	var messages []*pb.Message
+	for _, message := range existingMessages {
+		messages = append(messages, toPbMessage(message))
 	}
 	return &pb.GetMessagesByChatIdResponse{
 		Success:  true,
 		Messages: messages,
 	}, nil
 }
+
+// findChatForParticipant loads a chat, checking that userID takes part in
+// it. Every read of or write to a chat's contents goes through here.
+func (s *ChatService) findChatForParticipant(ctx context.Context, chatID, userID string) (*models.Chat, error) {
+	chatObjId, err := primitive.ObjectIDFromHex(chatID)
+	if err != nil {
+		return nil, grpcerrors.InvalidInput("invalid chat ID format", map[string]string{
+			"field": "chat_id",
+			"value": chatID,
+		})
+	}
+
+	chat, err := s.repo.GetChatByID(ctx, chatObjId)
+	if err == repository.ErrNotFound {
+		return nil, grpcerrors.NotFound("Chat")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(chat.Participants, userID) {
+		return nil, grpcerrors.PermissionDenied("user is not a participant in this chat")
+	}
+	return chat, nil
+}
+
+// publishToParticipants pushes data to every participant except skipUserID
+// over the chat.gateway route
+func (s *ChatService) publishToParticipants(ctx context.Context, participants []string, skipUserID string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message data: %v", err)
+	}
+	for _, recipientID := range participants {
+		if recipientID == skipUserID {
+			continue
+		}
+		msg := contracts.AmqpMessage{
+			OwnerID: recipientID,
+			Data:    payload,
+		}
+		if err := s.publisher.PublishMessage(ctx, "chat", "chat.gateway", msg); err != nil {
+			correlation.Printf(ctx, "failed to publish message to RabbitMQ: %v", err)
+		}
+	}
+	return nil
+}
+
+func toPbMessage(message *models.Message) *pb.Message {
+	msgType := message.Type
+	if msgType == "" {
+		msgType = models.MessageTypeText
+	}
+	var pollID string
+	if message.PollID != nil {
+		pollID = message.PollID.Hex()
+	}
+	return &pb.Message{
+		MessageId: message.ID.Hex(),
+		SenderId:  message.SenderID,
+		Message:   message.Message,
+		CreatedAt: message.CreatedAt.Format(time.RFC3339),
+		Type:      msgType,
+		PollId:    pollID,
+		Encrypted: message.Encrypted,
+	}
+}
@@ -0,0 +1,549 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wutthichod/sa-connext/services/chat-service/internal/models"
+	"github.com/wutthichod/sa-connext/services/chat-service/internal/repository"
+	"github.com/wutthichod/sa-connext/shared/contracts"
+	pb "github.com/wutthichod/sa-connext/shared/proto/chat"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// The Mongo backend only runs when CHAT_TEST_MONGO_URI points at a server,
+// e.g. CHAT_TEST_MONGO_URI=mongodb://localhost:27017 go test ./...
+const testMongoURIEnv = "CHAT_TEST_MONGO_URI"
+
+type backend struct {
+	name    string
+	newRepo func(t *testing.T) repository.ChatRepository
+}
+
+var backends = []backend{
+	{
+		name: "memory",
+		newRepo: func(t *testing.T) repository.ChatRepository {
+			return repository.NewMemoryRepository()
+		},
+	},
+	{
+		name: "mongo",
+		newRepo: func(t *testing.T) repository.ChatRepository {
+			uri := os.Getenv(testMongoURIEnv)
+			if uri == "" {
+				t.Skipf("%s not set", testMongoURIEnv)
+			}
+			ctx := context.Background()
+			client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+			if err != nil {
+				t.Fatalf("failed to connect to MongoDB: %v", err)
+			}
+			db := client.Database(fmt.Sprintf("chat-test-%d", time.Now().UnixNano()))
+			t.Cleanup(func() {
+				db.Drop(ctx)
+				client.Disconnect(ctx)
+			})
+			return repository.NewMongoRepository(db)
+		},
+	},
+}
+
+// fakePublisher records every published message instead of sending it
+type fakePublisher struct {
+	mutex     sync.Mutex
+	published []contracts.AmqpMessage
+}
+
+func (p *fakePublisher) PublishMessage(ctx context.Context, exchange, routingKey string, message interface{}) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.published = append(p.published, message.(contracts.AmqpMessage))
+	return nil
+}
+
+func (p *fakePublisher) ownerIDs() []string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	var ids []string
+	for _, msg := range p.published {
+		ids = append(ids, msg.OwnerID)
+	}
+	return ids
+}
+
+// forEachBackend runs fn against a fresh service for every storage backend
+func forEachBackend(t *testing.T, fn func(t *testing.T, s *ChatService, publisher *fakePublisher)) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			publisher := &fakePublisher{}
+			fn(t, NewChatService(b.newRepo(t), publisher), publisher)
+		})
+	}
+}
+
+func newGroup(t *testing.T, s *ChatService, owner string, members ...string) string {
+	t.Helper()
+	ctx := context.Background()
+	res, err := s.CreateGroup(ctx, &pb.CreateGroupRequest{SenderId: owner, GroupName: "group"})
+	if err != nil {
+		t.Fatalf("CreateGroup: %v", err)
+	}
+	for _, member := range members {
+		if _, err := s.JoinGroup(ctx, &pb.JoinGroupRequest{UserId: member, ChatId: res.ChatId}); err != nil {
+			t.Fatalf("JoinGroup(%s): %v", member, err)
+		}
+	}
+	return res.ChatId
+}
+
+func newDirectChat(t *testing.T, s *ChatService, sender, recipient string) string {
+	t.Helper()
+	res, err := s.CreateChat(context.Background(), &pb.CreateChatRequest{SenderId: sender, RecipientId: recipient})
+	if err != nil {
+		t.Fatalf("CreateChat: %v", err)
+	}
+	return res.ChatId
+}
+
+func TestCreateChat(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, s *ChatService, _ *fakePublisher) {
+		first := newDirectChat(t, s, "1", "2")
+		if again := newDirectChat(t, s, "2", "1"); again != first {
+			t.Errorf("expected the existing chat %s to be reused, got %s", first, again)
+		}
+		if other := newDirectChat(t, s, "1", "3"); other == first {
+			t.Errorf("expected a new chat for a different recipient")
+		}
+	})
+}
+
+func TestJoinGroup(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, s *ChatService, _ *fakePublisher) {
+		groupID := newGroup(t, s, "1", "2")
+		directID := newDirectChat(t, s, "1", "2")
+
+		tests := []struct {
+			name    string
+			userID  string
+			chatID  string
+			wantErr bool
+		}{
+			{name: "new member", userID: "3", chatID: groupID},
+			{name: "already a member", userID: "2", chatID: groupID, wantErr: true},
+			{name: "direct chat", userID: "3", chatID: directID, wantErr: true},
+			{name: "unknown chat", userID: "3", chatID: "64b000000000000000000000", wantErr: true},
+			{name: "malformed id", userID: "3", chatID: "nope", wantErr: true},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := s.JoinGroup(context.Background(), &pb.JoinGroupRequest{UserId: tt.userID, ChatId: tt.chatID})
+				if (err != nil) != tt.wantErr {
+					t.Errorf("JoinGroup() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			})
+		}
+	})
+}
+
+func TestSendMessage(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, s *ChatService, publisher *fakePublisher) {
+		groupID := newGroup(t, s, "1", "2", "3")
+		directID := newDirectChat(t, s, "1", "2")
+
+		tests := []struct {
+			name       string
+			req        *pb.SendMessageRequest
+			wantErr    bool
+			wantCode   codes.Code
+			wantOwners []string
+		}{
+			{
+				name:       "group message fans out to other members",
+				req:        &pb.SendMessageRequest{SenderId: "1", ChatId: groupID, Message: "hi all"},
+				wantOwners: []string{"2", "3"},
+			},
+			{
+				name:       "encrypted direct message",
+				req:        &pb.SendMessageRequest{SenderId: "2", ChatId: directID, Message: "Y2lwaGVy", Encrypted: true},
+				wantOwners: []string{"1"},
+			},
+			{
+				name:    "encrypted group message",
+				req:     &pb.SendMessageRequest{SenderId: "1", ChatId: groupID, Message: "Y2lwaGVy", Encrypted: true},
+				wantErr: true,
+			},
+			{
+				name:       "restricted sender in a group",
+				req:        &pb.SendMessageRequest{SenderId: "3", ChatId: groupID, Message: "hi", DirectRestricted: true},
+				wantOwners: []string{"1", "2"},
+			},
+			{
+				name:    "restricted sender in a direct chat",
+				req:     &pb.SendMessageRequest{SenderId: "1", ChatId: directID, Message: "hello", DirectRestricted: true},
+				wantErr: true,
+			},
+			{
+				name:     "sender not a participant",
+				req:      &pb.SendMessageRequest{SenderId: "4", ChatId: directID, Message: "hello"},
+				wantErr:  true,
+				wantCode: codes.PermissionDenied,
+			},
+			{
+				name:     "unknown chat",
+				req:      &pb.SendMessageRequest{SenderId: "1", ChatId: "64b000000000000000000000", Message: "hello"},
+				wantErr:  true,
+				wantCode: codes.NotFound,
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				publisher.published = nil
+				_, err := s.SendMessage(context.Background(), tt.req)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("SendMessage() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if tt.wantCode != codes.OK && status.Code(err) != tt.wantCode {
+					t.Errorf("SendMessage() code = %v, want %v", status.Code(err), tt.wantCode)
+				}
+				if got := publisher.ownerIDs(); !slices.Equal(got, tt.wantOwners) {
+					t.Errorf("published to %v, want %v", got, tt.wantOwners)
+				}
+			})
+		}
+
+		res, err := s.GetMessagesByChatId(context.Background(), &pb.GetMessagesByChatIdRequest{ChatId: directID, UserId: "2"})
+		if err != nil {
+			t.Fatalf("GetMessagesByChatId: %v", err)
+		}
+		if len(res.Messages) != 1 || !res.Messages[0].Encrypted || res.Messages[0].Type != models.MessageTypeText {
+			t.Errorf("unexpected direct chat history: %v", res.Messages)
+		}
+	})
+}
+
+func TestGetChats(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, s *ChatService, _ *fakePublisher) {
+		groupID := newGroup(t, s, "1", "2")
+		otherGroupID := newGroup(t, s, "2", "3")
+		directID := newDirectChat(t, s, "1", "2")
+		newDirectChat(t, s, "2", "3")
+
+		res, err := s.GetChats(context.Background(), &pb.GetChatsRequest{UserId: "1"})
+		if err != nil {
+			t.Fatalf("GetChats: %v", err)
+		}
+		var ids []string
+		for _, chat := range res.Chats {
+			ids = append(ids, chat.ChatId)
+			if chat.ChatId == directID && !slices.Equal(chat.OtherParticipantIds, []string{"2"}) {
+				t.Errorf("other participants = %v, want [2]", chat.OtherParticipantIds)
+			}
+			if chat.ChatId == groupID && !slices.Equal(chat.OtherParticipantIds, []string{"2"}) {
+				t.Errorf("group members = %v, want [2]", chat.OtherParticipantIds)
+			}
+			if chat.ChatId == otherGroupID && len(chat.OtherParticipantIds) > 0 {
+				t.Errorf("group members = %v shown to a non-member", chat.OtherParticipantIds)
+			}
+		}
+		slices.Sort(ids)
+		want := []string{groupID, otherGroupID, directID}
+		slices.Sort(want)
+		if !slices.Equal(ids, want) {
+			t.Errorf("GetChats() = %v, want %v", ids, want)
+		}
+	})
+}
+
+func TestGetMessagesByChatIdChecksParticipant(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, s *ChatService, _ *fakePublisher) {
+		ctx := context.Background()
+		directID := newDirectChat(t, s, "1", "2")
+
+		tests := []struct {
+			name     string
+			req      *pb.GetMessagesByChatIdRequest
+			wantCode codes.Code
+		}{
+			{name: "participant", req: &pb.GetMessagesByChatIdRequest{ChatId: directID, UserId: "1"}, wantCode: codes.OK},
+			{name: "non-participant", req: &pb.GetMessagesByChatIdRequest{ChatId: directID, UserId: "3"}, wantCode: codes.PermissionDenied},
+			{name: "no requester", req: &pb.GetMessagesByChatIdRequest{ChatId: directID}, wantCode: codes.PermissionDenied},
+			{name: "malformed chat ID", req: &pb.GetMessagesByChatIdRequest{ChatId: "nope", UserId: "1"}, wantCode: codes.InvalidArgument},
+			{name: "unknown chat", req: &pb.GetMessagesByChatIdRequest{ChatId: primitive.NewObjectID().Hex(), UserId: "1"}, wantCode: codes.NotFound},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if _, err := s.GetMessagesByChatId(ctx, tt.req); status.Code(err) != tt.wantCode {
+					t.Errorf("GetMessagesByChatId() error = %v, want %v", err, tt.wantCode)
+				}
+			})
+		}
+	})
+}
+
+func TestScheduledMessages(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, s *ChatService, publisher *fakePublisher) {
+		ctx := context.Background()
+		chatID := newDirectChat(t, s, "1", "2")
+		sendAt := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+
+		if _, err := s.ScheduleMessage(ctx, &pb.ScheduleMessageRequest{SenderId: "3", ChatId: chatID, Message: "hi", SendAt: sendAt}); status.Code(err) != codes.PermissionDenied {
+			t.Errorf("non-participant schedule: got %v, want PermissionDenied", err)
+		}
+		if _, err := s.ScheduleMessage(ctx, &pb.ScheduleMessageRequest{SenderId: "1", ChatId: chatID, Message: "hi", SendAt: "2000-01-01T00:00:00Z"}); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("past send_at: got %v, want InvalidArgument", err)
+		}
+		if _, err := s.ScheduleMessage(ctx, &pb.ScheduleMessageRequest{SenderId: "1", ChatId: chatID, Message: "hi", SendAt: sendAt, DirectRestricted: true}); status.Code(err) != codes.PermissionDenied {
+			t.Errorf("restricted sender schedule: got %v, want PermissionDenied", err)
+		}
+
+		kept, err := s.ScheduleMessage(ctx, &pb.ScheduleMessageRequest{SenderId: "1", ChatId: chatID, Message: "later", SendAt: sendAt})
+		if err != nil {
+			t.Fatalf("ScheduleMessage: %v", err)
+		}
+		cancelled, err := s.ScheduleMessage(ctx, &pb.ScheduleMessageRequest{SenderId: "1", ChatId: chatID, Message: "never", SendAt: sendAt})
+		if err != nil {
+			t.Fatalf("ScheduleMessage: %v", err)
+		}
+
+		updated, err := s.UpdateScheduledMessage(ctx, &pb.UpdateScheduledMessageRequest{ScheduledMessageId: kept.ScheduledMessageId, SenderId: "1", Message: "edited"})
+		if err != nil {
+			t.Fatalf("UpdateScheduledMessage: %v", err)
+		}
+		if updated.ScheduledMessage.Message != "edited" || updated.ScheduledMessage.SendAt != sendAt {
+			t.Errorf("unexpected update result: %v", updated.ScheduledMessage)
+		}
+		if _, err := s.UpdateScheduledMessage(ctx, &pb.UpdateScheduledMessageRequest{ScheduledMessageId: kept.ScheduledMessageId, SenderId: "2", Message: "hijack"}); status.Code(err) != codes.NotFound {
+			t.Errorf("edit by another user: got %v, want NotFound", err)
+		}
+
+		if _, err := s.CancelScheduledMessage(ctx, &pb.CancelScheduledMessageRequest{ScheduledMessageId: cancelled.ScheduledMessageId, SenderId: "1"}); err != nil {
+			t.Fatalf("CancelScheduledMessage: %v", err)
+		}
+		if _, err := s.CancelScheduledMessage(ctx, &pb.CancelScheduledMessageRequest{ScheduledMessageId: cancelled.ScheduledMessageId, SenderId: "1"}); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("second cancel: got %v, want InvalidArgument", err)
+		}
+
+		pending, err := s.GetScheduledMessages(ctx, &pb.GetScheduledMessagesRequest{SenderId: "1", ChatId: chatID})
+		if err != nil {
+			t.Fatalf("GetScheduledMessages: %v", err)
+		}
+		if len(pending.ScheduledMessages) != 1 || pending.ScheduledMessages[0].ScheduledMessageId != kept.ScheduledMessageId {
+			t.Errorf("pending = %v, want only %s", pending.ScheduledMessages, kept.ScheduledMessageId)
+		}
+	})
+}
+
+func TestSchedulerDeliversDueMessages(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := b.newRepo(t)
+			publisher := &fakePublisher{}
+			s := NewChatService(repo, publisher)
+			chatID := newDirectChat(t, s, "1", "2")
+			chat, err := repo.FindDirectChat(ctx, "1", "2")
+			if err != nil || chat.ID.Hex() != chatID {
+				t.Fatalf("FindDirectChat: %v", err)
+			}
+
+			// Insert directly, since the RPC only accepts future send times
+			insert := func(status string, updatedAt time.Time) *models.ScheduledMessage {
+				t.Helper()
+				scheduled := &models.ScheduledMessage{
+					ChatID:    chat.ID,
+					SenderID:  "1",
+					Message:   status,
+					SendAt:    time.Now().Add(-time.Hour),
+					Status:    status,
+					CreatedAt: time.Now().Add(-time.Hour),
+					UpdatedAt: updatedAt,
+				}
+				if err := repo.CreateScheduledMessage(ctx, scheduled); err != nil {
+					t.Fatalf("CreateScheduledMessage: %v", err)
+				}
+				return scheduled
+			}
+			due := insert(models.ScheduledStatusPending, time.Now())
+			// Claimed by a scheduler that died before finishing it
+			stale := insert(models.ScheduledStatusSending, time.Now().Add(-2*sendingLease))
+			// Still being delivered by another replica
+			inFlight := insert(models.ScheduledStatusSending, time.Now())
+
+			NewScheduler(s, time.Minute).deliverDue(ctx)
+
+			for _, tt := range []struct {
+				scheduled *models.ScheduledMessage
+				want      string
+			}{
+				{due, models.ScheduledStatusSent},
+				{stale, models.ScheduledStatusSent},
+				{inFlight, models.ScheduledStatusSending},
+			} {
+				got, err := repo.GetScheduledMessage(ctx, tt.scheduled.ID, "1")
+				if err != nil {
+					t.Fatalf("GetScheduledMessage: %v", err)
+				}
+				if got.Status != tt.want || (tt.want == models.ScheduledStatusSent) != (got.MessageID != nil) {
+					t.Errorf("%s message: status = %s, message id = %v; want %s", tt.scheduled.Message, got.Status, got.MessageID, tt.want)
+				}
+			}
+			if got := publisher.ownerIDs(); !slices.Equal(got, []string{"2", "2"}) {
+				t.Errorf("published to %v, want [2 2]", got)
+			}
+		})
+	}
+}
+
+func TestPolls(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, s *ChatService, publisher *fakePublisher) {
+		ctx := context.Background()
+		groupID := newGroup(t, s, "1", "2", "3")
+		directID := newDirectChat(t, s, "1", "2")
+
+		if _, err := s.CreatePoll(ctx, &pb.CreatePollRequest{SenderId: "1", ChatId: directID, Question: "?", Options: []string{"a", "b"}}); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("poll in direct chat: got %v, want InvalidArgument", err)
+		}
+		if _, err := s.CreatePoll(ctx, &pb.CreatePollRequest{SenderId: "1", ChatId: groupID, Question: "?", Options: []string{"a"}}); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("single option poll: got %v, want InvalidArgument", err)
+		}
+
+		single, err := s.CreatePoll(ctx, &pb.CreatePollRequest{SenderId: "1", ChatId: groupID, Question: "Lunch?", Options: []string{"pizza", "sushi"}})
+		if err != nil {
+			t.Fatalf("CreatePoll: %v", err)
+		}
+		anonymous, err := s.CreatePoll(ctx, &pb.CreatePollRequest{SenderId: "1", ChatId: groupID, Question: "Topics?", Options: []string{"go", "rust", "zig"}, MultipleChoice: true, Anonymous: true})
+		if err != nil {
+			t.Fatalf("CreatePoll: %v", err)
+		}
+
+		votes := []struct {
+			name     string
+			pollID   string
+			userID   string
+			options  []string
+			wantCode codes.Code
+		}{
+			{name: "single choice", pollID: single.PollId, userID: "2", options: []string{"0"}},
+			{name: "change vote", pollID: single.PollId, userID: "2", options: []string{"1"}},
+			{name: "second voter", pollID: single.PollId, userID: "3", options: []string{"1"}},
+			{name: "too many choices", pollID: single.PollId, userID: "1", options: []string{"0", "1"}, wantCode: codes.InvalidArgument},
+			{name: "unknown option", pollID: single.PollId, userID: "1", options: []string{"9"}, wantCode: codes.InvalidArgument},
+			{name: "non-participant", pollID: single.PollId, userID: "4", options: []string{"0"}, wantCode: codes.PermissionDenied},
+			{name: "multiple choice", pollID: anonymous.PollId, userID: "2", options: []string{"0", "2", "0"}},
+		}
+		for _, tt := range votes {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := s.Vote(ctx, &pb.VoteRequest{PollId: tt.pollID, UserId: tt.userID, OptionIds: tt.options})
+				if status.Code(err) != tt.wantCode {
+					t.Errorf("Vote() error = %v, want %v", err, tt.wantCode)
+				}
+			})
+		}
+
+		got, err := s.GetPoll(ctx, &pb.GetPollRequest{PollId: single.PollId, UserId: "2"})
+		if err != nil {
+			t.Fatalf("GetPoll: %v", err)
+		}
+		if got.Poll.TotalVoters != 2 || got.Poll.Options[0].Votes != 0 || got.Poll.Options[1].Votes != 2 {
+			t.Errorf("unexpected tally: %v", got.Poll)
+		}
+		if !slices.Equal(got.Poll.MyOptionIds, []string{"1"}) {
+			t.Errorf("my options = %v, want [1]", got.Poll.MyOptionIds)
+		}
+
+		hidden, err := s.GetPoll(ctx, &pb.GetPollRequest{PollId: anonymous.PollId, UserId: "1"})
+		if err != nil {
+			t.Fatalf("GetPoll: %v", err)
+		}
+		for _, option := range hidden.Poll.Options {
+			if len(option.VoterIds) != 0 {
+				t.Errorf("anonymous poll leaked voters %v", option.VoterIds)
+			}
+		}
+		if hidden.Poll.Options[0].Votes != 1 || hidden.Poll.Options[2].Votes != 1 {
+			t.Errorf("unexpected anonymous tally: %v", hidden.Poll.Options)
+		}
+
+		if _, err := s.ClosePoll(ctx, &pb.ClosePollRequest{PollId: single.PollId, UserId: "2"}); status.Code(err) != codes.PermissionDenied {
+			t.Errorf("close by non-creator: got %v, want PermissionDenied", err)
+		}
+		publisher.published = nil
+		closed, err := s.ClosePoll(ctx, &pb.ClosePollRequest{PollId: single.PollId, UserId: "1"})
+		if err != nil {
+			t.Fatalf("ClosePoll: %v", err)
+		}
+		if !closed.Poll.Closed {
+			t.Errorf("poll not reported as closed")
+		}
+		if got := publisher.ownerIDs(); len(got) != 3 {
+			t.Errorf("closed tally published to %v, want all 3 members", got)
+		}
+		var update pollUpdate
+		if err := json.Unmarshal(publisher.published[0].Data, &update); err != nil || update.Type != "poll_update" {
+			t.Errorf("unexpected poll update payload: %s", publisher.published[0].Data)
+		}
+		if _, err := s.Vote(ctx, &pb.VoteRequest{PollId: single.PollId, UserId: "1", OptionIds: []string{"0"}}); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("vote after close: got %v, want InvalidArgument", err)
+		}
+	})
+}
+
+func TestPurgeMessages(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, s *ChatService, _ *fakePublisher) {
+		ctx := context.Background()
+		groupID := newGroup(t, s, "1", "2", "3")
+		directID := newDirectChat(t, s, "2", "3")
+		for _, msg := range []*pb.SendMessageRequest{
+			{ChatId: groupID, SenderId: "1", Message: "hello"},
+			{ChatId: groupID, SenderId: "2", Message: "spam"},
+			{ChatId: groupID, SenderId: "2", Message: "more spam"},
+			{ChatId: directID, SenderId: "2", Message: "spam"},
+			{ChatId: directID, SenderId: "3", Message: "stop"},
+		} {
+			if _, err := s.SendMessage(ctx, msg); err != nil {
+				t.Fatalf("SendMessage: %v", err)
+			}
+		}
+
+		if _, err := s.PurgeMessages(ctx, &pb.PurgeMessagesRequest{AdminId: "9"}); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("purge without a filter: got %v, want InvalidArgument", err)
+		}
+
+		res, err := s.PurgeMessages(ctx, &pb.PurgeMessagesRequest{AdminId: "9", ChatId: groupID, SenderId: "2"})
+		if err != nil {
+			t.Fatalf("PurgeMessages: %v", err)
+		}
+		if res.DeletedCount != 2 {
+			t.Errorf("deleted %d messages from the group, want 2", res.DeletedCount)
+		}
+
+		res, err = s.PurgeMessages(ctx, &pb.PurgeMessagesRequest{AdminId: "9", SenderId: "2"})
+		if err != nil {
+			t.Fatalf("PurgeMessages: %v", err)
+		}
+		if res.DeletedCount != 1 {
+			t.Errorf("deleted %d remaining messages of the sender, want 1", res.DeletedCount)
+		}
+
+		for chatID, want := range map[string]string{groupID: "hello", directID: "stop"} {
+			history, err := s.GetMessagesByChatId(ctx, &pb.GetMessagesByChatIdRequest{ChatId: chatID, UserId: "3"})
+			if err != nil {
+				t.Fatalf("GetMessagesByChatId: %v", err)
+			}
+			if len(history.Messages) != 1 || history.Messages[0].Message != want {
+				t.Errorf("chat %s history = %v, want only %q", chatID, history.Messages, want)
+			}
+		}
+	})
+}
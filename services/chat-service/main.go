@@ -2,15 +2,24 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log"
 	"net"
+	"net/http"
+	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/wutthichod/sa-connext/services/chat-service/internal/repository"
 	"github.com/wutthichod/sa-connext/services/chat-service/internal/service"
 	"github.com/wutthichod/sa-connext/services/chat-service/package/database"
 	"github.com/wutthichod/sa-connext/shared/config"
+	"github.com/wutthichod/sa-connext/shared/correlation"
+	"github.com/wutthichod/sa-connext/shared/health"
+	"github.com/wutthichod/sa-connext/shared/lifecycle"
 	"github.com/wutthichod/sa-connext/shared/messaging"
+	"github.com/wutthichod/sa-connext/shared/metrics"
 	pb "github.com/wutthichod/sa-connext/shared/proto/chat"
+	"github.com/wutthichod/sa-connext/shared/tracing"
 	"google.golang.org/grpc"
 )
 
@@ -28,11 +37,30 @@ func main() {
 	}
 
 	ctx := context.Background()
-	mongoStore := database.NewMongoDB(ctx, config.Database().DSN)
-	db := mongoStore.DB()
+	provider, err := tracing.Init(ctx, "chat-service", config.Tracing())
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	if err := mongoStore.RunMigrate(); err != nil {
-		log.Fatalf("Migration failed: %v", err)
+	lc := lifecycle.New(config.Shutdown().Timeout)
+
+	checker := health.NewChecker()
+	var repo repository.ChatRepository
+	closeDB := func(ctx context.Context) error { return nil }
+	switch config.Database().Backend {
+	case "memory":
+		log.Println("Using in-memory storage, data will not survive a restart")
+		repo = repository.NewMemoryRepository()
+	case "mongo":
+		mongoStore := database.NewMongoDB(ctx, config.Database().DSN)
+		if err := mongoStore.RunMigrate(); err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		repo = repository.NewMongoRepository(mongoStore.DB())
+		checker.Add("mongo", health.Mongo(mongoStore.Client()))
+		closeDB = mongoStore.Close
+	default:
+		log.Fatalf("unknown database backend %q", config.Database().Backend)
 	}
 
 	// RabbitMQ connection
@@ -40,20 +68,68 @@ func main() {
 	if err != nil {
 		log.Fatal(err)
 	}
-	defer rmq.Close()
+	checker.Add("rabbitmq", rmq.Check)
 
 	// Setup exchange + queue for gateway
 	_, err = rmq.SetupQueue("chat_gateway", "chat", "direct", "chat.gateway", true, nil)
 	if err != nil {
 		log.Fatal(err)
 	}
+
+	metricsServer := metrics.NewServer(config.Metrics().Addr)
+
 	// Start gRPC server
-	chatServer := grpc.NewServer()
-	chatService := service.NewChatService(db, rmq)
+	chatServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			correlation.UnaryServerInterceptor(),
+			metrics.UnaryServerInterceptor(),
+		),
+		tracing.ServerOption(),
+	)
+	chatService := service.NewChatService(repo, rmq)
 	pb.RegisterChatServiceServer(chatServer, chatService)
+	healthServer := health.RegisterGRPC(lc.Context(), chatServer, checker, 10*time.Second)
+
+	// Deliver scheduled messages in the background
+	scheduler := service.NewScheduler(chatService, 10*time.Second)
+	schedulerDone := make(chan struct{})
+	go func() {
+		scheduler.Run(lc.Context())
+		close(schedulerDone)
+	}()
 
 	log.Println("Server listening on ", config.App().Chat)
-	if err := chatServer.Serve(lis); err != nil {
-		log.Fatalf("failed to serve: %v", err)
+	lc.Go("grpc", func() error { return chatServer.Serve(lis) })
+	lc.Go("metrics", func() error {
+		if err := metricsServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	})
+
+	// Report NOT_SERVING first so no new traffic is routed here while draining
+	lc.OnShutdown("health", func(ctx context.Context) error {
+		healthServer.Shutdown()
+		return nil
+	})
+	lc.OnShutdown("grpc", lifecycle.GRPCServer(chatServer))
+	lc.OnShutdown("scheduler", func(ctx context.Context) error {
+		select {
+		case <-schedulerDone:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+	lc.OnShutdown("rabbitmq", func(ctx context.Context) error {
+		rmq.Close()
+		return nil
+	})
+	lc.OnShutdown("database", closeDB)
+	lc.OnShutdown("metrics", metricsServer.Shutdown)
+	lc.OnShutdown("tracing", provider.Shutdown)
+
+	if err := lc.Wait(); err != nil {
+		log.Fatal(err)
 	}
 }
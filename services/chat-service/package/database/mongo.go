@@ -5,6 +5,8 @@ import (
 	"log"
 	"time"
 
+	"github.com/wutthichod/sa-connext/shared/metrics"
+	"github.com/wutthichod/sa-connext/shared/tracing"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -18,7 +20,9 @@ type MongoStore struct {
 func NewMongoDB(ctx context.Context, mongoURI string) *MongoStore {
 	databaseName := "chat-db"
 
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(mongoURI))
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(mongoURI).
+		SetMonitor(tracing.MongoMonitor()).
+		SetPoolMonitor(metrics.MongoPoolMonitor()))
 	if err != nil {
 		log.Fatalf("Failed to connect to MongoDB: %v", err)
 	}
@@ -69,6 +73,43 @@ func (m *MongoStore) RunMigrate() error {
 	if err != nil {
 		return err
 	}
+
+	// Scheduled Messages Collection
+	// The scheduler polls pending messages ordered by send time
+	scheduledCollection := m.database.Collection("scheduled_messages")
+	_, err = scheduledCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "status", Value: 1}, {Key: "send_at", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = scheduledCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "sender_id", Value: 1}, {Key: "send_at", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Polls Collection
+	// Open polls with a close time are closed by the scheduler
+	pollCollection := m.database.Collection("polls")
+	_, err = pollCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "closed", Value: 1}, {Key: "closes_at", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Poll Votes Collection
+	// Composite unique index: poll_id + user_id (one ballot per user)
+	pollVoteCollection := m.database.Collection("poll_votes")
+	_, err = pollVoteCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "poll_id", Value: 1}, {Key: "user_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -4,7 +4,10 @@ import (
 	"context"
 
 	"github.com/wutthichod/sa-connext/shared/config"
+	"github.com/wutthichod/sa-connext/shared/correlation"
+	"github.com/wutthichod/sa-connext/shared/health"
 	pb "github.com/wutthichod/sa-connext/shared/proto/user"
+	"github.com/wutthichod/sa-connext/shared/tracing"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
@@ -15,7 +18,11 @@ type UserClient struct {
 }
 
 func NewUserClient(config config.Config) (*UserClient, error) {
-	conn, err := grpc.NewClient(config.App().User, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.NewClient(config.App().User,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(correlation.UnaryClientInterceptor()),
+		tracing.DialOption(),
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -30,6 +37,15 @@ func (c *UserClient) Close() {
 	}
 }
 
+// Check asks the service behind the connection for its health
+func (c *UserClient) Check(ctx context.Context) error {
+	return health.GRPC(c.conn)(ctx)
+}
+
 func (c *UserClient) AddUserToEvent(ctx context.Context, req *pb.AddUserToEventRequest) (*pb.AddUserToEventResponse, error) {
 	return c.Client.AddUserToEvent(ctx, req)
 }
+
+func (c *UserClient) GetUserById(ctx context.Context, req *pb.GetUserByIdRequest) (*pb.GetUserByIdResponse, error) {
+	return c.Client.GetUserById(ctx, req)
+}
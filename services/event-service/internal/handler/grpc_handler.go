@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/wutthichod/sa-connext/services/event-service/internal/service"
+	"github.com/wutthichod/sa-connext/shared/contracts"
+	"github.com/wutthichod/sa-connext/shared/correlation"
+	grpcerrors "github.com/wutthichod/sa-connext/shared/errors"
+	pb "github.com/wutthichod/sa-connext/shared/proto/event"
+	"google.golang.org/grpc"
+)
+
+// gRPCHandler wraps the event service
+type gRPCHandler struct {
+	pb.UnimplementedEventServiceServer
+	service service.EventServiceInterface
+}
+
+// NewGRPCHandler creates a handler and registers it on server
+func NewGRPCHandler(server *grpc.Server, s service.EventServiceInterface) *gRPCHandler {
+	handler := &gRPCHandler{service: s}
+	pb.RegisterEventServiceServer(server, handler)
+	return handler
+}
+
+func (h *gRPCHandler) CreateEvent(ctx context.Context, req *pb.CreateEventRequest) (*pb.CreateEventResponse, error) {
+	correlation.Printf(ctx, "[Event Service] CreateEvent: name=%s, location=%s, date=%s, organizerID=%s",
+		req.GetName(), req.GetLocation(), req.GetDate(), req.GetOrganizerId())
+
+	res, err := h.service.CreateEvent(ctx, &contracts.CreateEventRequest{
+		Name:        req.GetName(),
+		Detail:      req.GetDetail(),
+		Location:    req.GetLocation(),
+		Date:        req.GetDate(),
+		OrganizerId: req.GetOrganizerId(),
+	})
+	if err != nil {
+		correlation.Printf(ctx, "[Event Service] CreateEvent: %v", err)
+		return nil, toStatus(err)
+	}
+
+	correlation.Printf(ctx, "[Event Service] CreateEvent: Success - eventID=%d, joiningCode=%s", res.EventID, res.JoiningCode)
+	return &pb.CreateEventResponse{
+		Success:     true,
+		EventId:     uint64(res.EventID),
+		JoiningCode: res.JoiningCode,
+	}, nil
+}
+
+func (h *gRPCHandler) GetEvent(ctx context.Context, req *pb.GetEventRequest) (*pb.GetEventResponse, error) {
+	event, err := h.service.GetEvent(ctx, uint(req.GetEventId()), req.GetRequesterId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.GetEventResponse{Success: true, Event: toProto(event)}, nil
+}
+
+func (h *gRPCHandler) GetAllEvents(ctx context.Context, req *pb.GetAllEventsRequest) (*pb.GetAllEventsResponse, error) {
+	events, err := h.service.GetAllEvents(ctx, req.GetRequesterId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.GetAllEventsResponse{Success: true, Events: toProtoList(events)}, nil
+}
+
+func (h *gRPCHandler) GetEventsByUserId(ctx context.Context, req *pb.GetEventsByUserIdRequest) (*pb.GetEventsByUserIdResponse, error) {
+	userID, err := strconv.ParseUint(req.GetUserId(), 10, 64)
+	if err != nil {
+		return nil, grpcerrors.InvalidInput("invalid user ID format", nil)
+	}
+	events, err := h.service.GetEventsByUserID(ctx, uint(userID))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.GetEventsByUserIdResponse{Success: true, Events: toProtoList(events)}, nil
+}
+
+func (h *gRPCHandler) JoinEvent(ctx context.Context, req *pb.JoinEventRequest) (*pb.JoinEventResponse, error) {
+	userID, err := strconv.ParseUint(req.GetUserId(), 10, 64)
+	if err != nil {
+		return nil, grpcerrors.InvalidInput("invalid user ID format", nil)
+	}
+
+	ok, eventID, err := h.service.JoinEvent(ctx, &contracts.JoinEventRequest{
+		UserID:      uint(userID),
+		JoiningCode: req.GetJoiningCode(),
+	})
+	if ok {
+		return &pb.JoinEventResponse{Success: true, EventId: uint64(eventID)}, nil
+	}
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return nil, grpcerrors.Unauthorized("invalid joining code")
+}
+
+func (h *gRPCHandler) DeleteEvent(ctx context.Context, req *pb.DeleteEventRequest) (*pb.DeleteEventResponse, error) {
+	correlation.Printf(ctx, "[Event Service] DeleteEvent: eventID=%d, requesterID=%s", req.GetEventId(), req.GetRequesterId())
+	if err := h.service.DeleteByID(ctx, uint(req.GetEventId()), req.GetRequesterId()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.DeleteEventResponse{Success: true}, nil
+}
+
+func (h *gRPCHandler) ForceDeleteEvent(ctx context.Context, req *pb.ForceDeleteEventRequest) (*pb.DeleteEventResponse, error) {
+	if err := h.service.ForceDeleteByID(ctx, uint(req.GetEventId()), req.GetAdminId(), req.GetReason()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.DeleteEventResponse{Success: true}, nil
+}
+
+func (h *gRPCHandler) UpdateEvent(ctx context.Context, req *pb.UpdateEventRequest) (*pb.UpdateEventResponse, error) {
+	correlation.Printf(ctx, "[Event Service] UpdateEvent: eventID=%d, requesterID=%s", req.GetEventId(), req.GetRequesterId())
+	event, err := h.service.UpdateEvent(ctx, uint(req.GetEventId()), req.GetRequesterId(), &contracts.UpdateEventRequest{
+		Name:     req.GetName(),
+		Detail:   req.GetDetail(),
+		Location: req.GetLocation(),
+		Date:     req.GetDate(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.UpdateEventResponse{Success: true, Event: toProto(event)}, nil
+}
+
+func (h *gRPCHandler) AddCoOrganizer(ctx context.Context, req *pb.CoOrganizerRequest) (*pb.CoOrganizerResponse, error) {
+	correlation.Printf(ctx, "[Event Service] AddCoOrganizer: eventID=%d, userID=%s", req.GetEventId(), req.GetUserId())
+	event, err := h.service.AddCoOrganizer(ctx, uint(req.GetEventId()), req.GetRequesterId(), req.GetUserId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.CoOrganizerResponse{Success: true, Event: toProto(event)}, nil
+}
+
+func (h *gRPCHandler) RemoveCoOrganizer(ctx context.Context, req *pb.CoOrganizerRequest) (*pb.CoOrganizerResponse, error) {
+	correlation.Printf(ctx, "[Event Service] RemoveCoOrganizer: eventID=%d, userID=%s", req.GetEventId(), req.GetUserId())
+	event, err := h.service.RemoveCoOrganizer(ctx, uint(req.GetEventId()), req.GetRequesterId(), req.GetUserId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.CoOrganizerResponse{Success: true, Event: toProto(event)}, nil
+}
+
+// toStatus maps the service's sentinel errors to gRPC statuses
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, service.ErrValidation):
+		return grpcerrors.InvalidInput(err.Error(), nil)
+	case errors.Is(err, service.ErrNotFound):
+		return grpcerrors.NotFound("event")
+	case errors.Is(err, service.ErrForbidden):
+		return grpcerrors.PermissionDenied(err.Error())
+	default:
+		return grpcerrors.HandleError(err)
+	}
+}
+
+func toProto(event *contracts.GetEventResponse) *pb.Event {
+	return &pb.Event{
+		EventId:        uint64(event.EventID),
+		Name:           event.Name,
+		Detail:         event.Detail,
+		Location:       event.Location,
+		Date:           event.Date,
+		JoiningCode:    event.JoiningCode,
+		OrganizerId:    event.OrganizerId,
+		CoOrganizerIds: event.CoOrganizerIDs,
+	}
+}
+
+func toProtoList(events []*contracts.GetEventResponse) []*pb.Event {
+	list := make([]*pb.Event, 0, len(events))
+	for _, event := range events {
+		list = append(list, toProto(event))
+	}
+	return list
+}
@@ -8,11 +8,18 @@ import (
 
 type Event struct {
 	gorm.Model
-	Name        string `gorm:"type:varchar(255);not null"`
-	Detail      string
-	Location    string
-	Date        time.Time
-	OrganizerID string `gorm:"type:varchar(36);index"`
-	JoiningCode string
-	DeletedAt   gorm.DeletedAt `gorm:"index"`
+	Name         string `gorm:"type:varchar(255);not null"`
+	Detail       string
+	Location     string
+	Date         time.Time
+	OrganizerID  string `gorm:"type:varchar(36);index"`
+	JoiningCode  string
+	CoOrganizers []CoOrganizer  `gorm:"constraint:OnDelete:CASCADE"`
+	DeletedAt    gorm.DeletedAt `gorm:"index"`
+}
+
+// CoOrganizer is a user the organizer lets manage the event with them
+type CoOrganizer struct {
+	EventID uint   `gorm:"primaryKey"`
+	UserID  string `gorm:"type:varchar(36);primaryKey;index"`
 }
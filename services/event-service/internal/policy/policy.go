@@ -0,0 +1,88 @@
+// Package policy decides what a user may do with an event, based on the role
+// they hold in it. The service asks it before every change and before showing
+// anything only organizers should see.
+package policy
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/wutthichod/sa-connext/services/event-service/internal/models"
+)
+
+// ErrForbidden is returned when the user's role does not allow the action
+var ErrForbidden = errors.New("forbidden")
+
+// Role is a user's relationship to an event; higher roles may do more
+type Role int
+
+const (
+	// Guest is anyone who does not organize the event
+	Guest Role = iota
+	CoOrganizer
+	Organizer
+)
+
+func (r Role) String() string {
+	switch r {
+	case Organizer:
+		return "organizer"
+	case CoOrganizer:
+		return "co-organizer"
+	default:
+		return "guest"
+	}
+}
+
+// Action is something done to an event
+type Action string
+
+const (
+	ViewJoiningCode  Action = "view the joining code of"
+	Update           Action = "update"
+	Delete           Action = "delete"
+	ManageOrganizers Action = "manage the organizers of"
+)
+
+// minimum is the lowest role allowed each action
+var minimum = map[Action]Role{
+	ViewJoiningCode:  CoOrganizer,
+	Update:           CoOrganizer,
+	Delete:           CoOrganizer,
+	ManageOrganizers: Organizer,
+}
+
+// RoleOf returns the role userID holds in event. CoOrganizers must be loaded.
+func RoleOf(event *models.Event, userID string) Role {
+	if userID == "" {
+		return Guest
+	}
+	if event.OrganizerID == userID {
+		return Organizer
+	}
+	for _, co := range event.CoOrganizers {
+		if co.UserID == userID {
+			return CoOrganizer
+		}
+	}
+	return Guest
+}
+
+// Allows reports whether userID may perform action on event
+func Allows(event *models.Event, userID string, action Action) bool {
+	required, ok := minimum[action]
+	return ok && RoleOf(event, userID) >= required
+}
+
+// Authorize returns an error wrapping ErrForbidden unless userID may perform
+// action on event
+func Authorize(event *models.Event, userID string, action Action) error {
+	if Allows(event, userID, action) {
+		return nil
+	}
+	who := "organizers"
+	if minimum[action] == Organizer {
+		who = "the organizer"
+	}
+	return fmt.Errorf("%w: only %s can %s this event", ErrForbidden, who, action)
+}
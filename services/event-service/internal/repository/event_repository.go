@@ -6,6 +6,7 @@ import (
 
 	"github.com/wutthichod/sa-connext/services/event-service/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // EventRepositoryInterface defines the methods for database interaction
@@ -17,6 +18,9 @@ type EventRepositoryInterface interface {
 	GetByJoiningCode(ctx context.Context, joiningCode string) (*models.Event, error)
 	GetByUserID(ctx context.Context, userID uint) ([]*models.Event, error)
 	DeleteByID(ctx context.Context, id uint) error
+	Update(ctx context.Context, event *models.Event) error
+	AddCoOrganizer(ctx context.Context, eventID uint, userID string) error
+	RemoveCoOrganizer(ctx context.Context, eventID uint, userID string) error
 }
 
 // eventRepository implements the interface using GORM
@@ -39,7 +43,7 @@ func (r *eventRepository) Create(ctx context.Context, event *models.Event) error
 func (r *eventRepository) GetByID(ctx context.Context, id uint) (*models.Event, error) {
 	var event models.Event
 	// GORM will return gorm.ErrRecordNotFound if no record is found
-	err := r.db.WithContext(ctx).First(&event, "id = ?", id).Error
+	err := r.db.WithContext(ctx).Preload("CoOrganizers").First(&event, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -49,7 +53,7 @@ func (r *eventRepository) GetByID(ctx context.Context, id uint) (*models.Event,
 // GetAll retrieves all events from the database
 func (r *eventRepository) GetAll(ctx context.Context) ([]*models.Event, error) {
 	var events []*models.Event
-	err := r.db.WithContext(ctx).Find(&events).Error
+	err := r.db.WithContext(ctx).Preload("CoOrganizers").Find(&events).Error
 	if err != nil {
 		return nil, err
 	}
@@ -79,10 +83,14 @@ func (r *eventRepository) GetByJoiningCode(ctx context.Context, joiningCode stri
 	return &event, nil
 }
 
+// GetByUserID finds the events the user organizes or co-organizes
 func (r *eventRepository) GetByUserID(ctx context.Context, userID uint) ([]*models.Event, error) {
 	var events []*models.Event
+	id := fmt.Sprintf("%d", userID)
 	err := r.db.WithContext(ctx).
-		Where("organizer_id = ?", fmt.Sprintf("%d", userID)).
+		Preload("CoOrganizers").
+		Where("organizer_id = ?", id).
+		Or("id IN (?)", r.db.Model(&models.CoOrganizer{}).Select("event_id").Where("user_id = ?", id)).
 		Find(&events).Error
 	if err != nil {
 		return nil, err
@@ -96,4 +104,32 @@ func (r *eventRepository) DeleteByID(ctx context.Context, id uint) error {
 		return err
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// Update saves an event's details. Its organizers and joining code are left alone.
+func (r *eventRepository) Update(ctx context.Context, event *models.Event) error {
+	return r.db.WithContext(ctx).Model(event).
+		Select("Name", "Detail", "Location", "Date").
+		Updates(event).Error
+}
+
+// AddCoOrganizer makes the user a co-organizer of the event; doing it twice is a no-op
+func (r *eventRepository) AddCoOrganizer(ctx context.Context, eventID uint, userID string) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.CoOrganizer{EventID: eventID, UserID: userID}).Error
+}
+
+// RemoveCoOrganizer returns gorm.ErrRecordNotFound if the user was not a co-organizer
+func (r *eventRepository) RemoveCoOrganizer(ctx context.Context, eventID uint, userID string) error {
+	result := r.db.WithContext(ctx).
+		Where("event_id = ? AND user_id = ?", eventID, userID).
+		Delete(&models.CoOrganizer{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
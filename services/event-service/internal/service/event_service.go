@@ -9,8 +9,11 @@ import (
 
 	"github.com/wutthichod/sa-connext/services/event-service/internal/clients"
 	"github.com/wutthichod/sa-connext/services/event-service/internal/models"
+	"github.com/wutthichod/sa-connext/services/event-service/internal/policy"
 	"github.com/wutthichod/sa-connext/services/event-service/internal/repository"
 	"github.com/wutthichod/sa-connext/shared/contracts"
+	"github.com/wutthichod/sa-connext/shared/correlation"
+	"github.com/wutthichod/sa-connext/shared/metrics"
 	pb "github.com/wutthichod/sa-connext/shared/proto/user"
 	"github.com/wutthichod/sa-connext/shared/utils"
 	"gorm.io/gorm"
@@ -21,23 +24,39 @@ var (
 	ErrNotFound   = errors.New("event not found")
 )
 
+// ErrForbidden is returned when the requester's role in an event does not
+// allow what they asked for
+var ErrForbidden = policy.ErrForbidden
+
+// EventServiceInterface takes the ID of the requesting user wherever the
+// result depends on their role in the event
 type EventServiceInterface interface {
-	GetEvent(ctx context.Context, id uint) (*contracts.GetEventResponse, error)
-	GetAllEvents(ctx context.Context) ([]*contracts.GetEventResponse, error)
+	GetEvent(ctx context.Context, id uint, requesterID string) (*contracts.GetEventResponse, error)
+	GetAllEvents(ctx context.Context, requesterID string) ([]*contracts.GetEventResponse, error)
 	CreateEvent(ctx context.Context, req *contracts.CreateEventRequest) (*contracts.CreateEventResponse, error)
 	JoinEvent(ctx context.Context, req *contracts.JoinEventRequest) (bool, uint, error)
 	GetEventsByUserID(ctx context.Context, userID uint) ([]*contracts.GetEventResponse, error)
-	DeleteByID(ctx context.Context, id uint) error
+	DeleteByID(ctx context.Context, id uint, requesterID string) error
+	ForceDeleteByID(ctx context.Context, id uint, adminID, reason string) error
+	UpdateEvent(ctx context.Context, id uint, requesterID string, req *contracts.UpdateEventRequest) (*contracts.GetEventResponse, error)
+	AddCoOrganizer(ctx context.Context, id uint, requesterID, userID string) (*contracts.GetEventResponse, error)
+	RemoveCoOrganizer(ctx context.Context, id uint, requesterID, userID string) (*contracts.GetEventResponse, error)
+}
+
+// Publisher delivers messages to the broker; *messaging.RabbitMQ implements it
+type Publisher interface {
+	PublishMessage(ctx context.Context, exchange, routingKey string, message interface{}) error
 }
 
 type eventService struct {
 	userClient *clients.UserClient
 	repo       repository.EventRepositoryInterface
+	publisher  Publisher
 }
 
 // NewEventService creates a new service instance
-func NewEventService(userClient *clients.UserClient, repo repository.EventRepositoryInterface) EventServiceInterface {
-	return &eventService{userClient: userClient, repo: repo}
+func NewEventService(userClient *clients.UserClient, repo repository.EventRepositoryInterface, publisher Publisher) EventServiceInterface {
+	return &eventService{userClient: userClient, repo: repo, publisher: publisher}
 }
 
 // CreateEvent handles the logic for creating a new event
@@ -97,6 +116,8 @@ func (s *eventService) CreateEvent(ctx context.Context, req *contracts.CreateEve
 	}
 
 	// 5. Transform Response (DB Model -> Response DTO)
+	metrics.EventsCreated.Inc()
+	s.publishChanged(ctx, contracts.EventCreatedRouting, event.ID)
 	return &contracts.CreateEventResponse{
 		EventID:     event.ID,
 		JoiningCode: joiningCode,
@@ -104,32 +125,19 @@ func (s *eventService) CreateEvent(ctx context.Context, req *contracts.CreateEve
 }
 
 // GetEvent handles the logic for retrieving a single event
-func (s *eventService) GetEvent(ctx context.Context, id uint) (*contracts.GetEventResponse, error) {
+func (s *eventService) GetEvent(ctx context.Context, id uint, requesterID string) (*contracts.GetEventResponse, error) {
 	// 1. Call Repository
-	event, err := s.repo.GetByID(ctx, id)
+	event, err := s.getEvent(ctx, id)
 	if err != nil {
-		// Check for GORM's specific "not found" error
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, ErrNotFound
-		}
-		// Otherwise, it's an unexpected internal error
-		return nil, fmt.Errorf("failed to get event from db: %w", err)
+		return nil, err
 	}
 
 	// 2. Transform Response (DB Model -> Response DTO)
-	return &contracts.GetEventResponse{
-		EventID:     event.ID,
-		Name:        event.Name,
-		Detail:      event.Detail,
-		Location:    event.Location,
-		Date:        event.Date.Format(time.RFC3339),
-		JoiningCode: event.JoiningCode,
-		OrganizerId: event.OrganizerID,
-	}, nil
+	return project(event, requesterID), nil
 }
 
 // GetAllEvents handles the logic for retrieving all events
-func (s *eventService) GetAllEvents(ctx context.Context) ([]*contracts.GetEventResponse, error) {
+func (s *eventService) GetAllEvents(ctx context.Context, requesterID string) ([]*contracts.GetEventResponse, error) {
 	// 1. Call Repository
 	events, err := s.repo.GetAll(ctx)
 	if err != nil {
@@ -137,20 +145,7 @@ func (s *eventService) GetAllEvents(ctx context.Context) ([]*contracts.GetEventR
 	}
 
 	// 2. Transform Response (DB Model -> Response DTO)
-	var responses []*contracts.GetEventResponse
-	for _, event := range events {
-		responses = append(responses, &contracts.GetEventResponse{
-			EventID:     event.ID,
-			Name:        event.Name,
-			Detail:      event.Detail,
-			Location:    event.Location,
-			Date:        event.Date.Format(time.RFC3339),
-			JoiningCode: event.JoiningCode,
-			OrganizerId: event.OrganizerID,
-		})
-	}
-
-	return responses, nil
+	return projectAll(events, requesterID), nil
 }
 
 func (s *eventService) JoinEvent(ctx context.Context, req *contracts.JoinEventRequest) (bool, uint, error) {
@@ -171,6 +166,9 @@ func (s *eventService) JoinEvent(ctx context.Context, req *contracts.JoinEventRe
 	if err != nil {
 		return false, 0, err
 	}
+	if result.Success {
+		metrics.EventJoins.Inc()
+	}
 
 	return result.Success, event.ID, nil
 }
@@ -180,26 +178,171 @@ func (s *eventService) GetEventsByUserID(ctx context.Context, userID uint) ([]*c
 	if err != nil {
 		return nil, fmt.Errorf("failed to get events from db: %w", err)
 	}
-	var responses []*contracts.GetEventResponse
-	for _, event := range events {
-		responses = append(responses, &contracts.GetEventResponse{
-			EventID:     event.ID,
-			Name:        event.Name,
-			Detail:      event.Detail,
-			Location:    event.Location,
-			Date:        event.Date.Format(time.RFC3339),
-			JoiningCode: event.JoiningCode,
-			OrganizerId: event.OrganizerID,
-		})
+	return projectAll(events, strconv.FormatUint(uint64(userID), 10)), nil
+}
+
+func (s *eventService) DeleteByID(ctx context.Context, id uint, requesterID string) error {
+	event, err := s.getEvent(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := policy.Authorize(event, requesterID, policy.Delete); err != nil {
+		return err
 	}
 
-	return responses, nil
+	err = s.repo.DeleteByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete event from db: %w", err)
+	}
+	s.publishChanged(ctx, contracts.EventDeletedRouting, id)
+	return nil
 }
 
-func (s *eventService) DeleteByID(ctx context.Context, id uint) error {
-	err := s.repo.DeleteByID(ctx, id)
-	if err != nil {
+// ForceDeleteByID lets a platform admin take down any event; the gateway
+// has already checked the admin's role
+func (s *eventService) ForceDeleteByID(ctx context.Context, id uint, adminID, reason string) error {
+	if _, err := s.getEvent(ctx, id); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteByID(ctx, id); err != nil {
 		return fmt.Errorf("failed to delete event from db: %w", err)
 	}
+	correlation.Printf(ctx, "Admin %s force-deleted event %d: %s", adminID, id, reason)
+	s.publishChanged(ctx, contracts.EventDeletedRouting, id)
 	return nil
 }
+
+// UpdateEvent replaces an event's details; its organizers and joining code stay
+func (s *eventService) UpdateEvent(ctx context.Context, id uint, requesterID string, req *contracts.UpdateEventRequest) (*contracts.GetEventResponse, error) {
+	if req.Name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrValidation)
+	}
+	eventDate, err := time.Parse(time.RFC3339, req.Date)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid date format, must be RFC3339", ErrValidation)
+	}
+
+	event, err := s.getEvent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := policy.Authorize(event, requesterID, policy.Update); err != nil {
+		return nil, err
+	}
+
+	event.Name = req.Name
+	event.Detail = req.Detail
+	event.Location = req.Location
+	event.Date = eventDate
+	if err := s.repo.Update(ctx, event); err != nil {
+		return nil, fmt.Errorf("failed to update event in db: %w", err)
+	}
+	s.publishChanged(ctx, contracts.EventUpdatedRouting, id)
+	return project(event, requesterID), nil
+}
+
+// AddCoOrganizer lets userID manage the event alongside its organizer
+func (s *eventService) AddCoOrganizer(ctx context.Context, id uint, requesterID, userID string) (*contracts.GetEventResponse, error) {
+	event, err := s.getEvent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := policy.Authorize(event, requesterID, policy.ManageOrganizers); err != nil {
+		return nil, err
+	}
+	if userID == event.OrganizerID {
+		return nil, fmt.Errorf("%w: the organizer cannot also be a co-organizer", ErrValidation)
+	}
+	if policy.RoleOf(event, userID) == policy.CoOrganizer {
+		return project(event, requesterID), nil
+	}
+
+	// Fails with NotFound for unknown users, which is passed on as is
+	if _, err := s.userClient.GetUserById(ctx, &pb.GetUserByIdRequest{UserId: userID}); err != nil {
+		return nil, err
+	}
+	if err := s.repo.AddCoOrganizer(ctx, id, userID); err != nil {
+		return nil, fmt.Errorf("failed to add co-organizer in db: %w", err)
+	}
+	event.CoOrganizers = append(event.CoOrganizers, models.CoOrganizer{EventID: id, UserID: userID})
+	s.publishChanged(ctx, contracts.EventUpdatedRouting, id)
+	return project(event, requesterID), nil
+}
+
+func (s *eventService) RemoveCoOrganizer(ctx context.Context, id uint, requesterID, userID string) (*contracts.GetEventResponse, error) {
+	event, err := s.getEvent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := policy.Authorize(event, requesterID, policy.ManageOrganizers); err != nil {
+		return nil, err
+	}
+	if policy.RoleOf(event, userID) != policy.CoOrganizer {
+		return nil, fmt.Errorf("%w: user %s is not a co-organizer of this event", ErrValidation, userID)
+	}
+
+	if err := s.repo.RemoveCoOrganizer(ctx, id, userID); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to remove co-organizer from db: %w", err)
+	}
+	remaining := event.CoOrganizers[:0]
+	for _, co := range event.CoOrganizers {
+		if co.UserID != userID {
+			remaining = append(remaining, co)
+		}
+	}
+	event.CoOrganizers = remaining
+	s.publishChanged(ctx, contracts.EventUpdatedRouting, id)
+	return project(event, requesterID), nil
+}
+
+// getEvent loads an event with its co-organizers
+func (s *eventService) getEvent(ctx context.Context, id uint) (*models.Event, error) {
+	event, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		// Check for GORM's specific "not found" error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		// Otherwise, it's an unexpected internal error
+		return nil, fmt.Errorf("failed to get event from db: %w", err)
+	}
+	return event, nil
+}
+
+// project shows event the way requesterID may see it
+func project(event *models.Event, requesterID string) *contracts.GetEventResponse {
+	coOrganizerIDs := make([]string, 0, len(event.CoOrganizers))
+	for _, co := range event.CoOrganizers {
+		coOrganizerIDs = append(coOrganizerIDs, co.UserID)
+	}
+	response := &contracts.GetEventResponse{
+		EventID:        event.ID,
+		Name:           event.Name,
+		Detail:         event.Detail,
+		Location:       event.Location,
+		Date:           event.Date.Format(time.RFC3339),
+		OrganizerId:    event.OrganizerID,
+		CoOrganizerIDs: coOrganizerIDs,
+	}
+	if policy.Allows(event, requesterID, policy.ViewJoiningCode) {
+		response.JoiningCode = event.JoiningCode
+	}
+	return response
+}
+
+func projectAll(events []*models.Event, requesterID string) []*contracts.GetEventResponse {
+	responses := make([]*contracts.GetEventResponse, 0, len(events))
+	for _, event := range events {
+		responses = append(responses, project(event, requesterID))
+	}
+	return responses
+}
+
+// publishChanged lets caches of event data (e.g. the gateway's) know they
+// are stale. A lost message only delays that until the entries expire.
+func (s *eventService) publishChanged(ctx context.Context, routingKey string, id uint) {
+	changed := contracts.EventChangedEvent{EventID: strconv.FormatUint(uint64(id), 10)}
+	if err := s.publisher.PublishMessage(ctx, contracts.EventExchange, routingKey, changed); err != nil {
+		correlation.Printf(ctx, "Failed to publish %s: %v", routingKey, err)
+	}
+}
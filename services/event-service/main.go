@@ -1,17 +1,28 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net"
+	"net/http"
+	"time"
 
-	"github.com/gofiber/fiber/v2"
 	"github.com/joho/godotenv"
-	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/wutthichod/sa-connext/services/event-service/internal/clients"
 	"github.com/wutthichod/sa-connext/services/event-service/internal/handler"
 	"github.com/wutthichod/sa-connext/services/event-service/internal/models"
 	"github.com/wutthichod/sa-connext/services/event-service/internal/repository"
 	"github.com/wutthichod/sa-connext/services/event-service/internal/service"
 	"github.com/wutthichod/sa-connext/shared/config"
+	"github.com/wutthichod/sa-connext/shared/contracts"
+	"github.com/wutthichod/sa-connext/shared/correlation"
+	"github.com/wutthichod/sa-connext/shared/health"
+	"github.com/wutthichod/sa-connext/shared/lifecycle"
+	"github.com/wutthichod/sa-connext/shared/messaging"
+	"github.com/wutthichod/sa-connext/shared/metrics"
+	"github.com/wutthichod/sa-connext/shared/tracing"
+	"google.golang.org/grpc"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -25,12 +36,28 @@ func main() {
 		log.Fatal(err)
 	}
 
+	ctx := context.Background()
+	provider, err := tracing.Init(ctx, "event-service", config.Tracing())
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	db, err := gorm.Open(postgres.Open(config.Database().DSN), &gorm.Config{})
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
+	if err := tracing.InstrumentGORM(db); err != nil {
+		log.Fatalf("Failed to instrument database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Fatalf("Failed to get database handle: %v", err)
+	}
+	if err := metrics.RegisterDB("event-db", sqlDB); err != nil {
+		log.Fatalf("Failed to register database metrics: %v", err)
+	}
 
-	if err := db.AutoMigrate(&models.Event{}); err != nil {
+	if err := db.AutoMigrate(&models.Event{}, &models.CoOrganizer{}); err != nil {
 		log.Fatalf("Failed to migrate database: %v", err)
 	}
 
@@ -39,23 +66,69 @@ func main() {
 		log.Fatalf("Failed to create user client: %v", err)
 	}
 
+	rb, err := messaging.NewRabbitMQ(config.RABBITMQ().URI)
+	if err != nil {
+		log.Fatalf("Failed to connect to RabbitMQ: %v", err)
+	}
+	if err := rb.DeclareExchange(contracts.EventExchange, "topic", true); err != nil {
+		log.Fatalf("Failed to declare event exchange: %v", err)
+	}
+
 	eventRepo := repository.NewEventRepository(db)
-	eventService := service.NewEventService(userClient, eventRepo)
-	eventHandler := handler.NewEventHandler(eventService)
+	eventService := service.NewEventService(userClient, eventRepo, rb)
+
+	lis, err := net.Listen("tcp", config.App().Event)
+	if err != nil {
+		log.Fatalf("Failed to listen: %v", err)
+	}
+
+	metricsServer := metrics.NewServer(config.Metrics().Addr)
+
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			correlation.UnaryServerInterceptor(),
+			metrics.UnaryServerInterceptor(),
+		),
+		tracing.ServerOption(),
+	)
+	handler.NewGRPCHandler(server, eventService)
+
+	lc := lifecycle.New(config.Shutdown().Timeout)
 
-	app := fiber.New()
+	checker := health.NewChecker()
+	checker.Add("postgres", health.SQL(sqlDB))
+	checker.Add("user-service", userClient.Check)
+	checker.Add("rabbitmq", rb.Check)
+	healthServer := health.RegisterGRPC(lc.Context(), server, checker, 10*time.Second)
 
-	// Logger middleware - logs all requests
-	app.Use(logger.New(logger.Config{
-		Format:     "${cyan}[${time}] ${white}${pid} ${red}${status} ${blue}[${method}] ${white}${path}\n",
-		TimeFormat: "02-Jan-2006",
-		TimeZone:   "UTC",
-	}))
+	log.Printf("Event Service listening on %v", config.App().Event)
+	lc.Go("grpc", func() error { return server.Serve(lis) })
+	lc.Go("metrics", func() error {
+		if err := metricsServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	})
 
-	eventHandler.RegisterRoutes(app)
+	// Report NOT_SERVING first so no new traffic is routed here while draining
+	lc.OnShutdown("health", func(ctx context.Context) error {
+		healthServer.Shutdown()
+		return nil
+	})
+	lc.OnShutdown("grpc", lifecycle.GRPCServer(server))
+	lc.OnShutdown("user client", func(ctx context.Context) error {
+		userClient.Close()
+		return nil
+	})
+	lc.OnShutdown("rabbitmq", func(ctx context.Context) error {
+		rb.Close()
+		return nil
+	})
+	lc.OnShutdown("postgres", func(ctx context.Context) error { return sqlDB.Close() })
+	lc.OnShutdown("metrics", metricsServer.Shutdown)
+	lc.OnShutdown("tracing", provider.Shutdown)
 
-	log.Printf("Event Service starting on %v", config.App().Event)
-	if err := app.Listen(config.App().Event); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	if err := lc.Wait(); err != nil {
+		log.Fatalf("Shutdown failed: %v", err)
 	}
 }
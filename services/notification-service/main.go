@@ -1,11 +1,17 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
 
 	"github.com/joho/godotenv"
 	"github.com/wutthichod/sa-connext/shared/config"
+	"github.com/wutthichod/sa-connext/shared/lifecycle"
 	"github.com/wutthichod/sa-connext/shared/messaging"
+	"github.com/wutthichod/sa-connext/shared/metrics"
+	"github.com/wutthichod/sa-connext/shared/tracing"
 )
 
 func main() {
@@ -14,11 +20,17 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to initialize config: %v", err)
 	}
+
+	ctx := context.Background()
+	provider, err := tracing.Init(ctx, "notification-service", config.Tracing())
+	if err != nil {
+		log.Fatalf("Failed to initialize tracing: %v", err)
+	}
+
 	rb, err := messaging.NewRabbitMQ(config.RABBITMQ().URI)
 	if err != nil {
 		log.Fatalf("Failed to connect to RabbitMQ: %v", err)
 	}
-	defer rb.Close()
 
 	queueName, err := rb.SetupQueue(
 		"email_queue",           // queue name
@@ -36,5 +48,26 @@ func main() {
 	if err := emailConsumer.Start(); err != nil {
 		log.Fatalf("Failed to start email consumer: %v", err)
 	}
-	select {} // Block forever
+
+	lc := lifecycle.New(config.Shutdown().Timeout)
+	metricsServer := metrics.NewServer(config.Metrics().Addr)
+	lc.Go("metrics", func() error {
+		if err := metricsServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	})
+
+	// Let emails that are already being sent finish before disconnecting
+	lc.OnShutdown("consumers", rb.StopConsumers)
+	lc.OnShutdown("rabbitmq", func(ctx context.Context) error {
+		rb.Close()
+		return nil
+	})
+	lc.OnShutdown("metrics", metricsServer.Shutdown)
+	lc.OnShutdown("tracing", provider.Shutdown)
+
+	if err := lc.Wait(); err != nil {
+		log.Fatalf("Shutdown failed: %v", err)
+	}
 }
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/wutthichod/sa-connext/services/user-service/internal/repository"
+	"github.com/wutthichod/sa-connext/services/user-service/pkg/database"
+	"github.com/wutthichod/sa-connext/shared/auth"
+)
+
+// grantAdminCmd makes a user a platform admin. Admins cannot grant the role
+// through the API, so the first one is made here. The user has to log in
+// again for their token to carry the role.
+var grantAdminCmd = &cobra.Command{
+	Use:   "grant-admin <email>",
+	Short: "Give the user with the email the admin role",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		role := auth.RoleAdmin
+		if revoke, _ := cmd.Flags().GetBool("revoke"); revoke {
+			role = auth.RoleUser
+		}
+
+		cfg, err := getConfigFromCmd(cmd)
+		if err != nil {
+			return err
+		}
+		db, err := database.InitDatabase(cfg.Database())
+		if err != nil {
+			return err
+		}
+
+		found, err := repository.NewRepo(db).SetRoleByEmail(cmd.Context(), args[0], role)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return fmt.Errorf("no user with email %s", args[0])
+		}
+		cmd.Printf("%s now has the %s role\n", args[0], role)
+		return nil
+	},
+}
+
+func init() {
+	grantAdminCmd.Flags().Bool("revoke", false, "take the admin role away instead")
+}
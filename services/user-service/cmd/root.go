@@ -46,7 +46,7 @@ func Execute() {
 }
 
 func init() {
-	RootCmd.AddCommand(serveCmd, setupCmd)
+	RootCmd.AddCommand(serveCmd, setupCmd, grantAdminCmd)
 }
 
 func getConfigFromCmd(cmd *cobra.Command) (config.Config, error) {
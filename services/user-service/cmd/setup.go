@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"log"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/wutthichod/sa-connext/services/user-service/internal/models"
@@ -26,26 +27,55 @@ var setupCmd = &cobra.Command{
 
 		// TODO: run migrations
 		// db.Migrator().DropTable(&models.Province{},&models.District{},&models.SubDistrict{})
-		err = db.Migrator().DropTable(
-			&models.User{},
-			&models.Contact{},
-			&models.Education{},
-			&models.Interest{},
-		)
-		if err != nil {
-			log.Fatalf("failed to drop tables: %v", err)
+		if keep, _ := cmd.Flags().GetBool("keep-data"); !keep {
+			err = db.Migrator().DropTable(
+				&models.User{},
+				&models.Contact{},
+				&models.Education{},
+				&models.Interest{},
+				&models.DeviceKey{},
+				&models.RefreshToken{},
+				&models.RevokedToken{},
+				&models.SigningKey{},
+				&models.EmailVerification{},
+			)
+			if err != nil {
+				log.Fatalf("failed to drop tables: %v", err)
+			}
+			log.Println("All tables dropped successfully")
 		}
-		log.Println("All tables dropped successfully")
+
+		// Users who registered before emails were verified never got the
+		// chance to; they are taken as verified rather than locked out
+		backfillVerified := db.Migrator().HasTable(&models.User{}) && !db.Migrator().HasColumn(&models.User{}, "EmailVerifiedAt")
+
 		err = db.AutoMigrate(
 			&models.User{},
 			&models.Contact{},
 			&models.Education{},
 			&models.Interest{},
+			&models.DeviceKey{},
+			&models.RefreshToken{},
+			&models.RevokedToken{},
+			&models.SigningKey{},
+			&models.EmailVerification{},
 		)
 		if err != nil {
 			log.Fatalf("failed to migrate tables: %v", err)
 		}
+
+		if backfillVerified {
+			res := db.Model(&models.User{}).Where("email_verified_at IS NULL").Update("email_verified_at", time.Now())
+			if res.Error != nil {
+				log.Fatalf("failed to backfill email_verified_at: %v", res.Error)
+			}
+			log.Printf("Marked %d existing users as verified", res.RowsAffected)
+		}
 		return nil
 
 	},
 }
+
+func init() {
+	setupCmd.Flags().Bool("keep-data", false, "migrate the tables in place instead of dropping them first")
+}
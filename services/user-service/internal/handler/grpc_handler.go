@@ -23,29 +23,90 @@ func NewGRPCHandler(server *grpc.Server, service service.Service) *gRPCHandler {
 }
 
 func (h *gRPCHandler) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.CreateUserResponse, error) {
-	jwtToken, err := h.service.CreateUser(ctx, req)
+	tokens, err := h.service.CreateUser(ctx, req)
 	if err != nil {
 		return nil, grpcerrors.HandleError(err)
 	}
 
 	return &pb.CreateUserResponse{
-		Success:  true,
-		JwtToken: *jwtToken,
+		Success:          true,
+		JwtToken:         tokens.Access,
+		RefreshToken:     tokens.Refresh,
+		ExpiresIn:        int64(tokens.AccessExpiresIn.Seconds()),
+		RefreshExpiresIn: int64(tokens.RefreshExpiresIn.Seconds()),
 	}, nil
 }
 
 func (h *gRPCHandler) Login(ctx context.Context, req *pb.LoginRequest) (*pb.LoginResponse, error) {
-	jwtToken, err := h.service.Login(ctx, req)
+	tokens, err := h.service.Login(ctx, req)
 	if err != nil {
 		return nil, grpcerrors.HandleError(err)
 	}
 
 	return &pb.LoginResponse{
-		Success:  true,
-		JwtToken: *jwtToken,
+		Success:          true,
+		JwtToken:         tokens.Access,
+		RefreshToken:     tokens.Refresh,
+		ExpiresIn:        int64(tokens.AccessExpiresIn.Seconds()),
+		RefreshExpiresIn: int64(tokens.RefreshExpiresIn.Seconds()),
 	}, nil
 }
 
+func (h *gRPCHandler) RefreshToken(ctx context.Context, req *pb.RefreshTokenRequest) (*pb.RefreshTokenResponse, error) {
+	tokens, err := h.service.RefreshToken(ctx, req)
+	if err != nil {
+		return nil, grpcerrors.HandleError(err)
+	}
+
+	return &pb.RefreshTokenResponse{
+		Success:          true,
+		JwtToken:         tokens.Access,
+		RefreshToken:     tokens.Refresh,
+		ExpiresIn:        int64(tokens.AccessExpiresIn.Seconds()),
+		RefreshExpiresIn: int64(tokens.RefreshExpiresIn.Seconds()),
+	}, nil
+}
+
+func (h *gRPCHandler) Logout(ctx context.Context, req *pb.LogoutRequest) (*pb.LogoutResponse, error) {
+	result, err := h.service.Logout(ctx, req)
+	if err != nil {
+		return nil, grpcerrors.HandleError(err)
+	}
+	return result, nil
+}
+
+func (h *gRPCHandler) LogoutEverywhere(ctx context.Context, req *pb.LogoutEverywhereRequest) (*pb.LogoutEverywhereResponse, error) {
+	result, err := h.service.LogoutEverywhere(ctx, req)
+	if err != nil {
+		return nil, grpcerrors.HandleError(err)
+	}
+	return result, nil
+}
+
+func (h *gRPCHandler) GetRevocations(ctx context.Context, req *pb.GetRevocationsRequest) (*pb.GetRevocationsResponse, error) {
+	result, err := h.service.GetRevocations(ctx, req)
+	if err != nil {
+		return nil, grpcerrors.HandleError(err)
+	}
+	return result, nil
+}
+
+func (h *gRPCHandler) VerifyEmail(ctx context.Context, req *pb.VerifyEmailRequest) (*pb.VerifyEmailResponse, error) {
+	result, err := h.service.VerifyEmail(ctx, req)
+	if err != nil {
+		return nil, grpcerrors.HandleError(err)
+	}
+	return result, nil
+}
+
+func (h *gRPCHandler) ResendVerification(ctx context.Context, req *pb.ResendVerificationRequest) (*pb.ResendVerificationResponse, error) {
+	result, err := h.service.ResendVerification(ctx, req)
+	if err != nil {
+		return nil, grpcerrors.HandleError(err)
+	}
+	return result, nil
+}
+
 func (h *gRPCHandler) GetUserById(ctx context.Context, req *pb.GetUserByIdRequest) (*pb.GetUserByIdResponse, error) {
 	user, err := h.service.GetUserById(ctx, req)
 	if err != nil {
@@ -54,6 +115,14 @@ func (h *gRPCHandler) GetUserById(ctx context.Context, req *pb.GetUserByIdReques
 	return user, nil
 }
 
+func (h *gRPCHandler) GetUsersByIds(ctx context.Context, req *pb.GetUsersByIdsRequest) (*pb.GetUsersByIdsResponse, error) {
+	users, err := h.service.GetUsersByIds(ctx, req)
+	if err != nil {
+		return nil, grpcerrors.HandleError(err)
+	}
+	return users, nil
+}
+
 func (h *gRPCHandler) GetUsersByEventId(ctx context.Context, req *pb.GetUsersByEventIdRequest) (*pb.GetUsersByEventIdResponse, error) {
 	users, err := h.service.GetUsersByEventId(ctx, req)
 	if err != nil {
@@ -85,3 +154,59 @@ func (h *gRPCHandler) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest)
 	}
 	return result, nil
 }
+
+func (h *gRPCHandler) RegisterDeviceKey(ctx context.Context, req *pb.RegisterDeviceKeyRequest) (*pb.RegisterDeviceKeyResponse, error) {
+	result, err := h.service.RegisterDeviceKey(ctx, req)
+	if err != nil {
+		return nil, grpcerrors.HandleError(err)
+	}
+	return result, nil
+}
+
+func (h *gRPCHandler) GetUserKeys(ctx context.Context, req *pb.GetUserKeysRequest) (*pb.GetUserKeysResponse, error) {
+	result, err := h.service.GetUserKeys(ctx, req)
+	if err != nil {
+		return nil, grpcerrors.HandleError(err)
+	}
+	return result, nil
+}
+
+func (h *gRPCHandler) RemoveDeviceKey(ctx context.Context, req *pb.RemoveDeviceKeyRequest) (*pb.RemoveDeviceKeyResponse, error) {
+	result, err := h.service.RemoveDeviceKey(ctx, req)
+	if err != nil {
+		return nil, grpcerrors.HandleError(err)
+	}
+	return result, nil
+}
+
+func (h *gRPCHandler) ListUsers(ctx context.Context, req *pb.ListUsersRequest) (*pb.ListUsersResponse, error) {
+	result, err := h.service.ListUsers(ctx, req)
+	if err != nil {
+		return nil, grpcerrors.HandleError(err)
+	}
+	return result, nil
+}
+
+func (h *gRPCHandler) SuspendUser(ctx context.Context, req *pb.SuspendUserRequest) (*pb.SuspendUserResponse, error) {
+	result, err := h.service.SuspendUser(ctx, req)
+	if err != nil {
+		return nil, grpcerrors.HandleError(err)
+	}
+	return result, nil
+}
+
+func (h *gRPCHandler) ReinstateUser(ctx context.Context, req *pb.ReinstateUserRequest) (*pb.ReinstateUserResponse, error) {
+	result, err := h.service.ReinstateUser(ctx, req)
+	if err != nil {
+		return nil, grpcerrors.HandleError(err)
+	}
+	return result, nil
+}
+
+func (h *gRPCHandler) GetSuspendedUserIds(ctx context.Context, req *pb.GetSuspendedUserIdsRequest) (*pb.GetSuspendedUserIdsResponse, error) {
+	result, err := h.service.GetSuspendedUserIds(ctx, req)
+	if err != nil {
+		return nil, grpcerrors.HandleError(err)
+	}
+	return result, nil
+}
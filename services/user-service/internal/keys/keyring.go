@@ -0,0 +1,202 @@
+// Package keys holds the private keys user-service signs access tokens with,
+// and publishes their public halves as a JSON Web Key Set. The keys live in
+// the database, so every replica signs with the same ones, and rotate on a
+// schedule: a new key is published ahead of its turn, and a retired one stays
+// published until the last token it signed has expired.
+package keys
+
+import (
+	"context"
+	"crypto"
+	"crypto/cipher"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wutthichod/sa-connext/services/user-service/internal/models"
+	"github.com/wutthichod/sa-connext/services/user-service/internal/repository"
+	"github.com/wutthichod/sa-connext/shared/auth"
+	"github.com/wutthichod/sa-connext/shared/config"
+)
+
+// Path is where the key set is served, as is customary for JWKS
+const Path = "/.well-known/jwks.json"
+
+var errNoSigningKey = errors.New("no signing key is active yet")
+
+type Keyring struct {
+	repo repository.Repository
+	cfg  config.JWT
+	aead cipher.AEAD // seals the private keys stored in the database
+
+	mutex   sync.RWMutex
+	signing *auth.SigningKey
+	jwks    []byte
+}
+
+func New(repo repository.Repository, cfg config.JWT) (*Keyring, error) {
+	if cfg.KeyOverlap < cfg.AccessTTL {
+		return nil, fmt.Errorf("key overlap %s must be at least the access token lifetime %s", cfg.KeyOverlap, cfg.AccessTTL)
+	}
+	if cfg.KeyRotation <= cfg.KeyOverlap {
+		return nil, fmt.Errorf("key rotation %s must be longer than the key overlap %s", cfg.KeyRotation, cfg.KeyOverlap)
+	}
+	aead, err := newAEAD(cfg.KeyEncryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	return &Keyring{repo: repo, cfg: cfg, aead: aead}, nil
+}
+
+// Signer returns the key new tokens are signed with
+func (k *Keyring) Signer() (auth.SigningKey, error) {
+	k.mutex.RLock()
+	defer k.mutex.RUnlock()
+	if k.signing == nil {
+		return auth.SigningKey{}, errNoSigningKey
+	}
+	return *k.signing, nil
+}
+
+// ServeHTTP serves the published keys
+func (k *Keyring) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	k.mutex.RLock()
+	jwks := k.jwks
+	k.mutex.RUnlock()
+	if jwks == nil {
+		http.Error(w, errNoSigningKey.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	w.Write(jwks)
+}
+
+// Sync loads the keys from the database, first adding the next key when its
+// publication is due
+func (k *Keyring) Sync(ctx context.Context) error {
+	now := time.Now()
+	if err := k.repo.DeleteExpiredSigningKeys(ctx, now); err != nil {
+		log.Printf("keys: failed to delete expired keys: %v", err)
+	}
+	stored, err := k.repo.GetSigningKeys(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	// Replicas may add a key at the same moment; the spare key is published
+	// like any other and does no harm
+	if activeFrom, due := k.nextKeyDue(stored, now); due {
+		if err := k.createKey(ctx, activeFrom); err != nil {
+			return err
+		}
+		if stored, err = k.repo.GetSigningKeys(ctx, now); err != nil {
+			return err
+		}
+	}
+	return k.load(stored, now)
+}
+
+// KeepFresh syncs the keys every interval until ctx is done
+func (k *Keyring) KeepFresh(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := k.Sync(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("keys: failed to sync: %v", err)
+		}
+	}
+}
+
+// nextKeyDue reports whether to add a key, and when it should start
+// signing. stored is ordered newest first.
+func (k *Keyring) nextKeyDue(stored []*models.SigningKey, now time.Time) (time.Time, bool) {
+	if len(stored) == 0 {
+		return now, true
+	}
+	next := stored[0].ActiveFrom.Add(k.cfg.KeyRotation)
+	if now.Before(next.Add(-k.cfg.KeyOverlap)) {
+		return time.Time{}, false
+	}
+	// After a long outage the schedule is behind; start right away
+	if next.Before(now) {
+		next = now
+	}
+	return next, true
+}
+
+func (k *Keyring) createKey(ctx context.Context, activeFrom time.Time) error {
+	private, err := auth.NewPrivateKey(k.cfg.Algorithm)
+	if err != nil {
+		return err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return err
+	}
+	kid := uuid.NewString()
+	key := &models.SigningKey{
+		KID:        kid,
+		Algorithm:  k.cfg.Algorithm,
+		PrivateKey: seal(k.aead, kid, der),
+		ActiveFrom: activeFrom,
+		ExpiresAt:  activeFrom.Add(k.cfg.KeyRotation + k.cfg.KeyOverlap),
+	}
+	if err := k.repo.CreateSigningKey(ctx, key); err != nil {
+		return err
+	}
+	log.Printf("keys: added %s key %s, signing from %s", key.Algorithm, key.KID, key.ActiveFrom.Format(time.RFC3339))
+	return nil
+}
+
+// load publishes every stored key and signs with the newest active one
+func (k *Keyring) load(stored []*models.SigningKey, now time.Time) error {
+	var signing *auth.SigningKey
+	set := auth.JWKSet{Keys: make([]auth.JWK, 0, len(stored))}
+	for _, key := range stored {
+		der, err := open(k.aead, key.KID, key.PrivateKey)
+		if err != nil {
+			return fmt.Errorf("key %s: %w", key.KID, err)
+		}
+		parsed, err := x509.ParsePKCS8PrivateKey(der)
+		if err != nil {
+			return fmt.Errorf("key %s: %w", key.KID, err)
+		}
+		private, ok := parsed.(crypto.Signer)
+		if !ok {
+			return fmt.Errorf("key %s: unsupported key type %T", key.KID, parsed)
+		}
+		signingKey := auth.SigningKey{ID: key.KID, Algorithm: key.Algorithm, Private: private}
+		jwk, err := auth.PublicJWK(signingKey)
+		if err != nil {
+			return err
+		}
+		set.Keys = append(set.Keys, jwk)
+		if signing == nil && !key.ActiveFrom.After(now) {
+			signing = &signingKey
+		}
+	}
+	if signing == nil {
+		return errNoSigningKey
+	}
+	jwks, err := json.Marshal(set)
+	if err != nil {
+		return err
+	}
+
+	k.mutex.Lock()
+	k.signing, k.jwks = signing, jwks
+	k.mutex.Unlock()
+	return nil
+}
@@ -0,0 +1,244 @@
+package keys
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wutthichod/sa-connext/services/user-service/internal/models"
+	"github.com/wutthichod/sa-connext/services/user-service/internal/repository"
+	"github.com/wutthichod/sa-connext/shared/auth"
+	"github.com/wutthichod/sa-connext/shared/config"
+)
+
+var testConfig = config.JWT{
+	AccessTTL:        15 * time.Minute,
+	Algorithm:        auth.AlgorithmEdDSA,
+	KeyRotation:      24 * time.Hour,
+	KeyOverlap:       time.Hour,
+	KeyEncryptionKey: testSecret(1),
+}
+
+// fakeRepo stores signing keys in memory. Anything else panics on the nil
+// Repository it embeds.
+type fakeRepo struct {
+	repository.Repository
+
+	mutex sync.Mutex
+	keys  []*models.SigningKey
+}
+
+func (r *fakeRepo) CreateSigningKey(ctx context.Context, key *models.SigningKey) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.keys = append(r.keys, key)
+	return nil
+}
+
+func (r *fakeRepo) GetSigningKeys(ctx context.Context, now time.Time) ([]*models.SigningKey, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	var keys []*models.SigningKey
+	for _, key := range r.keys {
+		if key.ExpiresAt.After(now) {
+			keys = append(keys, key)
+		}
+	}
+	slices.SortFunc(keys, func(a, b *models.SigningKey) int { return b.ActiveFrom.Compare(a.ActiveFrom) })
+	return keys, nil
+}
+
+func (r *fakeRepo) DeleteExpiredSigningKeys(ctx context.Context, now time.Time) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.keys = slices.DeleteFunc(r.keys, func(key *models.SigningKey) bool { return !key.ExpiresAt.After(now) })
+	return nil
+}
+
+// age moves every stored key d into the past, as if that much time passed
+func (r *fakeRepo) age(d time.Duration) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, key := range r.keys {
+		key.ActiveFrom = key.ActiveFrom.Add(-d)
+		key.ExpiresAt = key.ExpiresAt.Add(-d)
+	}
+}
+
+func newTestKeyring(t *testing.T) (*Keyring, *fakeRepo) {
+	t.Helper()
+	repo := &fakeRepo{}
+	k, err := New(repo, testConfig)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return k, repo
+}
+
+func mustSync(t *testing.T, k *Keyring) {
+	t.Helper()
+	if err := k.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+}
+
+// published fetches the key set the keyring serves
+func published(t *testing.T, k *Keyring) map[string]crypto.PublicKey {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	k.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, Path, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("serving the key set: %d %s", rec.Code, rec.Body)
+	}
+	var set auth.JWKSet
+	if err := json.Unmarshal(rec.Body.Bytes(), &set); err != nil {
+		t.Fatalf("decoding the key set: %v", err)
+	}
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		key, err := jwk.PublicKey()
+		if err != nil {
+			t.Fatalf("decoding key %s: %v", jwk.Kid, err)
+		}
+		keys[jwk.Kid] = key
+	}
+	return keys
+}
+
+// keySet serves published keys to auth.ValidateToken
+type keySet map[string]crypto.PublicKey
+
+func (s keySet) PublicKey(kid string) (crypto.PublicKey, error) {
+	if key, ok := s[kid]; ok {
+		return key, nil
+	}
+	return nil, auth.ErrUnknownKey
+}
+
+func signer(t *testing.T, k *Keyring) auth.SigningKey {
+	t.Helper()
+	key, err := k.Signer()
+	if err != nil {
+		t.Fatalf("Signer: %v", err)
+	}
+	return key
+}
+
+func TestNextKeyDue(t *testing.T) {
+	k, _ := newTestKeyring(t)
+	newest := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	stored := []*models.SigningKey{{ActiveFrom: newest}}
+
+	tests := []struct {
+		name       string
+		stored     []*models.SigningKey
+		now        time.Time
+		wantDue    bool
+		wantActive time.Time
+	}{
+		{name: "no keys", now: newest, wantDue: true, wantActive: newest},
+		{name: "key just started", stored: stored, now: newest.Add(time.Hour)},
+		{name: "before the overlap", stored: stored, now: newest.Add(23*time.Hour - time.Second)},
+		{name: "overlap begins", stored: stored, now: newest.Add(23 * time.Hour), wantDue: true, wantActive: newest.Add(24 * time.Hour)},
+		{name: "after an outage", stored: stored, now: newest.Add(30 * time.Hour), wantDue: true, wantActive: newest.Add(30 * time.Hour)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			activeFrom, due := k.nextKeyDue(tt.stored, tt.now)
+			if due != tt.wantDue || !activeFrom.Equal(tt.wantActive) {
+				t.Errorf("nextKeyDue = %v, %v; want %v, %v", activeFrom, due, tt.wantActive, tt.wantDue)
+			}
+		})
+	}
+}
+
+func TestKeyringRotation(t *testing.T) {
+	k, repo := newTestKeyring(t)
+	if _, err := k.Signer(); err == nil {
+		t.Fatal("signed before the first sync")
+	}
+
+	mustSync(t, k)
+	old := signer(t, k)
+	token, err := auth.GenerateToken(old, auth.Claims{UserID: 1}, testConfig.AccessTTL)
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+
+	// Half an hour into the overlap the next key is published but doesn't
+	// sign yet
+	repo.age(23*time.Hour + 30*time.Minute)
+	mustSync(t, k)
+	if got := signer(t, k).ID; got != old.ID {
+		t.Fatalf("signing with %s before its turn, want %s", got, old.ID)
+	}
+	if keys := published(t, k); len(keys) != 2 {
+		t.Fatalf("published %d keys during the overlap, want 2", len(keys))
+	}
+
+	// Once it takes over, tokens signed with the retired key still validate
+	repo.age(time.Hour)
+	mustSync(t, k)
+	next := signer(t, k)
+	if next.ID == old.ID {
+		t.Fatal("the next key did not take over")
+	}
+	if _, err := auth.ValidateToken(keySet(published(t, k)), token); err != nil {
+		t.Errorf("token signed with the retired key: %v", err)
+	}
+
+	// After the overlap only the new key is left
+	repo.age(time.Hour)
+	mustSync(t, k)
+	if keys := published(t, k); len(keys) != 1 || keys[next.ID] == nil {
+		t.Errorf("published %v after the overlap, want only %s", slices.Collect(maps.Keys(keys)), next.ID)
+	}
+	if len(repo.keys) != 1 {
+		t.Errorf("%d keys stored after the overlap, want 1", len(repo.keys))
+	}
+}
+
+func TestSyncRefusesKeysItCannotOpen(t *testing.T) {
+	k, repo := newTestKeyring(t)
+	mustSync(t, k)
+
+	other := testConfig
+	other.KeyEncryptionKey = testSecret(2)
+	withOtherSecret, err := New(repo, other)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := withOtherSecret.Sync(context.Background()); err == nil {
+		t.Error("synced keys sealed with another secret")
+	}
+
+	// A plain key someone wrote into the table
+	der, err := open(k.aead, repo.keys[0].KID, repo.keys[0].PrivateKey)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	repo.keys[0].PrivateKey = der
+	if err := k.Sync(context.Background()); err == nil {
+		t.Error("synced a key that is not sealed")
+	}
+}
+
+func TestNewChecksOverlap(t *testing.T) {
+	tooShort := testConfig
+	tooShort.KeyOverlap = tooShort.AccessTTL - time.Second
+	longerThanRotation := testConfig
+	longerThanRotation.KeyOverlap = longerThanRotation.KeyRotation
+
+	for name, cfg := range map[string]config.JWT{"overlap shorter than a token": tooShort, "overlap as long as rotation": longerThanRotation} {
+		if _, err := New(&fakeRepo{}, cfg); err == nil {
+			t.Errorf("%s was accepted", name)
+		}
+	}
+}
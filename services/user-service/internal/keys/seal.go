@@ -0,0 +1,61 @@
+package keys
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// sealedV1 starts every private key sealed with AES-GCM, leaving room for
+// another scheme later
+const sealedV1 = 0x01
+
+func newAEAD(secret string) (cipher.AEAD, error) {
+	if secret == "" {
+		return nil, errors.New("JWT_KEY_ENCRYPTION_KEY is not set")
+	}
+	key, err := base64.StdEncoding.DecodeString(secret)
+	if err != nil {
+		return nil, fmt.Errorf("JWT_KEY_ENCRYPTION_KEY is not valid base64: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("JWT_KEY_ENCRYPTION_KEY must be 32 bytes, got %d", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts der, binding it to kid so a sealed key can't be swapped
+// onto another row
+func seal(aead cipher.AEAD, kid string, der []byte) []byte {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		panic(err) // crypto/rand doesn't fail on supported platforms
+	}
+	sealed := append([]byte{sealedV1}, nonce...)
+	return aead.Seal(sealed, nonce, der, []byte(kid))
+}
+
+// open reverses seal. Anything else is refused: a plain key in the table
+// was not written by user-service and must not be trusted for signing.
+func open(aead cipher.AEAD, kid string, stored []byte) ([]byte, error) {
+	if len(stored) == 0 || stored[0] != sealedV1 {
+		return nil, errors.New("key is not sealed")
+	}
+	stored = stored[1:]
+	if len(stored) < aead.NonceSize() {
+		return nil, errors.New("sealed key is truncated")
+	}
+	nonce, ciphertext := stored[:aead.NonceSize()], stored[aead.NonceSize():]
+	der, err := aead.Open(nil, nonce, ciphertext, []byte(kid))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt key, is JWT_KEY_ENCRYPTION_KEY the one it was sealed with? %w", err)
+	}
+	return der, nil
+}
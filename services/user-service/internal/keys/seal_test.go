@@ -0,0 +1,70 @@
+package keys
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+)
+
+func testSecret(b byte) string {
+	return base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, 32))
+}
+
+func TestOpen(t *testing.T) {
+	aead, err := newAEAD(testSecret(1))
+	if err != nil {
+		t.Fatalf("newAEAD: %v", err)
+	}
+	der := []byte("private key")
+	sealed := seal(aead, "kid-1", der)
+
+	tests := []struct {
+		name    string
+		secret  string
+		kid     string
+		stored  []byte
+		wantErr bool
+	}{
+		{name: "round trip", secret: testSecret(1), kid: "kid-1", stored: sealed},
+		{name: "wrong secret", secret: testSecret(2), kid: "kid-1", stored: sealed, wantErr: true},
+		{name: "moved to another row", secret: testSecret(1), kid: "kid-2", stored: sealed, wantErr: true},
+		{name: "tampered", secret: testSecret(1), kid: "kid-1", stored: append(bytes.Clone(sealed[:len(sealed)-1]), sealed[len(sealed)-1]^1), wantErr: true},
+		{name: "truncated", secret: testSecret(1), kid: "kid-1", stored: sealed[:5], wantErr: true},
+		{name: "not sealed", secret: testSecret(1), kid: "kid-1", stored: der, wantErr: true},
+		{name: "empty", secret: testSecret(1), kid: "kid-1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aead, err := newAEAD(tt.secret)
+			if err != nil {
+				t.Fatalf("newAEAD: %v", err)
+			}
+			got, err := open(aead, tt.kid, tt.stored)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("open error = %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil && !bytes.Equal(got, der) {
+				t.Errorf("open = %q, want %q", got, der)
+			}
+		})
+	}
+}
+
+func TestSealUsesFreshNonces(t *testing.T) {
+	aead, _ := newAEAD(testSecret(1))
+	if bytes.Equal(seal(aead, "kid", []byte("key")), seal(aead, "kid", []byte("key"))) {
+		t.Error("sealing the same key twice gave the same bytes")
+	}
+}
+
+func TestNewAEADChecksSecret(t *testing.T) {
+	for name, secret := range map[string]string{
+		"unset":        "",
+		"not base64":   "not base64!",
+		"wrong length": base64.StdEncoding.EncodeToString(make([]byte, 16)),
+	} {
+		if _, err := newAEAD(secret); err == nil {
+			t.Errorf("%s secret was accepted", name)
+		}
+	}
+}
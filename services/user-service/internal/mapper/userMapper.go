@@ -2,6 +2,7 @@ package mapper
 
 import (
 	"strconv"
+	"time"
 
 	"github.com/devfeel/mapper"
 	"github.com/jinzhu/copier"
@@ -76,9 +77,21 @@ func ToPbUser(user *models.User) *pb.User {
 			University: user.Education.University,
 			Major:      user.Education.Major,
 		},
+		EmailVerified: user.EmailVerified(),
 	}
 }
 
+// ToPbAdminUser adds the account state only admins get to see
+func ToPbAdminUser(user *models.User) *pb.User {
+	pbUser := ToPbUser(user)
+	pbUser.Role = user.Role
+	if user.SuspendedAt != nil {
+		pbUser.SuspendedAt = user.SuspendedAt.UTC().Format(time.RFC3339)
+	}
+	pbUser.SuspensionReason = user.SuspensionReason
+	return pbUser
+}
+
 // FromPbUpdateRequest maps Protobuf UpdateUserRequest → DTO
 func FromPbUpdateRequest(req *pb.UpdateUserRequest) *dto.UserDTO {
 	dtoUser := &dto.UserDTO{
@@ -103,3 +116,13 @@ func FromPbUpdateRequest(req *pb.UpdateUserRequest) *dto.UserDTO {
 
 	return dtoUser
 }
+
+func ToPbDeviceKey(key *models.DeviceKey) *pb.DeviceKey {
+	return &pb.DeviceKey{
+		DeviceId:    key.DeviceID,
+		IdentityKey: key.IdentityKey,
+		Algorithm:   key.Algorithm,
+		CreatedAt:   key.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   key.UpdatedAt.Format(time.RFC3339),
+	}
+}
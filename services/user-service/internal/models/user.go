@@ -1,6 +1,8 @@
 package models
 
 import (
+	"time"
+
 	"gorm.io/gorm"
 )
 
@@ -20,6 +22,28 @@ type User struct {
 	Education   Education `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
 
 	CurrentEventID uint
+
+	// Role is auth.RoleUser or auth.RoleAdmin
+	Role             string     `gorm:"type:varchar(20);not null;default:user"`
+	SuspendedAt      *time.Time `gorm:"index"`
+	SuspensionReason string     `gorm:"type:varchar(500)"`
+
+	// TokenVersion is bumped to log the user out everywhere
+	TokenVersion uint `gorm:"not null;default:0"`
+
+	// EmailVerifiedAt is unset until the user follows the link in the
+	// verification email, and again after they change their email
+	EmailVerifiedAt *time.Time
+}
+
+// EmailVerified reports whether the user confirmed they own their email
+func (u *User) EmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// Suspended reports whether an admin has suspended the user
+func (u *User) Suspended() bool {
+	return u.SuspendedAt != nil
 }
 
 // Contact represents the nested message Contact.
@@ -42,3 +66,56 @@ type Interest struct {
 	UserID uint   `gorm:"index;not null"`
 	Name   string `gorm:"type:varchar(100);not null"`
 }
+
+// RefreshToken is one link in a chain of refresh tokens, each traded once for
+// the next. Chains share a FamilyID from the login that started them, so a
+// token used twice can take down the whole chain. Only the token's hash is
+// stored.
+type RefreshToken struct {
+	gorm.Model
+	UserID    uint      `gorm:"index;not null"`
+	FamilyID  string    `gorm:"type:varchar(36);index;not null"`
+	TokenHash string    `gorm:"type:char(64);uniqueIndex;not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	RevokedAt *time.Time
+}
+
+// RevokedToken is an access token revoked at logout. It is kept until the
+// token would have expired anyway.
+type RevokedToken struct {
+	JTI       string    `gorm:"type:varchar(36);primaryKey"`
+	UserID    uint      `gorm:"index;not null"`
+	ExpiresAt time.Time `gorm:"index;not null"`
+}
+
+// SigningKey is a private key user-service signs access tokens with. It
+// signs from ActiveFrom until a newer key becomes active, and its public half
+// is published until ExpiresAt.
+type SigningKey struct {
+	KID        string    `gorm:"type:varchar(36);primaryKey"`
+	Algorithm  string    `gorm:"type:varchar(10);not null"`
+	PrivateKey []byte    `gorm:"not null"` // PKCS #8 DER, sealed with AES-GCM
+	ActiveFrom time.Time `gorm:"index;not null"`
+	ExpiresAt  time.Time `gorm:"index;not null"`
+	CreatedAt  time.Time
+}
+
+// EmailVerification is the token emailed to a user to verify their address.
+// A user has at most one; sending another replaces it.
+type EmailVerification struct {
+	gorm.Model
+	UserID    uint      `gorm:"uniqueIndex;not null"`
+	TokenHash string    `gorm:"type:char(64);uniqueIndex;not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+}
+
+// DeviceKey is the public identity key of one of a user's devices, used by
+// clients to encrypt direct messages end to end.
+type DeviceKey struct {
+	gorm.Model
+	UserID      uint   `gorm:"not null;uniqueIndex:idx_device_keys_user_device"`
+	DeviceID    string `gorm:"type:varchar(64);not null;uniqueIndex:idx_device_keys_user_device"`
+	IdentityKey string `gorm:"type:varchar(255);not null"`
+	Algorithm   string `gorm:"type:varchar(20);not null"`
+}
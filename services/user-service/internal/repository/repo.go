@@ -2,20 +2,57 @@ package repository
 
 import (
 	"context"
-	"log"
+	"strings"
+	"time"
 
 	"github.com/wutthichod/sa-connext/services/user-service/internal/models"
+	"github.com/wutthichod/sa-connext/shared/correlation"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Repository interface {
 	CreateUser(ctx context.Context, user *models.User) (*models.User, error)
 	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
 	GetUserById(ctx context.Context, userId uint) (*models.User, error)
+	GetUsersByIds(ctx context.Context, userIds []uint) ([]*models.User, error)
 	GetUsersByEventId(ctx context.Context, eventId uint) ([]*models.User, error)
 	AddUserToEvent(ctx context.Context, eventId, userId uint) error
 	LeaveEvent(ctx context.Context, userId uint) error
 	UpdateUser(ctx context.Context, userId uint, user *models.User) (*models.User, error)
+	UpsertDeviceKey(ctx context.Context, key *models.DeviceKey) (*models.DeviceKey, error)
+	GetDeviceKeysByUserId(ctx context.Context, userId uint) ([]*models.DeviceKey, error)
+	DeleteDeviceKey(ctx context.Context, userId uint, deviceId string) (bool, error)
+	ListUsers(ctx context.Context, filter UserFilter) ([]*models.User, int64, error)
+	SetSuspension(ctx context.Context, userId uint, suspendedAt *time.Time, reason string) error
+	GetSuspendedUserIds(ctx context.Context) ([]uint, error)
+	SetRoleByEmail(ctx context.Context, email, role string) (bool, error)
+	CreateRefreshToken(ctx context.Context, token *models.RefreshToken) error
+	GetRefreshTokenByHash(ctx context.Context, hash string) (*models.RefreshToken, error)
+	MarkRefreshTokenUsed(ctx context.Context, id uint, usedAt time.Time) (bool, error)
+	RevokeRefreshTokenFamily(ctx context.Context, familyID string, revokedAt time.Time) error
+	RevokeUserRefreshTokens(ctx context.Context, userId uint, revokedAt time.Time) error
+	RevokeToken(ctx context.Context, token *models.RevokedToken) error
+	GetRevokedTokens(ctx context.Context, now time.Time) ([]*models.RevokedToken, error)
+	DeleteExpiredRevokedTokens(ctx context.Context, now time.Time) error
+	BumpTokenVersion(ctx context.Context, userId uint) (uint, error)
+	GetTokenVersions(ctx context.Context) (map[uint]uint, error)
+	CreateSigningKey(ctx context.Context, key *models.SigningKey) error
+	GetSigningKeys(ctx context.Context, now time.Time) ([]*models.SigningKey, error)
+	DeleteExpiredSigningKeys(ctx context.Context, now time.Time) error
+	ReplaceEmailVerification(ctx context.Context, verification *models.EmailVerification) error
+	GetEmailVerificationByUserId(ctx context.Context, userId uint) (*models.EmailVerification, error)
+	ConsumeEmailVerification(ctx context.Context, hash string, verifiedAt time.Time) (*models.EmailVerification, error)
+	SetEmailVerified(ctx context.Context, userId uint, verifiedAt *time.Time) error
+}
+
+// UserFilter selects a page of users for ListUsers
+type UserFilter struct {
+	// Query matches usernames and emails, case-insensitively
+	Query         string
+	SuspendedOnly bool
+	Offset        int
+	Limit         int
 }
 
 type repository struct {
@@ -63,6 +100,19 @@ func (r *repository) GetUserById(ctx context.Context, userId uint) (*models.User
 	return &user, nil
 }
 
+func (r *repository) GetUsersByIds(ctx context.Context, userIds []uint) ([]*models.User, error) {
+	var users []*models.User
+	if err := r.db.WithContext(ctx).
+		Preload("Contact").
+		Preload("Education").
+		Preload("Interests").
+		Where("id IN ?", userIds).
+		Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
 func (r *repository) GetUsersByEventId(ctx context.Context, eventId uint) ([]*models.User, error) {
 	var users []*models.User
 	if err := r.db.WithContext(ctx).Where("current_event_id = ?", eventId).Find(&users).Error; err != nil {
@@ -94,7 +144,7 @@ func (r *repository) UpdateUser(ctx context.Context, userId uint, user *models.U
 		"job_title": user.JobTitle,
 	}).Error; err != nil {
 		tx.Rollback()
-		log.Printf("Error updating user basic info: %v", err)
+		correlation.Printf(ctx, "Error updating user basic info: %v", err)
 		return nil, err
 	}
 
@@ -105,7 +155,7 @@ func (r *repository) UpdateUser(ctx context.Context, userId uint, user *models.U
 			"phone": user.Contact.Phone,
 		}).Error; err != nil {
 			tx.Rollback()
-			log.Printf("Error updating contact: %v", err)
+			correlation.Printf(ctx, "Error updating contact: %v", err)
 			return nil, err
 		}
 	} else {
@@ -115,12 +165,12 @@ func (r *repository) UpdateUser(ctx context.Context, userId uint, user *models.U
 		}
 		if err := tx.Create(contact).Error; err != nil {
 			tx.Rollback()
-			log.Printf("Error creating contact: %v", err)
+			correlation.Printf(ctx, "Error creating contact: %v", err)
 			return nil, err
 		}
 		if err := tx.Model(&models.User{}).Where("id = ?", userId).Update("contact_id", contact.ID).Error; err != nil {
 			tx.Rollback()
-			log.Printf("Error updating contact: %v", err)
+			correlation.Printf(ctx, "Error updating contact: %v", err)
 			return nil, err
 		}
 	}
@@ -170,3 +220,264 @@ func (r *repository) UpdateUser(ctx context.Context, userId uint, user *models.U
 	// Return updated user with preloaded relations
 	return r.GetUserById(ctx, userId)
 }
+
+func (r *repository) UpsertDeviceKey(ctx context.Context, key *models.DeviceKey) (*models.DeviceKey, error) {
+	// Re-registering a device replaces its key
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "device_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"identity_key", "algorithm", "updated_at"}),
+	}).Create(key).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var saved models.DeviceKey
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND device_id = ?", key.UserID, key.DeviceID).
+		First(&saved).Error; err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+func (r *repository) GetDeviceKeysByUserId(ctx context.Context, userId uint) ([]*models.DeviceKey, error) {
+	var keys []*models.DeviceKey
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userId).Order("created_at").Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (r *repository) DeleteDeviceKey(ctx context.Context, userId uint, deviceId string) (bool, error) {
+	// Hard delete so the device can register again under the unique index
+	res := r.db.WithContext(ctx).Unscoped().
+		Where("user_id = ? AND device_id = ?", userId, deviceId).
+		Delete(&models.DeviceKey{})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (r *repository) ListUsers(ctx context.Context, filter UserFilter) ([]*models.User, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.User{}).
+		Joins("LEFT JOIN contacts ON contacts.id = users.contact_id")
+	if filter.Query != "" {
+		// Escape LIKE wildcards so the query matches literally
+		pattern := "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(filter.Query) + "%"
+		query = query.Where("users.username ILIKE ? OR contacts.email ILIKE ?", pattern, pattern)
+	}
+	if filter.SuspendedOnly {
+		query = query.Where("users.suspended_at IS NOT NULL")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []*models.User
+	if err := query.
+		Preload("Contact").
+		Preload("Education").
+		Preload("Interests").
+		Order("users.id").
+		Offset(filter.Offset).
+		Limit(filter.Limit).
+		Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+// SetSuspension suspends the user at suspendedAt, or reinstates them when it is nil
+func (r *repository) SetSuspension(ctx context.Context, userId uint, suspendedAt *time.Time, reason string) error {
+	return r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", userId).Updates(map[string]interface{}{
+		"suspended_at":      suspendedAt,
+		"suspension_reason": reason,
+	}).Error
+}
+
+func (r *repository) GetSuspendedUserIds(ctx context.Context) ([]uint, error) {
+	var ids []uint
+	if err := r.db.WithContext(ctx).Model(&models.User{}).
+		Where("suspended_at IS NOT NULL").
+		Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// SetRoleByEmail reports false when no user has the email
+func (r *repository) SetRoleByEmail(ctx context.Context, email, role string) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&models.User{}).
+		Where("contact_id IN (?)", r.db.Model(&models.Contact{}).Select("id").Where("email = ?", email)).
+		Update("role", role)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (r *repository) CreateRefreshToken(ctx context.Context, token *models.RefreshToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+func (r *repository) GetRefreshTokenByHash(ctx context.Context, hash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkRefreshTokenUsed reports false when the token was already used or
+// revoked, so of two concurrent refreshes only one wins
+func (r *repository) MarkRefreshTokenUsed(ctx context.Context, id uint, usedAt time.Time) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&models.RefreshToken{}).
+		Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", id).
+		Update("used_at", usedAt)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (r *repository) RevokeRefreshTokenFamily(ctx context.Context, familyID string, revokedAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", revokedAt).Error
+}
+
+func (r *repository) RevokeUserRefreshTokens(ctx context.Context, userId uint, revokedAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userId).
+		Update("revoked_at", revokedAt).Error
+}
+
+// RevokeToken is a no-op for a token that is already revoked
+func (r *repository) RevokeToken(ctx context.Context, token *models.RevokedToken) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(token).Error
+}
+
+// GetRevokedTokens lists the revoked tokens that have not expired by now
+func (r *repository) GetRevokedTokens(ctx context.Context, now time.Time) ([]*models.RevokedToken, error) {
+	var tokens []*models.RevokedToken
+	if err := r.db.WithContext(ctx).Where("expires_at > ?", now).Find(&tokens).Error; err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func (r *repository) DeleteExpiredRevokedTokens(ctx context.Context, now time.Time) error {
+	return r.db.WithContext(ctx).Where("expires_at <= ?", now).Delete(&models.RevokedToken{}).Error
+}
+
+// BumpTokenVersion returns the user's new token version
+func (r *repository) BumpTokenVersion(ctx context.Context, userId uint) (uint, error) {
+	var user models.User
+	res := r.db.WithContext(ctx).Model(&user).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "token_version"}}}).
+		Where("id = ?", userId).
+		Update("token_version", gorm.Expr("token_version + 1"))
+	if res.Error != nil {
+		return 0, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return 0, gorm.ErrRecordNotFound
+	}
+	return user.TokenVersion, nil
+}
+
+// GetTokenVersions maps the users who ever logged out everywhere to their
+// current token version
+func (r *repository) GetTokenVersions(ctx context.Context) (map[uint]uint, error) {
+	var rows []struct {
+		ID           uint
+		TokenVersion uint
+	}
+	if err := r.db.WithContext(ctx).Model(&models.User{}).
+		Select("id", "token_version").
+		Where("token_version > 0").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	versions := make(map[uint]uint, len(rows))
+	for _, row := range rows {
+		versions[row.ID] = row.TokenVersion
+	}
+	return versions, nil
+}
+
+func (r *repository) CreateSigningKey(ctx context.Context, key *models.SigningKey) error {
+	return r.db.WithContext(ctx).Create(key).Error
+}
+
+// GetSigningKeys lists the keys still published at now, newest first
+func (r *repository) GetSigningKeys(ctx context.Context, now time.Time) ([]*models.SigningKey, error) {
+	var keys []*models.SigningKey
+	if err := r.db.WithContext(ctx).
+		Where("expires_at > ?", now).
+		Order("active_from DESC, kid").
+		Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (r *repository) DeleteExpiredSigningKeys(ctx context.Context, now time.Time) error {
+	return r.db.WithContext(ctx).Where("expires_at <= ?", now).Delete(&models.SigningKey{}).Error
+}
+
+// ReplaceEmailVerification drops the user's earlier token, so only the
+// latest email's link works
+func (r *repository) ReplaceEmailVerification(ctx context.Context, verification *models.EmailVerification) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("user_id = ?", verification.UserID).Delete(&models.EmailVerification{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(verification).Error
+	})
+}
+
+func (r *repository) GetEmailVerificationByUserId(ctx context.Context, userId uint) (*models.EmailVerification, error) {
+	var verification models.EmailVerification
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userId).First(&verification).Error; err != nil {
+		return nil, err
+	}
+	return &verification, nil
+}
+
+// ConsumeEmailVerification spends the unexpired token with hash and marks
+// its user's email verified at verifiedAt. The row is deleted in the same
+// transaction, so a link only works once, even when opened twice at once.
+func (r *repository) ConsumeEmailVerification(ctx context.Context, hash string, verifiedAt time.Time) (*models.EmailVerification, error) {
+	var verification models.EmailVerification
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Unscoped().Clauses(clause.Returning{}).
+			Where("token_hash = ? AND expires_at > ?", hash, verifiedAt).
+			Delete(&verification)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Model(&models.User{}).Where("id = ?", verification.UserID).Update("email_verified_at", verifiedAt).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &verification, nil
+}
+
+// SetEmailVerified marks the email verified at verifiedAt, or unverified
+// when it is nil. Either way the outstanding token is spent.
+func (r *repository) SetEmailVerified(ctx context.Context, userId uint, verifiedAt *time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).Where("id = ?", userId).Update("email_verified_at", verifiedAt).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("user_id = ?", userId).Delete(&models.EmailVerification{}).Error
+	})
+}
@@ -1,15 +1,26 @@
 package server
 
 import (
+	"context"
+	"errors"
 	"log"
 	"net"
+	"net/http"
+	"time"
 
 	"github.com/wutthichod/sa-connext/services/user-service/internal/handler"
+	"github.com/wutthichod/sa-connext/services/user-service/internal/keys"
 	"github.com/wutthichod/sa-connext/services/user-service/internal/repository"
 	"github.com/wutthichod/sa-connext/services/user-service/internal/service"
 	"github.com/wutthichod/sa-connext/services/user-service/pkg/database"
 	"github.com/wutthichod/sa-connext/shared/config"
+	"github.com/wutthichod/sa-connext/shared/contracts"
+	"github.com/wutthichod/sa-connext/shared/correlation"
+	"github.com/wutthichod/sa-connext/shared/health"
+	"github.com/wutthichod/sa-connext/shared/lifecycle"
 	"github.com/wutthichod/sa-connext/shared/messaging"
+	"github.com/wutthichod/sa-connext/shared/metrics"
+	"github.com/wutthichod/sa-connext/shared/tracing"
 
 	"google.golang.org/grpc"
 )
@@ -18,6 +29,12 @@ func InitServer(cfg config.Config) error {
 
 	grpcAddr := cfg.App().User
 
+	ctx := context.Background()
+	provider, err := tracing.Init(ctx, "user-service", cfg.Tracing())
+	if err != nil {
+		log.Fatalf("failed to init tracing: %v", err)
+	}
+
 	lis, err := net.Listen("tcp", grpcAddr)
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
@@ -27,18 +44,57 @@ func InitServer(cfg config.Config) error {
 	if err != nil {
 		log.Fatalf("failed to connect to the database: %v", err)
 	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Fatalf("failed to get database handle: %v", err)
+	}
+	if err := metrics.RegisterDB("user-db", sqlDB); err != nil {
+		log.Fatalf("failed to register database metrics: %v", err)
+	}
+	metricsServer := metrics.NewServer(cfg.Metrics().Addr)
+
 	rb, err := messaging.NewRabbitMQ(cfg.RABBITMQ().URI)
 	if err != nil {
 		log.Fatalf("failed to connect to RabbitMQ: %v", err)
 	}
-	defer rb.Close()
 
-	server := grpc.NewServer()
+	if err := rb.DeclareExchange(contracts.UserExchange, "topic", true); err != nil {
+		log.Fatalf("failed to declare user exchange: %v", err)
+	}
+
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			correlation.UnaryServerInterceptor(),
+			metrics.UnaryServerInterceptor(),
+		),
+		tracing.ServerOption(),
+	)
 	repo := repository.NewRepo(db)
-	service := service.NewService(repo, rb, cfg)
+
+	// Only user-service holds private keys; validators fetch the public
+	// halves from the JWKS endpoint
+	keyring, err := keys.New(repo, cfg.JWT())
+	if err != nil {
+		log.Fatalf("invalid signing key settings: %v", err)
+	}
+	if err := keyring.Sync(ctx); err != nil {
+		log.Fatalf("failed to load signing keys: %v", err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle(keys.Path, keyring)
+	jwksServer := &http.Server{Addr: cfg.JWT().JWKSAddr, Handler: mux}
+
+	service := service.NewService(repo, rb, cfg, keyring)
 
 	handler.NewGRPCHandler(server, service)
 
+	lc := lifecycle.New(cfg.Shutdown().Timeout)
+
+	checker := health.NewChecker()
+	checker.Add("postgres", health.SQL(sqlDB))
+	checker.Add("rabbitmq", rb.Check)
+	healthServer := health.RegisterGRPC(lc.Context(), server, checker, 10*time.Second)
+
 	// if err = service.CreateUser(ctx, "brightka"); err != nil {
 	// 	log.Fatalf("failed to create user: %v", err)
 	// }
@@ -47,5 +103,35 @@ func InitServer(cfg config.Config) error {
 	// if err := server.Serve(lis); err != nil {
 	// 	log.Fatalf("failed to serve: %v", err)
 	// }
-	return server.Serve(lis)
+	lc.Go("grpc", func() error { return server.Serve(lis) })
+	lc.Go("jwks", func() error {
+		if err := jwksServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	})
+	lc.Go("metrics", func() error {
+		if err := metricsServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	})
+	go keyring.KeepFresh(lc.Context(), time.Minute)
+
+	// Report NOT_SERVING first so no new traffic is routed here while draining
+	lc.OnShutdown("health", func(ctx context.Context) error {
+		healthServer.Shutdown()
+		return nil
+	})
+	lc.OnShutdown("grpc", lifecycle.GRPCServer(server))
+	lc.OnShutdown("jwks", jwksServer.Shutdown)
+	lc.OnShutdown("rabbitmq", func(ctx context.Context) error {
+		rb.Close()
+		return nil
+	})
+	lc.OnShutdown("postgres", func(ctx context.Context) error { return sqlDB.Close() })
+	lc.OnShutdown("metrics", metricsServer.Shutdown)
+	lc.OnShutdown("tracing", provider.Shutdown)
+
+	return lc.Wait()
 }
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wutthichod/sa-connext/services/user-service/internal/mapper"
+	"github.com/wutthichod/sa-connext/services/user-service/internal/models"
+	"github.com/wutthichod/sa-connext/services/user-service/internal/repository"
+	"github.com/wutthichod/sa-connext/shared/auth"
+	"github.com/wutthichod/sa-connext/shared/contracts"
+	"github.com/wutthichod/sa-connext/shared/correlation"
+	grpcerrors "github.com/wutthichod/sa-connext/shared/errors"
+	pb "github.com/wutthichod/sa-connext/shared/proto/user"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultUserPageSize = 50
+	maxUserPageSize     = 200
+	maxSuspensionReason = 500
+)
+
+// errAccountSuspended is returned to suspended users trying to log in; the
+// gateway maps the ACCOUNT_SUSPENDED prefix to its error code
+var errAccountSuspended = grpcerrors.PermissionDenied("ACCOUNT_SUSPENDED: this account has been suspended")
+
+func (s *service) ListUsers(ctx context.Context, pbReq *pb.ListUsersRequest) (*pb.ListUsersResponse, error) {
+	page := int(pbReq.Page)
+	if page < 1 {
+		page = 1
+	}
+	pageSize := int(pbReq.PageSize)
+	if pageSize < 1 {
+		pageSize = defaultUserPageSize
+	}
+	if pageSize > maxUserPageSize {
+		return nil, grpcerrors.InvalidInput("page size must be at most 200", map[string]string{
+			"field": "page_size",
+		})
+	}
+
+	users, total, err := s.repo.ListUsers(ctx, repository.UserFilter{
+		Query:         strings.TrimSpace(pbReq.Query),
+		SuspendedOnly: pbReq.SuspendedOnly,
+		Offset:        (page - 1) * pageSize,
+		Limit:         pageSize,
+	})
+	if err != nil {
+		return nil, grpcerrors.DatabaseError(err.Error())
+	}
+
+	pbUsers := make([]*pb.User, 0, len(users))
+	for _, user := range users {
+		pbUsers = append(pbUsers, mapper.ToPbAdminUser(user))
+	}
+	return &pb.ListUsersResponse{Success: true, Users: pbUsers, Total: total}, nil
+}
+
+func (s *service) SuspendUser(ctx context.Context, pbReq *pb.SuspendUserRequest) (*pb.SuspendUserResponse, error) {
+	reason := strings.TrimSpace(pbReq.Reason)
+	if reason == "" || len(reason) > maxSuspensionReason {
+		return nil, grpcerrors.InvalidInput("reason is required and must be at most 500 characters", map[string]string{
+			"field": "reason",
+		})
+	}
+	user, err := s.findUser(ctx, pbReq.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == auth.RoleAdmin {
+		return nil, grpcerrors.PermissionDenied("admins cannot be suspended")
+	}
+
+	if !user.Suspended() {
+		now := time.Now()
+		if err := s.repo.SetSuspension(ctx, user.ID, &now, reason); err != nil {
+			return nil, grpcerrors.DatabaseError(err.Error())
+		}
+		user.SuspendedAt, user.SuspensionReason = &now, reason
+		correlation.Printf(ctx, "Admin %s suspended user %s: %s", pbReq.AdminId, pbReq.UserId, reason)
+		s.publishSuspensionChanged(ctx, contracts.UserSuspendedRouting, pbReq.UserId)
+	}
+	return &pb.SuspendUserResponse{Success: true, User: mapper.ToPbAdminUser(user)}, nil
+}
+
+func (s *service) ReinstateUser(ctx context.Context, pbReq *pb.ReinstateUserRequest) (*pb.ReinstateUserResponse, error) {
+	user, err := s.findUser(ctx, pbReq.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Suspended() {
+		if err := s.repo.SetSuspension(ctx, user.ID, nil, ""); err != nil {
+			return nil, grpcerrors.DatabaseError(err.Error())
+		}
+		user.SuspendedAt, user.SuspensionReason = nil, ""
+		correlation.Printf(ctx, "Admin %s reinstated user %s", pbReq.AdminId, pbReq.UserId)
+		s.publishSuspensionChanged(ctx, contracts.UserReinstatedRouting, pbReq.UserId)
+	}
+	return &pb.ReinstateUserResponse{Success: true, User: mapper.ToPbAdminUser(user)}, nil
+}
+
+// GetSuspendedUserIds lets the gateway reject the tokens of suspended users,
+// which stay valid until they expire
+func (s *service) GetSuspendedUserIds(ctx context.Context, pbReq *pb.GetSuspendedUserIdsRequest) (*pb.GetSuspendedUserIdsResponse, error) {
+	ids, err := s.repo.GetSuspendedUserIds(ctx)
+	if err != nil {
+		return nil, grpcerrors.DatabaseError(err.Error())
+	}
+	userIds := make([]string, 0, len(ids))
+	for _, id := range ids {
+		userIds = append(userIds, strconv.FormatUint(uint64(id), 10))
+	}
+	return &pb.GetSuspendedUserIdsResponse{Success: true, UserIds: userIds}, nil
+}
+
+func (s *service) findUser(ctx context.Context, id string) (*models.User, error) {
+	userId, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return nil, grpcerrors.InvalidInput("invalid user ID format", map[string]string{
+			"field": "user_id",
+			"value": id,
+		})
+	}
+	user, err := s.repo.GetUserById(ctx, uint(userId))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, grpcerrors.NotFound("User")
+		}
+		return nil, grpcerrors.DatabaseError(err.Error())
+	}
+	return user, nil
+}
+
+// publishSuspensionChanged tells the gateways to start or stop rejecting the
+// user's tokens. They also resync periodically, so a lost message only
+// delays that.
+func (s *service) publishSuspensionChanged(ctx context.Context, routingKey, userID string) {
+	changed := contracts.UserSuspensionChangedEvent{UserID: userID}
+	if err := s.rb.PublishMessage(ctx, contracts.UserExchange, routingKey, changed); err != nil {
+		correlation.Printf(ctx, "Failed to publish %s: %v", routingKey, err)
+	}
+}
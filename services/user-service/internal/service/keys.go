@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/wutthichod/sa-connext/services/user-service/internal/mapper"
+	"github.com/wutthichod/sa-connext/services/user-service/internal/models"
+	grpcerrors "github.com/wutthichod/sa-connext/shared/errors"
+	pb "github.com/wutthichod/sa-connext/shared/proto/user"
+	"gorm.io/gorm"
+)
+
+const maxDeviceIDLength = 64
+
+// identityKeySizes lists the supported key algorithms and their raw public key size
+var identityKeySizes = map[string]int{
+	"x25519":  32,
+	"ed25519": 32,
+}
+
+func (s *service) RegisterDeviceKey(ctx context.Context, pbReq *pb.RegisterDeviceKeyRequest) (*pb.RegisterDeviceKeyResponse, error) {
+	userId, err := strconv.ParseUint(pbReq.UserId, 10, 64)
+	if err != nil {
+		return nil, grpcerrors.InvalidInput("invalid user ID format", map[string]string{
+			"field": "user_id",
+			"value": pbReq.UserId,
+		})
+	}
+
+	deviceId := strings.TrimSpace(pbReq.DeviceId)
+	if deviceId == "" || len(deviceId) > maxDeviceIDLength {
+		return nil, grpcerrors.InvalidInput("device ID is required and must be at most 64 characters", map[string]string{
+			"field": "device_id",
+		})
+	}
+
+	algorithm := strings.ToLower(pbReq.Algorithm)
+	if algorithm == "" {
+		algorithm = "x25519"
+	}
+	keySize, ok := identityKeySizes[algorithm]
+	if !ok {
+		return nil, grpcerrors.InvalidInput("unsupported key algorithm", map[string]string{
+			"field": "algorithm",
+			"value": pbReq.Algorithm,
+		})
+	}
+	rawKey, err := base64.StdEncoding.DecodeString(pbReq.IdentityKey)
+	if err != nil || len(rawKey) != keySize {
+		return nil, grpcerrors.InvalidInput("identity key must be a base64 encoded public key", map[string]string{
+			"field": "identity_key",
+		})
+	}
+
+	if _, err := s.repo.GetUserById(ctx, uint(userId)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, grpcerrors.NotFound("User")
+		}
+		return nil, grpcerrors.DatabaseError(err.Error())
+	}
+
+	key, err := s.repo.UpsertDeviceKey(ctx, &models.DeviceKey{
+		UserID:      uint(userId),
+		DeviceID:    deviceId,
+		IdentityKey: pbReq.IdentityKey,
+		Algorithm:   algorithm,
+	})
+	if err != nil {
+		return nil, grpcerrors.DatabaseError(err.Error())
+	}
+
+	return &pb.RegisterDeviceKeyResponse{
+		Success: true,
+		Key:     mapper.ToPbDeviceKey(key),
+	}, nil
+}
+
+func (s *service) GetUserKeys(ctx context.Context, pbReq *pb.GetUserKeysRequest) (*pb.GetUserKeysResponse, error) {
+	userId, err := strconv.ParseUint(pbReq.UserId, 10, 64)
+	if err != nil {
+		return nil, grpcerrors.InvalidInput("invalid user ID format", map[string]string{
+			"field": "user_id",
+			"value": pbReq.UserId,
+		})
+	}
+
+	keys, err := s.repo.GetDeviceKeysByUserId(ctx, uint(userId))
+	if err != nil {
+		return nil, grpcerrors.DatabaseError(err.Error())
+	}
+
+	pbKeys := make([]*pb.DeviceKey, len(keys))
+	for i, key := range keys {
+		pbKeys[i] = mapper.ToPbDeviceKey(key)
+	}
+	return &pb.GetUserKeysResponse{
+		Success: true,
+		Keys:    pbKeys,
+	}, nil
+}
+
+func (s *service) RemoveDeviceKey(ctx context.Context, pbReq *pb.RemoveDeviceKeyRequest) (*pb.RemoveDeviceKeyResponse, error) {
+	userId, err := strconv.ParseUint(pbReq.UserId, 10, 64)
+	if err != nil {
+		return nil, grpcerrors.InvalidInput("invalid user ID format", map[string]string{
+			"field": "user_id",
+			"value": pbReq.UserId,
+		})
+	}
+
+	deleted, err := s.repo.DeleteDeviceKey(ctx, uint(userId), pbReq.DeviceId)
+	if err != nil {
+		return nil, grpcerrors.DatabaseError(err.Error())
+	}
+	if !deleted {
+		return nil, grpcerrors.NotFound("Device key")
+	}
+	return &pb.RemoveDeviceKeyResponse{Success: true}, nil
+}
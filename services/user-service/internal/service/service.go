@@ -3,46 +3,71 @@ package service
 import (
 	"context"
 	"errors"
-	"log"
+	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 
+	"github.com/wutthichod/sa-connext/services/user-service/internal/keys"
 	"github.com/wutthichod/sa-connext/services/user-service/internal/mapper"
 	"github.com/wutthichod/sa-connext/services/user-service/internal/repository"
 	"github.com/wutthichod/sa-connext/shared/auth"
 	"github.com/wutthichod/sa-connext/shared/config"
 	"github.com/wutthichod/sa-connext/shared/contracts"
+	"github.com/wutthichod/sa-connext/shared/correlation"
 	grpcerrors "github.com/wutthichod/sa-connext/shared/errors"
-	"github.com/wutthichod/sa-connext/shared/messaging"
 	pb "github.com/wutthichod/sa-connext/shared/proto/user"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
+// maxBatchUserIds caps a single GetUsersByIds call
+const maxBatchUserIds = 200
+
 type Service interface {
-	CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*string, error)
-	Login(ctx context.Context, pbReq *pb.LoginRequest) (*string, error)
+	CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*Tokens, error)
+	Login(ctx context.Context, pbReq *pb.LoginRequest) (*Tokens, error)
+	RefreshToken(ctx context.Context, pbReq *pb.RefreshTokenRequest) (*Tokens, error)
+	Logout(ctx context.Context, pbReq *pb.LogoutRequest) (*pb.LogoutResponse, error)
+	LogoutEverywhere(ctx context.Context, pbReq *pb.LogoutEverywhereRequest) (*pb.LogoutEverywhereResponse, error)
+	GetRevocations(ctx context.Context, pbReq *pb.GetRevocationsRequest) (*pb.GetRevocationsResponse, error)
+	VerifyEmail(ctx context.Context, pbReq *pb.VerifyEmailRequest) (*pb.VerifyEmailResponse, error)
+	ResendVerification(ctx context.Context, pbReq *pb.ResendVerificationRequest) (*pb.ResendVerificationResponse, error)
 	GetUserById(ctx context.Context, pbReq *pb.GetUserByIdRequest) (*pb.GetUserByIdResponse, error)
+	GetUsersByIds(ctx context.Context, pbReq *pb.GetUsersByIdsRequest) (*pb.GetUsersByIdsResponse, error)
 	GetUsersByEventId(ctx context.Context, pbReq *pb.GetUsersByEventIdRequest) (*pb.GetUsersByEventIdResponse, error)
 	AddUserToEvent(ctx context.Context, pbReq *pb.AddUserToEventRequest) (*pb.AddUserToEventResponse, error)
 	LeaveEvent(ctx context.Context, pbReq *pb.LeaveEventRequest) (*pb.LeaveEventResponse, error)
 	UpdateUser(ctx context.Context, pbReq *pb.UpdateUserRequest) (*pb.UpdateUserResponse, error)
+	RegisterDeviceKey(ctx context.Context, pbReq *pb.RegisterDeviceKeyRequest) (*pb.RegisterDeviceKeyResponse, error)
+	GetUserKeys(ctx context.Context, pbReq *pb.GetUserKeysRequest) (*pb.GetUserKeysResponse, error)
+	RemoveDeviceKey(ctx context.Context, pbReq *pb.RemoveDeviceKeyRequest) (*pb.RemoveDeviceKeyResponse, error)
+	ListUsers(ctx context.Context, pbReq *pb.ListUsersRequest) (*pb.ListUsersResponse, error)
+	SuspendUser(ctx context.Context, pbReq *pb.SuspendUserRequest) (*pb.SuspendUserResponse, error)
+	ReinstateUser(ctx context.Context, pbReq *pb.ReinstateUserRequest) (*pb.ReinstateUserResponse, error)
+	GetSuspendedUserIds(ctx context.Context, pbReq *pb.GetSuspendedUserIdsRequest) (*pb.GetSuspendedUserIdsResponse, error)
+}
+
+// Publisher delivers messages to the broker; *messaging.RabbitMQ implements it
+type Publisher interface {
+	PublishMessage(ctx context.Context, exchange, routingKey string, message interface{}) error
 }
 
 type service struct {
 	repo repository.Repository
-	rb   *messaging.RabbitMQ
+	rb   Publisher
 	cfg  config.Config
+	keys *keys.Keyring
 }
 
-func NewService(repo repository.Repository, rb *messaging.RabbitMQ, cfg config.Config) Service {
-	return &service{repo, rb, cfg}
+func NewService(repo repository.Repository, rb Publisher, cfg config.Config, keyring *keys.Keyring) Service {
+	return &service{repo, rb, cfg, keyring}
 }
 
-func (s *service) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*string, error) {
+func (s *service) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*Tokens, error) {
 	// PB → DTO
 	dtoUser := mapper.FromPbRequest(req)
-	log.Printf("Mapped DTO: %+v\n", dtoUser)
+	correlation.Printf(ctx, "Mapped DTO: %+v\n", dtoUser)
 
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(dtoUser.Password), bcrypt.DefaultCost)
 	if err != nil {
@@ -52,39 +77,30 @@ func (s *service) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*s
 
 	// DTO → Model
 	userModel := mapper.ToUserModel(dtoUser)
-	log.Printf("Mapped Model: %+v\n", userModel)
-
-	// Publish to RabbitMQ
-	event := contracts.EmailEvent{
-		To:      req.Contact.Email,
-		Subject: "Welcome!",
-		Body:    "Hi there, thanks for signing up!",
-	}
-
-	if err := s.rb.PublishMessage(context.Background(), "notification.exchange", "notification.email", event); err != nil {
-		log.Printf("Failed to publish email event: %v", err)
-	}
+	userModel.Role = auth.RoleUser
+	correlation.Printf(ctx, "Mapped Model: %+v\n", userModel)
 
 	// Save to DB
 	createdUser, err := s.repo.CreateUser(ctx, userModel)
 	if err != nil {
-		log.Printf("Failed to create user: %v", err)
+		correlation.Printf(ctx, "Failed to create user: %v", err)
 		// Check for duplicate key errors
 		if strings.Contains(err.Error(), "duplicate") || strings.Contains(err.Error(), "unique") {
 			return nil, grpcerrors.AlreadyExists("User", "username or email")
 		}
 		return nil, grpcerrors.DatabaseError(err.Error())
 	}
-	// Generate JWT token
-	token, err := auth.GenerateToken(s.cfg.JWT().Token, createdUser.ID)
-	if err != nil {
-		return nil, err
+
+	// The account works right away, but with the restrictions the gateway
+	// puts on unverified users; they can ask for another email if this fails
+	if err := s.sendVerification(ctx, createdUser); err != nil {
+		correlation.Printf(ctx, "Failed to send verification email: %v", err)
 	}
 
-	return &token, nil
+	return s.issueTokens(ctx, createdUser, "")
 }
 
-func (s *service) Login(ctx context.Context, pbReq *pb.LoginRequest) (*string, error) {
+func (s *service) Login(ctx context.Context, pbReq *pb.LoginRequest) (*Tokens, error) {
 
 	user, err := s.repo.GetUserByEmail(ctx, pbReq.Email)
 	if err != nil {
@@ -101,13 +117,12 @@ func (s *service) Login(ctx context.Context, pbReq *pb.LoginRequest) (*string, e
 	if err != nil {
 		return nil, grpcerrors.Unauthorized("invalid email or password")
 	}
-
-	token, err := auth.GenerateToken(s.cfg.JWT().Token, user.ID)
-	if err != nil {
-		return nil, err
+	// Checked after the password so it doesn't reveal which emails exist
+	if user.Suspended() {
+		return nil, errAccountSuspended
 	}
 
-	return &token, nil
+	return s.issueTokens(ctx, user, "")
 }
 
 func (s *service) GetUserById(ctx context.Context, pbReq *pb.GetUserByIdRequest) (*pb.GetUserByIdResponse, error) {
@@ -134,6 +149,45 @@ func (s *service) GetUserById(ctx context.Context, pbReq *pb.GetUserByIdRequest)
 	}, nil
 }
 
+func (s *service) GetUsersByIds(ctx context.Context, pbReq *pb.GetUsersByIdsRequest) (*pb.GetUsersByIdsResponse, error) {
+	if len(pbReq.UserIds) > maxBatchUserIds {
+		return nil, grpcerrors.InvalidInput(fmt.Sprintf("at most %d user IDs can be requested at once", maxBatchUserIds), map[string]string{
+			"field": "user_ids",
+		})
+	}
+
+	userIds := make([]uint, 0, len(pbReq.UserIds))
+	for _, id := range pbReq.UserIds {
+		userId, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			return nil, grpcerrors.InvalidInput("invalid user ID format", map[string]string{
+				"field": "user_ids",
+				"value": id,
+			})
+		}
+		if !slices.Contains(userIds, uint(userId)) {
+			userIds = append(userIds, uint(userId))
+		}
+	}
+	if len(userIds) == 0 {
+		return &pb.GetUsersByIdsResponse{Success: true}, nil
+	}
+
+	users, err := s.repo.GetUsersByIds(ctx, userIds)
+	if err != nil {
+		return nil, grpcerrors.DatabaseError(err.Error())
+	}
+
+	pbUsers := make([]*pb.User, len(users))
+	for i, user := range users {
+		pbUsers[i] = mapper.ToPbUser(user)
+	}
+	return &pb.GetUsersByIdsResponse{
+		Success: true,
+		Users:   pbUsers,
+	}, nil
+}
+
 func (s *service) GetUsersByEventId(ctx context.Context, pbReq *pb.GetUsersByEventIdRequest) (*pb.GetUsersByEventIdResponse, error) {
 	eventId, err := strconv.ParseUint(pbReq.EventId, 10, 64)
 	if err != nil {
@@ -176,6 +230,7 @@ func (s *service) AddUserToEvent(ctx context.Context, pbReq *pb.AddUserToEventRe
 	if err != nil {
 		return nil, grpcerrors.DatabaseError(err.Error())
 	}
+	s.publishEventChanged(ctx, pbReq.UserId)
 	return &pb.AddUserToEventResponse{
 		Success: true,
 	}, nil
@@ -193,15 +248,24 @@ func (s *service) LeaveEvent(ctx context.Context, pbReq *pb.LeaveEventRequest) (
 	if err != nil {
 		return nil, grpcerrors.DatabaseError(err.Error())
 	}
+	s.publishEventChanged(ctx, pbReq.UserId)
 	return &pb.LeaveEventResponse{
 		Success: true,
 	}, nil
 }
 
+// publishEventChanged lets caches of attendee lists know they are stale
+func (s *service) publishEventChanged(ctx context.Context, userID string) {
+	changed := contracts.UserEventChangedEvent{UserID: userID}
+	if err := s.rb.PublishMessage(ctx, contracts.UserExchange, contracts.UserEventChangedRouting, changed); err != nil {
+		correlation.Printf(ctx, "Failed to publish event membership change: %v", err)
+	}
+}
+
 func (s *service) UpdateUser(ctx context.Context, pbReq *pb.UpdateUserRequest) (*pb.UpdateUserResponse, error) {
 	userId, err := strconv.ParseUint(pbReq.UserId, 10, 64)
 	if err != nil {
-		log.Printf("Error parsing user ID: %v", err)
+		correlation.Printf(ctx, "Error parsing user ID: %v", err)
 		return nil, grpcerrors.InvalidInput("invalid user ID format", map[string]string{
 			"field": "user_id",
 			"value": pbReq.UserId,
@@ -211,14 +275,14 @@ func (s *service) UpdateUser(ctx context.Context, pbReq *pb.UpdateUserRequest) (
 	// Get existing user to preserve contact and education IDs
 	existingUser, err := s.repo.GetUserById(ctx, uint(userId))
 	if err != nil {
-		log.Printf("Error getting user by ID: %v", err)
+		correlation.Printf(ctx, "Error getting user by ID: %v", err)
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, grpcerrors.NotFound("User")
 		}
 		return nil, grpcerrors.DatabaseError(err.Error())
 	}
 	if existingUser == nil {
-		log.Printf("User not found")
+		correlation.Printf(ctx, "User not found")
 		return nil, grpcerrors.NotFound("User")
 	}
 
@@ -247,6 +311,23 @@ func (s *service) UpdateUser(ctx context.Context, pbReq *pb.UpdateUserRequest) (
 		return nil, grpcerrors.DatabaseError(err.Error())
 	}
 
+	// A new address has to be verified again
+	if email := updatedUser.Contact.Email; email != "" && email != existingUser.Contact.Email {
+		if err := s.repo.SetEmailVerified(ctx, updatedUser.ID, nil); err != nil {
+			return nil, grpcerrors.DatabaseError(err.Error())
+		}
+		updatedUser.EmailVerifiedAt = nil
+		if err := s.sendVerification(ctx, updatedUser); err != nil {
+			correlation.Printf(ctx, "Failed to send verification email: %v", err)
+		}
+	}
+
+	// Let caches of this profile (e.g. the gateway's) know it is stale
+	updated := contracts.UserProfileUpdatedEvent{UserID: pbReq.UserId}
+	if err := s.rb.PublishMessage(ctx, contracts.UserExchange, contracts.UserProfileUpdatedRouting, updated); err != nil {
+		correlation.Printf(ctx, "Failed to publish profile update event: %v", err)
+	}
+
 	return &pb.UpdateUserResponse{
 		Success: true,
 		User:    mapper.ToPbUser(updatedUser),
@@ -0,0 +1,327 @@
+package service
+
+import (
+	"context"
+	"crypto"
+	"encoding/base64"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wutthichod/sa-connext/services/user-service/internal/keys"
+	"github.com/wutthichod/sa-connext/services/user-service/internal/models"
+	"github.com/wutthichod/sa-connext/services/user-service/internal/repository"
+	"github.com/wutthichod/sa-connext/shared/auth"
+	"github.com/wutthichod/sa-connext/shared/config"
+	"gorm.io/gorm"
+)
+
+// fakeRepo keeps what the token flows touch in memory. Anything else panics
+// on the nil Repository it embeds.
+type fakeRepo struct {
+	repository.Repository
+
+	mutex         sync.Mutex
+	users         map[uint]*models.User
+	refreshTokens []*models.RefreshToken
+	revokedTokens map[string]*models.RevokedToken
+	signingKeys   []*models.SigningKey
+	verifications map[uint]*models.EmailVerification // by user
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{
+		users:         make(map[uint]*models.User),
+		revokedTokens: make(map[string]*models.RevokedToken),
+		verifications: make(map[uint]*models.EmailVerification),
+	}
+}
+
+func (r *fakeRepo) addUser(user *models.User) *models.User {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	user.ID = uint(len(r.users) + 1)
+	r.users[user.ID] = user
+	return user
+}
+
+func (r *fakeRepo) GetUserById(ctx context.Context, userId uint) (*models.User, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	user, ok := r.users[userId]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *user
+	return &copied, nil
+}
+
+func (r *fakeRepo) CreateRefreshToken(ctx context.Context, token *models.RefreshToken) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	token.ID = uint(len(r.refreshTokens) + 1)
+	r.refreshTokens = append(r.refreshTokens, token)
+	return nil
+}
+
+func (r *fakeRepo) GetRefreshTokenByHash(ctx context.Context, hash string) (*models.RefreshToken, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, token := range r.refreshTokens {
+		if token.TokenHash == hash {
+			copied := *token
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeRepo) MarkRefreshTokenUsed(ctx context.Context, id uint, usedAt time.Time) (bool, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	token := r.refreshTokens[id-1]
+	if token.UsedAt != nil || token.RevokedAt != nil {
+		return false, nil
+	}
+	token.UsedAt = &usedAt
+	return true, nil
+}
+
+func (r *fakeRepo) RevokeRefreshTokenFamily(ctx context.Context, familyID string, revokedAt time.Time) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, token := range r.refreshTokens {
+		if token.FamilyID == familyID && token.RevokedAt == nil {
+			token.RevokedAt = &revokedAt
+		}
+	}
+	return nil
+}
+
+func (r *fakeRepo) RevokeUserRefreshTokens(ctx context.Context, userId uint, revokedAt time.Time) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, token := range r.refreshTokens {
+		if token.UserID == userId && token.RevokedAt == nil {
+			token.RevokedAt = &revokedAt
+		}
+	}
+	return nil
+}
+
+// refreshToken finds the stored row for a token handed to a client
+func (r *fakeRepo) refreshToken(t *testing.T, token string) *models.RefreshToken {
+	t.Helper()
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, stored := range r.refreshTokens {
+		if stored.TokenHash == hashToken(token) {
+			return stored
+		}
+	}
+	t.Fatalf("refresh token %q is not stored", token)
+	return nil
+}
+
+func (r *fakeRepo) RevokeToken(ctx context.Context, token *models.RevokedToken) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if _, ok := r.revokedTokens[token.JTI]; !ok {
+		r.revokedTokens[token.JTI] = token
+	}
+	return nil
+}
+
+func (r *fakeRepo) GetRevokedTokens(ctx context.Context, now time.Time) ([]*models.RevokedToken, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	var tokens []*models.RevokedToken
+	for _, token := range r.revokedTokens {
+		if token.ExpiresAt.After(now) {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens, nil
+}
+
+func (r *fakeRepo) DeleteExpiredRevokedTokens(ctx context.Context, now time.Time) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for jti, token := range r.revokedTokens {
+		if !token.ExpiresAt.After(now) {
+			delete(r.revokedTokens, jti)
+		}
+	}
+	return nil
+}
+
+func (r *fakeRepo) BumpTokenVersion(ctx context.Context, userId uint) (uint, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	user, ok := r.users[userId]
+	if !ok {
+		return 0, gorm.ErrRecordNotFound
+	}
+	user.TokenVersion++
+	return user.TokenVersion, nil
+}
+
+func (r *fakeRepo) GetTokenVersions(ctx context.Context) (map[uint]uint, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	versions := make(map[uint]uint)
+	for id, user := range r.users {
+		if user.TokenVersion > 0 {
+			versions[id] = user.TokenVersion
+		}
+	}
+	return versions, nil
+}
+
+func (r *fakeRepo) CreateSigningKey(ctx context.Context, key *models.SigningKey) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.signingKeys = append(r.signingKeys, key)
+	return nil
+}
+
+func (r *fakeRepo) GetSigningKeys(ctx context.Context, now time.Time) ([]*models.SigningKey, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	var keys []*models.SigningKey
+	for _, key := range r.signingKeys {
+		if key.ExpiresAt.After(now) {
+			keys = append(keys, key)
+		}
+	}
+	slices.SortFunc(keys, func(a, b *models.SigningKey) int { return b.ActiveFrom.Compare(a.ActiveFrom) })
+	return keys, nil
+}
+
+func (r *fakeRepo) DeleteExpiredSigningKeys(ctx context.Context, now time.Time) error {
+	return nil
+}
+
+func (r *fakeRepo) ReplaceEmailVerification(ctx context.Context, verification *models.EmailVerification) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	verification.CreatedAt = time.Now()
+	r.verifications[verification.UserID] = verification
+	return nil
+}
+
+func (r *fakeRepo) GetEmailVerificationByUserId(ctx context.Context, userId uint) (*models.EmailVerification, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	verification, ok := r.verifications[userId]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *verification
+	return &copied, nil
+}
+
+func (r *fakeRepo) ConsumeEmailVerification(ctx context.Context, hash string, verifiedAt time.Time) (*models.EmailVerification, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for userID, verification := range r.verifications {
+		if verification.TokenHash == hash && verification.ExpiresAt.After(verifiedAt) {
+			delete(r.verifications, userID)
+			r.users[userID].EmailVerifiedAt = &verifiedAt
+			return verification, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// published is one message sent through the fakePublisher
+type published struct {
+	routingKey string
+	message    interface{}
+}
+
+// fakePublisher records every published message instead of sending it
+type fakePublisher struct {
+	mutex     sync.Mutex
+	published []published
+}
+
+func (p *fakePublisher) PublishMessage(ctx context.Context, exchange, routingKey string, message interface{}) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.published = append(p.published, published{routingKey, message})
+	return nil
+}
+
+// sent lists the messages published with routingKey
+func (p *fakePublisher) sent(routingKey string) []interface{} {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	var messages []interface{}
+	for _, msg := range p.published {
+		if msg.routingKey == routingKey {
+			messages = append(messages, msg.message)
+		}
+	}
+	return messages
+}
+
+// testConfig overrides the sections the token flows read
+type testConfig struct{ config.Config }
+
+func (testConfig) JWT() config.JWT {
+	return config.JWT{
+		AccessTTL:        15 * time.Minute,
+		RefreshTTL:       24 * time.Hour,
+		Algorithm:        auth.AlgorithmEdDSA,
+		KeyRotation:      24 * time.Hour,
+		KeyOverlap:       time.Hour,
+		KeyEncryptionKey: base64.StdEncoding.EncodeToString(make([]byte, 32)),
+	}
+}
+
+func (testConfig) Verification() config.Verification {
+	return config.Verification{
+		LinkURL:        "https://connext.test/verify",
+		TokenTTL:       time.Hour,
+		ResendCooldown: time.Minute,
+	}
+}
+
+// staticKeys is a KeySet holding the test service's signing key
+type staticKeys map[string]crypto.PublicKey
+
+func (k staticKeys) PublicKey(kid string) (crypto.PublicKey, error) {
+	if key, ok := k[kid]; ok {
+		return key, nil
+	}
+	return nil, auth.ErrUnknownKey
+}
+
+func newTestService(t *testing.T) (*service, *fakeRepo, *fakePublisher) {
+	t.Helper()
+	repo, publisher, cfg := newFakeRepo(), &fakePublisher{}, testConfig{}
+	keyring, err := keys.New(repo, cfg.JWT())
+	if err != nil {
+		t.Fatalf("keys.New: %v", err)
+	}
+	if err := keyring.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	return NewService(repo, publisher, cfg, keyring).(*service), repo, publisher
+}
+
+// validate checks an access token against the service's keys
+func validate(t *testing.T, s *service, token string) *auth.Claims {
+	t.Helper()
+	key, err := s.keys.Signer()
+	if err != nil {
+		t.Fatalf("Signer: %v", err)
+	}
+	claims, err := auth.ValidateToken(staticKeys{key.ID: key.Private.Public()}, token)
+	if err != nil {
+		t.Fatalf("ValidateToken: %v", err)
+	}
+	return claims
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wutthichod/sa-connext/services/user-service/internal/models"
+	"github.com/wutthichod/sa-connext/shared/auth"
+	"github.com/wutthichod/sa-connext/shared/contracts"
+	"github.com/wutthichod/sa-connext/shared/correlation"
+	grpcerrors "github.com/wutthichod/sa-connext/shared/errors"
+	pb "github.com/wutthichod/sa-connext/shared/proto/user"
+	"gorm.io/gorm"
+)
+
+var (
+	errInvalidRefreshToken = grpcerrors.Unauthorized("INVALID_REFRESH_TOKEN: refresh token is invalid or expired")
+	errRefreshTokenReused  = grpcerrors.Unauthorized("REFRESH_TOKEN_REUSED: refresh token was already used; sign in again")
+)
+
+// Tokens is what a client gets when signing in or refreshing
+type Tokens struct {
+	Access           string
+	Refresh          string
+	AccessExpiresIn  time.Duration
+	RefreshExpiresIn time.Duration
+}
+
+// RefreshToken rotates a refresh token. A token that was already traded in
+// means it was copied, so every token descending from the same login is
+// revoked and both parties have to sign in again.
+func (s *service) RefreshToken(ctx context.Context, pbReq *pb.RefreshTokenRequest) (*Tokens, error) {
+	if pbReq.RefreshToken == "" {
+		return nil, errInvalidRefreshToken
+	}
+	stored, err := s.repo.GetRefreshTokenByHash(ctx, hashToken(pbReq.RefreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errInvalidRefreshToken
+		}
+		return nil, grpcerrors.DatabaseError(err.Error())
+	}
+	if stored.RevokedAt != nil || time.Now().After(stored.ExpiresAt) {
+		return nil, errInvalidRefreshToken
+	}
+
+	now := time.Now()
+	fresh, err := s.repo.MarkRefreshTokenUsed(ctx, stored.ID, now)
+	if err != nil {
+		return nil, grpcerrors.DatabaseError(err.Error())
+	}
+	if !fresh {
+		correlation.Printf(ctx, "Refresh token reuse for user %d, revoking family %s", stored.UserID, stored.FamilyID)
+		if err := s.repo.RevokeRefreshTokenFamily(ctx, stored.FamilyID, now); err != nil {
+			return nil, grpcerrors.DatabaseError(err.Error())
+		}
+		return nil, errRefreshTokenReused
+	}
+
+	user, err := s.repo.GetUserById(ctx, stored.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errInvalidRefreshToken
+		}
+		return nil, grpcerrors.DatabaseError(err.Error())
+	}
+	if user.Suspended() {
+		return nil, errAccountSuspended
+	}
+	return s.issueTokens(ctx, user, stored.FamilyID)
+}
+
+// Logout revokes the access token until it expires, and the chain of refresh
+// tokens it was issued with. UserId and Jti are only set when the access
+// token is still valid; once it has expired, the refresh token alone
+// identifies the user, so the family is revoked anyway.
+func (s *service) Logout(ctx context.Context, pbReq *pb.LogoutRequest) (*pb.LogoutResponse, error) {
+	var userID uint
+	if pbReq.UserId != "" {
+		user, err := s.findUser(ctx, pbReq.UserId)
+		if err != nil {
+			return nil, err
+		}
+		userID = user.ID
+	}
+	now := time.Now()
+
+	if pbReq.RefreshToken != "" {
+		stored, err := s.repo.GetRefreshTokenByHash(ctx, hashToken(pbReq.RefreshToken))
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, grpcerrors.DatabaseError(err.Error())
+		}
+		// Someone else's refresh token is ignored rather than revoked
+		if err == nil && (userID == 0 || stored.UserID == userID) {
+			if err := s.repo.RevokeRefreshTokenFamily(ctx, stored.FamilyID, now); err != nil {
+				return nil, grpcerrors.DatabaseError(err.Error())
+			}
+			userID = stored.UserID
+		}
+	}
+
+	expiresAt := time.Unix(pbReq.ExpiresAt, 0)
+	if pbReq.Jti != "" && pbReq.UserId != "" && expiresAt.After(now) {
+		if err := s.repo.RevokeToken(ctx, &models.RevokedToken{JTI: pbReq.Jti, UserID: userID, ExpiresAt: expiresAt}); err != nil {
+			return nil, grpcerrors.DatabaseError(err.Error())
+		}
+		revoked := contracts.UserTokenRevokedEvent{UserID: pbReq.UserId, JTI: pbReq.Jti, ExpiresAt: expiresAt}
+		if err := s.rb.PublishMessage(ctx, contracts.UserExchange, contracts.UserTokenRevokedRouting, revoked); err != nil {
+			correlation.Printf(ctx, "Failed to publish %s: %v", contracts.UserTokenRevokedRouting, err)
+		}
+	}
+
+	// Logouts are frequent enough to keep the list trimmed without a job
+	if err := s.repo.DeleteExpiredRevokedTokens(ctx, now); err != nil {
+		correlation.Printf(ctx, "Failed to delete expired revoked tokens: %v", err)
+	}
+	return &pb.LogoutResponse{Success: true}, nil
+}
+
+// LogoutEverywhere bumps the user's token version, which invalidates every
+// access token issued so far, and revokes all their refresh tokens
+func (s *service) LogoutEverywhere(ctx context.Context, pbReq *pb.LogoutEverywhereRequest) (*pb.LogoutEverywhereResponse, error) {
+	user, err := s.findUser(ctx, pbReq.UserId)
+	if err != nil {
+		return nil, err
+	}
+	version, err := s.repo.BumpTokenVersion(ctx, user.ID)
+	if err != nil {
+		return nil, grpcerrors.DatabaseError(err.Error())
+	}
+	if err := s.repo.RevokeUserRefreshTokens(ctx, user.ID, time.Now()); err != nil {
+		return nil, grpcerrors.DatabaseError(err.Error())
+	}
+
+	loggedOut := contracts.UserLogoutEverywhereEvent{UserID: pbReq.UserId, TokenVersion: version}
+	if err := s.rb.PublishMessage(ctx, contracts.UserExchange, contracts.UserLogoutEverywhereRouting, loggedOut); err != nil {
+		correlation.Printf(ctx, "Failed to publish %s: %v", contracts.UserLogoutEverywhereRouting, err)
+	}
+	return &pb.LogoutEverywhereResponse{Success: true}, nil
+}
+
+// GetRevocations lets the gateway check tokens without calling in on every
+// request
+func (s *service) GetRevocations(ctx context.Context, pbReq *pb.GetRevocationsRequest) (*pb.GetRevocationsResponse, error) {
+	tokens, err := s.repo.GetRevokedTokens(ctx, time.Now())
+	if err != nil {
+		return nil, grpcerrors.DatabaseError(err.Error())
+	}
+	versions, err := s.repo.GetTokenVersions(ctx)
+	if err != nil {
+		return nil, grpcerrors.DatabaseError(err.Error())
+	}
+
+	res := &pb.GetRevocationsResponse{
+		Success:       true,
+		Tokens:        make([]*pb.RevokedToken, 0, len(tokens)),
+		TokenVersions: make(map[string]uint32, len(versions)),
+	}
+	for _, token := range tokens {
+		res.Tokens = append(res.Tokens, &pb.RevokedToken{Jti: token.JTI, ExpiresAt: token.ExpiresAt.Unix()})
+	}
+	for userID, version := range versions {
+		res.TokenVersions[strconv.FormatUint(uint64(userID), 10)] = uint32(version)
+	}
+	return res, nil
+}
+
+// issueTokens signs an access token for the user and adds a refresh token to
+// the family, starting a new family when familyID is empty
+func (s *service) issueTokens(ctx context.Context, user *models.User, familyID string) (*Tokens, error) {
+	cfg := s.cfg.JWT()
+	key, err := s.keys.Signer()
+	if err != nil {
+		return nil, grpcerrors.InternalError(err.Error())
+	}
+	access, err := auth.GenerateToken(key, auth.Claims{
+		UserID:        user.ID,
+		Role:          user.Role,
+		TokenVersion:  user.TokenVersion,
+		EmailVerified: user.EmailVerified(),
+	}, cfg.AccessTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	refresh, err := newOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	if familyID == "" {
+		familyID = uuid.NewString()
+	}
+	if err := s.repo.CreateRefreshToken(ctx, &models.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: hashToken(refresh),
+		ExpiresAt: time.Now().Add(cfg.RefreshTTL),
+	}); err != nil {
+		return nil, grpcerrors.DatabaseError(err.Error())
+	}
+
+	return &Tokens{
+		Access:           access,
+		Refresh:          refresh,
+		AccessExpiresIn:  cfg.AccessTTL,
+		RefreshExpiresIn: cfg.RefreshTTL,
+	}, nil
+}
+
+// newOpaqueToken makes a random token for refresh tokens and email links
+func newOpaqueToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken needs no salt or stretching: opaque tokens are random, so there
+// is nothing to guess from a leaked hash
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
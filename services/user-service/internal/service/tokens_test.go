@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/wutthichod/sa-connext/services/user-service/internal/models"
+	"github.com/wutthichod/sa-connext/shared/contracts"
+	pb "github.com/wutthichod/sa-connext/shared/proto/user"
+)
+
+// signIn starts a new family of refresh tokens, as Login does
+func signIn(t *testing.T, s *service, user *models.User) *Tokens {
+	t.Helper()
+	tokens, err := s.issueTokens(context.Background(), user, "")
+	if err != nil {
+		t.Fatalf("issueTokens: %v", err)
+	}
+	return tokens
+}
+
+func refresh(s *service, token string) (*Tokens, error) {
+	return s.RefreshToken(context.Background(), &pb.RefreshTokenRequest{RefreshToken: token})
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	s, repo, _ := newTestService(t)
+	user := repo.addUser(&models.User{Username: "ann"})
+	first := signIn(t, s, user)
+
+	second, err := refresh(s, first.Refresh)
+	if err != nil {
+		t.Fatalf("RefreshToken: %v", err)
+	}
+	if second.Refresh == first.Refresh {
+		t.Fatal("refresh token was not rotated")
+	}
+	if claims := validate(t, s, second.Access); claims.UserID != user.ID {
+		t.Errorf("access token is for user %d, want %d", claims.UserID, user.ID)
+	}
+	if repo.refreshToken(t, first.Refresh).UsedAt == nil {
+		t.Error("traded-in refresh token is not marked used")
+	}
+	if got, want := repo.refreshToken(t, second.Refresh).FamilyID, repo.refreshToken(t, first.Refresh).FamilyID; got != want {
+		t.Errorf("rotated token is in family %s, want %s", got, want)
+	}
+
+	// The chain goes on with the newest token
+	if _, err := refresh(s, second.Refresh); err != nil {
+		t.Errorf("RefreshToken with the rotated token: %v", err)
+	}
+}
+
+func TestRefreshTokenRejected(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(repo *fakeRepo, stored *models.RefreshToken, user *models.User)
+		token   func(issued string) string
+		wantErr error
+	}{
+		{
+			name:    "empty",
+			token:   func(string) string { return "" },
+			wantErr: errInvalidRefreshToken,
+		},
+		{
+			name:    "unknown",
+			token:   func(string) string { return "made-up" },
+			wantErr: errInvalidRefreshToken,
+		},
+		{
+			name: "expired",
+			prepare: func(repo *fakeRepo, stored *models.RefreshToken, user *models.User) {
+				stored.ExpiresAt = time.Now().Add(-time.Second)
+			},
+			wantErr: errInvalidRefreshToken,
+		},
+		{
+			name: "revoked",
+			prepare: func(repo *fakeRepo, stored *models.RefreshToken, user *models.User) {
+				revokedAt := time.Now()
+				stored.RevokedAt = &revokedAt
+			},
+			wantErr: errInvalidRefreshToken,
+		},
+		{
+			name: "user deleted",
+			prepare: func(repo *fakeRepo, stored *models.RefreshToken, user *models.User) {
+				delete(repo.users, user.ID)
+			},
+			wantErr: errInvalidRefreshToken,
+		},
+		{
+			name: "user suspended",
+			prepare: func(repo *fakeRepo, stored *models.RefreshToken, user *models.User) {
+				suspendedAt := time.Now()
+				repo.users[user.ID].SuspendedAt = &suspendedAt
+			},
+			wantErr: errAccountSuspended,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, repo, _ := newTestService(t)
+			user := repo.addUser(&models.User{Username: "ann"})
+			issued := signIn(t, s, user).Refresh
+			if tt.prepare != nil {
+				tt.prepare(repo, repo.refreshToken(t, issued), user)
+			}
+			token := issued
+			if tt.token != nil {
+				token = tt.token(issued)
+			}
+
+			if _, err := refresh(s, token); !errors.Is(err, tt.wantErr) {
+				t.Errorf("RefreshToken error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRefreshTokenReuseRevokesFamily(t *testing.T) {
+	s, repo, _ := newTestService(t)
+	user := repo.addUser(&models.User{Username: "ann"})
+	stolen := signIn(t, s, user)
+	otherDevice := signIn(t, s, user)
+
+	rotated, err := refresh(s, stolen.Refresh)
+	if err != nil {
+		t.Fatalf("RefreshToken: %v", err)
+	}
+	// Whoever copied the token trades it in again
+	if _, err := refresh(s, stolen.Refresh); !errors.Is(err, errRefreshTokenReused) {
+		t.Fatalf("reused token error = %v, want %v", err, errRefreshTokenReused)
+	}
+
+	// The whole chain is revoked, including the token the rightful owner holds
+	if _, err := refresh(s, rotated.Refresh); !errors.Is(err, errInvalidRefreshToken) {
+		t.Errorf("token from the reused family error = %v, want %v", err, errInvalidRefreshToken)
+	}
+	// Other logins are left alone
+	if _, err := refresh(s, otherDevice.Refresh); err != nil {
+		t.Errorf("token from another family: %v", err)
+	}
+}
+
+func TestLogout(t *testing.T) {
+	soon := time.Now().Add(10 * time.Minute).Unix()
+	tests := []struct {
+		name string
+		// req is completed with the refresh token the test user signed in with
+		req               *pb.LogoutRequest
+		wantFamilyRevoked bool
+		wantRevokedJTIs   []string
+	}{
+		{
+			name:              "with a valid access token",
+			req:               &pb.LogoutRequest{UserId: "1", Jti: "jti-1", ExpiresAt: soon},
+			wantFamilyRevoked: true,
+			wantRevokedJTIs:   []string{"jti-1"},
+		},
+		{
+			name:              "after the access token expired",
+			req:               &pb.LogoutRequest{},
+			wantFamilyRevoked: true,
+		},
+		{
+			name:            "with someone else's refresh token",
+			req:             &pb.LogoutRequest{UserId: "2", Jti: "jti-2", ExpiresAt: soon},
+			wantRevokedJTIs: []string{"jti-2"},
+		},
+		{
+			name:              "with an access token that has just expired",
+			req:               &pb.LogoutRequest{UserId: "1", Jti: "jti-1", ExpiresAt: time.Now().Add(-time.Second).Unix()},
+			wantFamilyRevoked: true,
+		},
+		{
+			name:              "with a jti but no validated user",
+			req:               &pb.LogoutRequest{Jti: "jti-1", ExpiresAt: soon},
+			wantFamilyRevoked: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, repo, publisher := newTestService(t)
+			ann := repo.addUser(&models.User{Username: "ann"})
+			repo.addUser(&models.User{Username: "bob"})
+			tokens := signIn(t, s, ann)
+
+			tt.req.RefreshToken = tokens.Refresh
+			if _, err := s.Logout(context.Background(), tt.req); err != nil {
+				t.Fatalf("Logout: %v", err)
+			}
+
+			if revoked := repo.refreshToken(t, tokens.Refresh).RevokedAt != nil; revoked != tt.wantFamilyRevoked {
+				t.Errorf("refresh family revoked = %v, want %v", revoked, tt.wantFamilyRevoked)
+			}
+			res, err := s.GetRevocations(context.Background(), &pb.GetRevocationsRequest{})
+			if err != nil {
+				t.Fatalf("GetRevocations: %v", err)
+			}
+			var jtis []string
+			for _, token := range res.Tokens {
+				jtis = append(jtis, token.Jti)
+			}
+			if !slices.Equal(jtis, tt.wantRevokedJTIs) {
+				t.Errorf("revoked jtis = %v, want %v", jtis, tt.wantRevokedJTIs)
+			}
+			if events := publisher.sent(contracts.UserTokenRevokedRouting); len(events) != len(tt.wantRevokedJTIs) {
+				t.Errorf("published %d revocations, want %d", len(events), len(tt.wantRevokedJTIs))
+			}
+		})
+	}
+}
+
+func TestLogoutEverywhere(t *testing.T) {
+	s, repo, publisher := newTestService(t)
+	ann := repo.addUser(&models.User{Username: "ann"})
+	bob := repo.addUser(&models.User{Username: "bob"})
+	phone, laptop, other := signIn(t, s, ann), signIn(t, s, ann), signIn(t, s, bob)
+	if claims := validate(t, s, phone.Access); claims.TokenVersion != 0 {
+		t.Fatalf("token version = %d before logging out everywhere, want 0", claims.TokenVersion)
+	}
+
+	if _, err := s.LogoutEverywhere(context.Background(), &pb.LogoutEverywhereRequest{UserId: "1"}); err != nil {
+		t.Fatalf("LogoutEverywhere: %v", err)
+	}
+
+	for _, tokens := range []*Tokens{phone, laptop} {
+		if _, err := refresh(s, tokens.Refresh); !errors.Is(err, errInvalidRefreshToken) {
+			t.Errorf("refresh after logging out everywhere error = %v, want %v", err, errInvalidRefreshToken)
+		}
+	}
+	if _, err := refresh(s, other.Refresh); err != nil {
+		t.Errorf("another user's refresh: %v", err)
+	}
+
+	// Tokens carrying an older version are refused by the gateway
+	res, err := s.GetRevocations(context.Background(), &pb.GetRevocationsRequest{})
+	if err != nil {
+		t.Fatalf("GetRevocations: %v", err)
+	}
+	if want := map[string]uint32{"1": 1}; !maps.Equal(res.TokenVersions, want) {
+		t.Errorf("token versions = %v, want %v", res.TokenVersions, want)
+	}
+	events := publisher.sent(contracts.UserLogoutEverywhereRouting)
+	if want := []interface{}{contracts.UserLogoutEverywhereEvent{UserID: "1", TokenVersion: 1}}; !slices.Equal(events, want) {
+		t.Errorf("published %v, want %v", events, want)
+	}
+
+	// Signing in again issues tokens with the new version
+	user, _ := repo.GetUserById(context.Background(), ann.ID)
+	if claims := validate(t, s, signIn(t, s, user).Access); claims.TokenVersion != 1 {
+		t.Errorf("token version = %d after signing in again, want 1", claims.TokenVersion)
+	}
+}
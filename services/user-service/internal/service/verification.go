@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/wutthichod/sa-connext/services/user-service/internal/models"
+	"github.com/wutthichod/sa-connext/shared/contracts"
+	"github.com/wutthichod/sa-connext/shared/correlation"
+	grpcerrors "github.com/wutthichod/sa-connext/shared/errors"
+	pb "github.com/wutthichod/sa-connext/shared/proto/user"
+	"gorm.io/gorm"
+)
+
+var errInvalidVerificationToken = grpcerrors.InvalidInput("INVALID_VERIFICATION_TOKEN: the verification link is invalid or has expired", nil)
+
+func (s *service) VerifyEmail(ctx context.Context, pbReq *pb.VerifyEmailRequest) (*pb.VerifyEmailResponse, error) {
+	if pbReq.Token == "" {
+		return nil, errInvalidVerificationToken
+	}
+	// Verifying spends the token, so the link can't be replayed
+	verification, err := s.repo.ConsumeEmailVerification(ctx, hashToken(pbReq.Token), time.Now())
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errInvalidVerificationToken
+		}
+		return nil, grpcerrors.DatabaseError(err.Error())
+	}
+
+	userID := strconv.FormatUint(uint64(verification.UserID), 10)
+	correlation.Printf(ctx, "User %s verified their email", userID)
+	// Profiles show whether the email is verified
+	updated := contracts.UserProfileUpdatedEvent{UserID: userID}
+	if err := s.rb.PublishMessage(ctx, contracts.UserExchange, contracts.UserProfileUpdatedRouting, updated); err != nil {
+		correlation.Printf(ctx, "Failed to publish profile update event: %v", err)
+	}
+	return &pb.VerifyEmailResponse{Success: true, UserId: userID}, nil
+}
+
+// ResendVerification sends a new link, which makes earlier ones stop working
+func (s *service) ResendVerification(ctx context.Context, pbReq *pb.ResendVerificationRequest) (*pb.ResendVerificationResponse, error) {
+	user, err := s.findUser(ctx, pbReq.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if user.EmailVerified() {
+		return nil, grpcerrors.InvalidInput("EMAIL_ALREADY_VERIFIED: the email is already verified", nil)
+	}
+
+	// The gateway limits requests too, but only per instance
+	last, err := s.repo.GetEmailVerificationByUserId(ctx, user.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, grpcerrors.DatabaseError(err.Error())
+	}
+	if err == nil {
+		if wait := time.Until(last.CreatedAt.Add(s.cfg.Verification().ResendCooldown)); wait > 0 {
+			return nil, grpcerrors.TooManyRequests(fmt.Sprintf("a verification email was just sent; try again in %d seconds", int(wait.Seconds())+1))
+		}
+	}
+
+	if err := s.sendVerification(ctx, user); err != nil {
+		return nil, err
+	}
+	return &pb.ResendVerificationResponse{Success: true}, nil
+}
+
+// sendVerification emails the user a link to verify their address through
+// notification-service
+func (s *service) sendVerification(ctx context.Context, user *models.User) error {
+	cfg := s.cfg.Verification()
+	token, err := newOpaqueToken()
+	if err != nil {
+		return grpcerrors.InternalError(err.Error())
+	}
+	if err := s.repo.ReplaceEmailVerification(ctx, &models.EmailVerification{
+		UserID:    user.ID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(cfg.TokenTTL),
+	}); err != nil {
+		return grpcerrors.DatabaseError(err.Error())
+	}
+
+	link := cfg.LinkURL + "?token=" + url.QueryEscape(token)
+	email := contracts.EmailEvent{
+		To:      user.Contact.Email,
+		Subject: "Verify your email",
+		Body: fmt.Sprintf("Hi %s,\r\n\r\nPlease confirm this is your email by opening the link below. It expires in %s.\r\n\r\n%s",
+			user.Username, cfg.TokenTTL, link),
+	}
+	if err := s.rb.PublishMessage(ctx, "notification.exchange", "notification.email", email); err != nil {
+		return grpcerrors.InternalError(fmt.Sprintf("failed to send verification email: %v", err))
+	}
+	return nil
+}
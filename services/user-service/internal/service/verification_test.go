@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wutthichod/sa-connext/services/user-service/internal/models"
+	"github.com/wutthichod/sa-connext/shared/contracts"
+	pb "github.com/wutthichod/sa-connext/shared/proto/user"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// emailedToken reads the token out of the last verification email sent
+func emailedToken(t *testing.T, publisher *fakePublisher) string {
+	t.Helper()
+	emails := publisher.sent("notification.email")
+	if len(emails) == 0 {
+		t.Fatal("no verification email was sent")
+	}
+	body := emails[len(emails)-1].(contracts.EmailEvent).Body
+	link := body[strings.LastIndex(body, "\n")+1:]
+	parsed, err := url.Parse(link)
+	if err != nil {
+		t.Fatalf("link %q: %v", link, err)
+	}
+	return parsed.Query().Get("token")
+}
+
+func verify(s *service, token string) (*pb.VerifyEmailResponse, error) {
+	return s.VerifyEmail(context.Background(), &pb.VerifyEmailRequest{Token: token})
+}
+
+func TestVerifyEmail(t *testing.T) {
+	tests := []struct {
+		name string
+		// prepare runs after the first email is sent and returns the token
+		// to verify with
+		prepare func(t *testing.T, s *service, repo *fakeRepo, publisher *fakePublisher, emailed string) string
+		wantErr error
+	}{
+		{
+			name: "emailed token",
+			prepare: func(t *testing.T, s *service, repo *fakeRepo, publisher *fakePublisher, emailed string) string {
+				return emailed
+			},
+		},
+		{
+			name: "empty token",
+			prepare: func(t *testing.T, s *service, repo *fakeRepo, publisher *fakePublisher, emailed string) string {
+				return ""
+			},
+			wantErr: errInvalidVerificationToken,
+		},
+		{
+			name: "unknown token",
+			prepare: func(t *testing.T, s *service, repo *fakeRepo, publisher *fakePublisher, emailed string) string {
+				return "made-up"
+			},
+			wantErr: errInvalidVerificationToken,
+		},
+		{
+			name: "expired token",
+			prepare: func(t *testing.T, s *service, repo *fakeRepo, publisher *fakePublisher, emailed string) string {
+				repo.verifications[1].ExpiresAt = time.Now().Add(-time.Second)
+				return emailed
+			},
+			wantErr: errInvalidVerificationToken,
+		},
+		{
+			name: "token already used",
+			prepare: func(t *testing.T, s *service, repo *fakeRepo, publisher *fakePublisher, emailed string) string {
+				if _, err := verify(s, emailed); err != nil {
+					t.Fatalf("first VerifyEmail: %v", err)
+				}
+				repo.users[1].EmailVerifiedAt = nil
+				return emailed
+			},
+			wantErr: errInvalidVerificationToken,
+		},
+		{
+			name: "token replaced by a newer email",
+			prepare: func(t *testing.T, s *service, repo *fakeRepo, publisher *fakePublisher, emailed string) string {
+				if err := s.sendVerification(context.Background(), repo.users[1]); err != nil {
+					t.Fatalf("sendVerification: %v", err)
+				}
+				return emailed
+			},
+			wantErr: errInvalidVerificationToken,
+		},
+		{
+			name: "token from the newer email",
+			prepare: func(t *testing.T, s *service, repo *fakeRepo, publisher *fakePublisher, emailed string) string {
+				if err := s.sendVerification(context.Background(), repo.users[1]); err != nil {
+					t.Fatalf("sendVerification: %v", err)
+				}
+				return emailedToken(t, publisher)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, repo, publisher := newTestService(t)
+			user := repo.addUser(&models.User{Username: "ann", Contact: models.Contact{Email: "ann@example.com"}})
+			if err := s.sendVerification(context.Background(), user); err != nil {
+				t.Fatalf("sendVerification: %v", err)
+			}
+			token := tt.prepare(t, s, repo, publisher, emailedToken(t, publisher))
+
+			res, err := verify(s, token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("VerifyEmail error = %v, want %v", err, tt.wantErr)
+			}
+			verified := repo.users[user.ID].EmailVerified()
+			if verified != (tt.wantErr == nil) {
+				t.Errorf("email verified = %v, want %v", verified, tt.wantErr == nil)
+			}
+			if err != nil {
+				return
+			}
+			if res.UserId != "1" {
+				t.Errorf("verified user %q, want 1", res.UserId)
+			}
+			if _, err := verify(s, token); !errors.Is(err, errInvalidVerificationToken) {
+				t.Errorf("replayed link error = %v, want %v", err, errInvalidVerificationToken)
+			}
+		})
+	}
+}
+
+func TestResendVerification(t *testing.T) {
+	s, repo, publisher := newTestService(t)
+	user := repo.addUser(&models.User{Username: "ann", Contact: models.Contact{Email: "ann@example.com"}})
+	resend := func() error {
+		_, err := s.ResendVerification(context.Background(), &pb.ResendVerificationRequest{UserId: "1"})
+		return err
+	}
+
+	if err := resend(); err != nil {
+		t.Fatalf("ResendVerification: %v", err)
+	}
+	if email := publisher.sent("notification.email")[0].(contracts.EmailEvent); email.To != user.Contact.Email {
+		t.Errorf("email sent to %q, want %q", email.To, user.Contact.Email)
+	}
+	if err := resend(); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("resend within the cooldown error = %v, want ResourceExhausted", err)
+	}
+
+	repo.verifications[user.ID].CreatedAt = time.Now().Add(-time.Minute)
+	first := emailedToken(t, publisher)
+	if err := resend(); err != nil {
+		t.Fatalf("resend after the cooldown: %v", err)
+	}
+	if _, err := verify(s, first); !errors.Is(err, errInvalidVerificationToken) {
+		t.Errorf("link from the earlier email error = %v, want %v", err, errInvalidVerificationToken)
+	}
+	if _, err := verify(s, emailedToken(t, publisher)); err != nil {
+		t.Fatalf("VerifyEmail: %v", err)
+	}
+	if err := resend(); err == nil {
+		t.Error("sent another email after the address was verified")
+	}
+}
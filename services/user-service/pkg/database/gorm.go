@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/wutthichod/sa-connext/shared/config"
+	"github.com/wutthichod/sa-connext/shared/tracing"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -56,6 +57,10 @@ func InitDatabase(cfg config.Database) (*gorm.DB, error) {
 				done <- connErr
 				return
 			}
+			if connErr = tracing.InstrumentGORM(db); connErr != nil {
+				done <- connErr
+				return
+			}
 
 			sqlDB, connErr := db.DB()
 			if connErr != nil {
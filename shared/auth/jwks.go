@@ -0,0 +1,147 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// jwksMaxAge is how long fetched keys are used before fetching again
+	jwksMaxAge = 10 * time.Minute
+	// jwksMinInterval keeps tokens with made-up kids from turning into a
+	// fetch each
+	jwksMinInterval = 30 * time.Second
+)
+
+// RemoteKeySet is a KeySet fetched from a JWKS endpoint and cached. A kid it
+// doesn't know triggers a fetch, so keys published after the last one are
+// picked up right away.
+type RemoteKeySet struct {
+	url    string
+	client *http.Client
+
+	mutex     sync.RWMutex
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+	fetching  chan struct{} // closed when the fetch in flight finishes
+}
+
+var remoteKeySets sync.Map // url to *RemoteKeySet
+
+// JWKS returns the key set at url, shared by every caller so the keys are
+// fetched once per process
+func JWKS(url string) *RemoteKeySet {
+	if keys, ok := remoteKeySets.Load(url); ok {
+		return keys.(*RemoteKeySet)
+	}
+	keys, _ := remoteKeySets.LoadOrStore(url, &RemoteKeySet{
+		url:    url,
+		client: &http.Client{Timeout: 5 * time.Second},
+		keys:   make(map[string]crypto.PublicKey),
+	})
+	return keys.(*RemoteKeySet)
+}
+
+func (s *RemoteKeySet) PublicKey(kid string) (crypto.PublicKey, error) {
+	s.mutex.RLock()
+	key, ok := s.keys[kid]
+	due := s.due(ok)
+	s.mutex.RUnlock()
+
+	if due {
+		done := s.refresh()
+		// A stale key is served while the fetch runs; only an unknown kid
+		// has to wait for it
+		if !ok {
+			<-done
+			s.mutex.RLock()
+			key, ok = s.keys[kid]
+			s.mutex.RUnlock()
+		}
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownKey, kid)
+	}
+	return key, nil
+}
+
+// due reports whether the keys should be fetched again. The caller holds
+// the mutex.
+func (s *RemoteKeySet) due(known bool) bool {
+	stale := time.Since(s.fetchedAt) > jwksMaxAge
+	return (!known || stale) && time.Since(s.fetchedAt) > jwksMinInterval
+}
+
+// refresh starts a fetch unless one is already in flight and returns a
+// channel that is closed once it finishes. Concurrent misses share one fetch.
+func (s *RemoteKeySet) refresh() <-chan struct{} {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.fetching != nil {
+		return s.fetching
+	}
+	done := make(chan struct{})
+	if !s.due(false) {
+		// Another fetch finished since the caller looked
+		close(done)
+		return done
+	}
+	s.fetching = done
+
+	go func() {
+		defer close(done)
+		keys, err := s.fetch()
+
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		// Failed fetches count too, so an unreachable endpoint is retried at
+		// most every jwksMinInterval
+		s.fetchedAt = time.Now()
+		s.fetching = nil
+		if err != nil {
+			// Keep using what we have; the endpoint may be back shortly
+			log.Printf("auth: failed to fetch %s: %v", s.url, err)
+			return
+		}
+		s.keys = keys
+	}()
+	return done
+}
+
+func (s *RemoteKeySet) fetch() (map[string]crypto.PublicKey, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", res.Status)
+	}
+
+	var set JWKSet
+	if err := json.NewDecoder(res.Body).Decode(&set); err != nil {
+		return nil, err
+	}
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		key, err := jwk.PublicKey()
+		if err != nil {
+			log.Printf("auth: skipping key from %s: %v", s.url, err)
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	return keys, nil
+}
@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// jwksServer serves whichever keys it is given and counts the fetches
+type jwksServer struct {
+	*httptest.Server
+	mutex   sync.Mutex
+	set     JWKSet
+	fetches atomic.Int32
+}
+
+func newJWKSServer(t *testing.T) *jwksServer {
+	s := &jwksServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.fetches.Add(1)
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		json.NewEncoder(w).Encode(s.set)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *jwksServer) publish(t *testing.T, key SigningKey) {
+	t.Helper()
+	jwk, err := PublicJWK(key)
+	if err != nil {
+		t.Fatalf("PublicJWK: %v", err)
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.set.Keys = append(s.set.Keys, jwk)
+}
+
+// age makes the last fetch look d older
+func age(keys *RemoteKeySet, d time.Duration) {
+	keys.mutex.Lock()
+	defer keys.mutex.Unlock()
+	keys.fetchedAt = keys.fetchedAt.Add(-d)
+}
+
+func TestRemoteKeySetPicksUpNewKeys(t *testing.T) {
+	server := newJWKSServer(t)
+	current := newSigningKey(t, "current", AlgorithmEdDSA)
+	server.publish(t, current)
+	keys := JWKS(server.URL)
+
+	token, _ := GenerateToken(current, Claims{UserID: 1}, time.Minute)
+	for i := 0; i < 3; i++ {
+		if _, err := ValidateToken(keys, token); err != nil {
+			t.Fatalf("ValidateToken: %v", err)
+		}
+	}
+	if n := server.fetches.Load(); n != 1 {
+		t.Errorf("fetched %d times for a known key, want once", n)
+	}
+
+	// Made-up kids don't turn into a fetch each
+	if _, err := keys.PublicKey("made-up"); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("PublicKey(made-up) error = %v, want ErrUnknownKey", err)
+	}
+	if n := server.fetches.Load(); n != 1 {
+		t.Errorf("fetched %d times within the minimum interval, want once", n)
+	}
+
+	// A key published since is fetched as soon as a token names it
+	next := newSigningKey(t, "next", AlgorithmRS256)
+	server.publish(t, next)
+	age(keys, jwksMinInterval)
+	token, _ = GenerateToken(next, Claims{UserID: 1}, time.Minute)
+	if _, err := ValidateToken(keys, token); err != nil {
+		t.Errorf("token signed with the new key: %v", err)
+	}
+	if n := server.fetches.Load(); n != 2 {
+		t.Errorf("fetched %d times, want 2", n)
+	}
+}
+
+func TestRemoteKeySetKeepsKeysWhenFetchFails(t *testing.T) {
+	server := newJWKSServer(t)
+	key := newSigningKey(t, "kid", AlgorithmEdDSA)
+	server.publish(t, key)
+	keys := JWKS(server.URL)
+	if _, err := keys.PublicKey(key.ID); err != nil {
+		t.Fatalf("PublicKey: %v", err)
+	}
+
+	server.Close()
+	age(keys, jwksMaxAge+time.Second)
+	// The stale key is served while the failing fetch runs
+	if _, err := keys.PublicKey(key.ID); err != nil {
+		t.Errorf("PublicKey with the endpoint down: %v", err)
+	}
+	<-keys.refresh()
+	if _, err := keys.PublicKey(key.ID); err != nil {
+		t.Errorf("PublicKey after the failed fetch: %v", err)
+	}
+}
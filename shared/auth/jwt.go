@@ -7,35 +7,69 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// Roles a user can hold. Tokens issued before roles existed carry none and
+// are treated as RoleUser.
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
 )
 
 type Claims struct {
-	UserID uint `json:"user_id"`
+	UserID uint   `json:"user_id"`
+	Role   string `json:"role,omitempty"`
+	// TokenVersion goes up when the user logs out everywhere, which
+	// invalidates every token carrying an older version
+	TokenVersion  uint `json:"tv,omitempty"`
+	EmailVerified bool `json:"email_verified,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func GenerateToken(jwtSecret string, userID uint) (string, error) {
-	claims := &Claims{
-		UserID: userID,
-		RegisteredClaims: jwt.RegisteredClaims{
-			Issuer:    "connext.app",
-			Subject:   fmt.Sprint(userID),
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * 24)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-		},
+// IsAdmin reports whether the token grants platform administration
+func (c *Claims) IsAdmin() bool {
+	return c.Role == RoleAdmin
+}
+
+// GenerateToken issues an access token with the user's claims, valid for
+// ttl, signed with key and naming it in the kid header. Each token gets a
+// unique ID (jti) so it can be revoked on its own.
+func GenerateToken(key SigningKey, claims Claims, ttl time.Duration) (string, error) {
+	method := jwt.GetSigningMethod(key.Algorithm)
+	if method == nil {
+		return "", fmt.Errorf("unsupported signing algorithm %q", key.Algorithm)
+	}
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ID:        uuid.NewString(),
+		Issuer:    "connext.app",
+		Subject:   fmt.Sprint(claims.UserID),
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(jwtSecret))
+	token := jwt.NewWithClaims(method, &claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.Private)
 }
 
-func ValidateToken(jwtSecret, tokenString string) (*Claims, error) {
+// ValidateToken checks the token against the public key its kid names
+func ValidateToken(keys KeySet, tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		kid, _ := token.Header["kid"].(string)
+		if kid == "" {
+			return nil, errors.New("token has no kid")
+		}
+		key, err := keys.PublicKey(kid)
+		if err != nil {
+			return nil, err
+		}
+		// The algorithm comes from the token, so it must match the key
+		if token.Method.Alg() != algorithmFor(key) {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		return []byte(jwtSecret), nil
-	})
+		return key, nil
+	}, jwt.WithValidMethods(Algorithms))
 
 	if err != nil {
 		log.Print(err)
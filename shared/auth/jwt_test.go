@@ -0,0 +1,123 @@
+package auth
+
+import (
+	"crypto"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// staticKeys is a KeySet that never changes
+type staticKeys map[string]crypto.PublicKey
+
+func (k staticKeys) PublicKey(kid string) (crypto.PublicKey, error) {
+	if key, ok := k[kid]; ok {
+		return key, nil
+	}
+	return nil, ErrUnknownKey
+}
+
+func newSigningKey(t *testing.T, id, algorithm string) SigningKey {
+	t.Helper()
+	private, err := NewPrivateKey(algorithm)
+	if err != nil {
+		t.Fatalf("NewPrivateKey(%s): %v", algorithm, err)
+	}
+	return SigningKey{ID: id, Algorithm: algorithm, Private: private}
+}
+
+func TestValidateToken(t *testing.T) {
+	rsaKey := newSigningKey(t, "rsa", AlgorithmRS256)
+	edKey := newSigningKey(t, "ed", AlgorithmEdDSA)
+	impostor := newSigningKey(t, "ed", AlgorithmEdDSA)
+	keys := staticKeys{rsaKey.ID: rsaKey.Private.Public(), edKey.ID: edKey.Private.Public()}
+
+	tests := []struct {
+		name    string
+		key     SigningKey
+		ttl     time.Duration
+		wantErr bool
+	}{
+		{name: "RS256", key: rsaKey, ttl: time.Minute},
+		{name: "EdDSA", key: edKey, ttl: time.Minute},
+		{name: "expired", key: edKey, ttl: -time.Minute, wantErr: true},
+		{name: "unknown kid", key: newSigningKey(t, "other", AlgorithmEdDSA), ttl: time.Minute, wantErr: true},
+		{name: "another key under a known kid", key: impostor, ttl: time.Minute, wantErr: true},
+		{name: "kid naming a key for another algorithm", key: SigningKey{ID: rsaKey.ID, Algorithm: AlgorithmEdDSA, Private: edKey.Private}, ttl: time.Minute, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := Claims{UserID: 7, Role: RoleAdmin, TokenVersion: 2, EmailVerified: true}
+			token, err := GenerateToken(tt.key, claims, tt.ttl)
+			if err != nil {
+				t.Fatalf("GenerateToken: %v", err)
+			}
+			got, err := ValidateToken(keys, token)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateToken error = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.UserID != claims.UserID || got.Role != claims.Role || got.TokenVersion != claims.TokenVersion || !got.EmailVerified {
+				t.Errorf("claims = %+v, want %+v", got, claims)
+			}
+			if got.ID == "" {
+				t.Error("token has no jti")
+			}
+		})
+	}
+}
+
+func TestGenerateTokenGivesEachTokenAnID(t *testing.T) {
+	key := newSigningKey(t, "ed", AlgorithmEdDSA)
+	keys := staticKeys{key.ID: key.Private.Public()}
+	first, _ := GenerateToken(key, Claims{UserID: 1}, time.Minute)
+	second, _ := GenerateToken(key, Claims{UserID: 1}, time.Minute)
+	a, _ := ValidateToken(keys, first)
+	b, _ := ValidateToken(keys, second)
+	if a == nil || b == nil || a.ID == b.ID {
+		t.Error("two tokens share a jti")
+	}
+}
+
+func TestJWKRoundTrip(t *testing.T) {
+	for _, algorithm := range Algorithms {
+		t.Run(algorithm, func(t *testing.T) {
+			key := newSigningKey(t, "kid", algorithm)
+			jwk, err := PublicJWK(key)
+			if err != nil {
+				t.Fatalf("PublicJWK: %v", err)
+			}
+			// Through JSON, as validators receive it
+			data, _ := json.Marshal(jwk)
+			var decoded JWK
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			public, err := decoded.PublicKey()
+			if err != nil {
+				t.Fatalf("PublicKey: %v", err)
+			}
+			want := key.Private.Public().(interface{ Equal(crypto.PublicKey) bool })
+			if !want.Equal(public) {
+				t.Error("decoded key differs from the signing key's public half")
+			}
+			if decoded.Alg != algorithm || decoded.Kid != "kid" {
+				t.Errorf("alg, kid = %s, %s; want %s, kid", decoded.Alg, decoded.Kid, algorithm)
+			}
+		})
+	}
+}
+
+func TestJWKRejectsMalformedKeys(t *testing.T) {
+	for name, jwk := range map[string]JWK{
+		"unknown type":         {Kty: "EC"},
+		"short Ed25519 key":    {Kty: "OKP", Crv: "Ed25519", X: "AAAA"},
+		"RSA exponent too big": {Kty: "RSA", N: "AQAB", E: "AQAAAAAAAAAA"},
+	} {
+		if _, err := jwk.PublicKey(); err == nil {
+			t.Errorf("%s was accepted", name)
+		}
+	}
+}
@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 )
@@ -15,6 +18,12 @@ type Config interface {
 	Database() Database
 	JWT() JWT
 	Notification() Notification
+	Tracing() Tracing
+	Metrics() Metrics
+	Shutdown() Shutdown
+	API() API
+	Resilience() Resilience
+	Verification() Verification
 	String() string
 }
 
@@ -32,12 +41,32 @@ type RABBITMQ struct {
 }
 
 type Database struct {
-	DSN  string
-	Name string
+	// Backend selects the storage implementation for services that support
+	// more than one, e.g. "mongo" (default) or "memory" for chat-service
+	Backend string
+	DSN     string
+	Name    string
 }
 
 type JWT struct {
-	Token string
+	// AccessTTL is how long an access token is valid; clients then trade
+	// their refresh token, valid for RefreshTTL, for a new pair
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+	// Algorithm is what user-service signs new keys for, "EdDSA" or "RS256"
+	Algorithm string `validate:"oneof=EdDSA RS256"`
+	// KeyRotation is how long a key signs tokens. Each key is published
+	// KeyOverlap before it starts signing and stays published KeyOverlap
+	// after it stops, which must cover AccessTTL.
+	KeyRotation time.Duration
+	KeyOverlap  time.Duration
+	// JWKSAddr is where user-service serves the public keys, and JWKSURL
+	// where validators fetch them
+	JWKSAddr string
+	JWKSURL  string
+	// KeyEncryptionKey encrypts the signing keys user-service stores: 32
+	// bytes, base64 encoded. Only user-service is given it.
+	KeyEncryptionKey string
 }
 
 type Notification struct {
@@ -45,12 +74,75 @@ type Notification struct {
 	EmailPW string
 }
 
+type Tracing struct {
+	// Exporter is one of "none", "stdout", "memory" or "otlp"
+	Exporter     string
+	OTLPEndpoint string
+	// SampleRatio is the fraction of new traces to record, between 0 and 1
+	SampleRatio float64
+}
+
+type Metrics struct {
+	// Addr is where services without an HTTP server expose /metrics
+	Addr string
+}
+
+type Shutdown struct {
+	// Timeout bounds how long a service may take to drain and stop
+	Timeout time.Duration
+}
+
+type API struct {
+	// LegacyDeprecated and LegacySunset are announced on requests to the
+	// unversioned paths, which are served from /v1 until the sunset
+	LegacyDeprecated time.Time
+	LegacySunset     time.Time
+	// ProxyHeader carries the client IP, but is only believed on requests
+	// from TrustedProxies (IPs or CIDRs); with none, the peer address is
+	// the client IP
+	ProxyHeader    string
+	TrustedProxies []string `validate:"dive,ip|cidr"`
+}
+
+type Resilience struct {
+	// Timeout is the deadline of each attempt at a downstream call, unless
+	// the caller's own deadline is sooner
+	Timeout time.Duration
+	// MaxAttempts bounds how often an idempotent call is tried, counting the
+	// first attempt; other calls are never retried
+	MaxAttempts int
+	// BackoffBase doubles after every retry up to BackoffMax
+	BackoffBase time.Duration
+	BackoffMax  time.Duration
+	// BreakerThreshold consecutive failures open a downstream's breaker,
+	// which fails calls fast for BreakerCooldown before letting one through
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
+type Verification struct {
+	// LinkURL is the page verification emails link to, with the token
+	// appended as ?token=
+	LinkURL        string
+	TokenTTL       time.Duration
+	ResendCooldown time.Duration
+	// Restricted lists what users may not do until they verify their email:
+	// "dm", "create_group" and "create_event"
+	Restricted []string `validate:"dive,oneof=dm create_group create_event"`
+}
+
 type config struct {
-	AppCfg      App
-	DatabaseCfg Database
-	RabbitMqCfg RABBITMQ
-	JwtCfg      JWT
-	NotiCfg     Notification
+	AppCfg          App
+	DatabaseCfg     Database
+	RabbitMqCfg     RABBITMQ
+	JwtCfg          JWT
+	NotiCfg         Notification
+	TracingCfg      Tracing
+	MetricsCfg      Metrics
+	ShutdownCfg     Shutdown
+	APICfg          API
+	ResilienceCfg   Resilience
+	VerificationCfg Verification
 }
 
 func (c *config) App() App                   { return c.AppCfg }
@@ -58,6 +150,12 @@ func (c *config) Database() Database         { return c.DatabaseCfg }
 func (c *config) JWT() JWT                   { return c.JwtCfg }
 func (c *config) RABBITMQ() RABBITMQ         { return c.RabbitMqCfg }
 func (c *config) Notification() Notification { return c.NotiCfg }
+func (c *config) Tracing() Tracing           { return c.TracingCfg }
+func (c *config) Metrics() Metrics           { return c.MetricsCfg }
+func (c *config) Shutdown() Shutdown         { return c.ShutdownCfg }
+func (c *config) API() API                   { return c.APICfg }
+func (c *config) Resilience() Resilience     { return c.ResilienceCfg }
+func (c *config) Verification() Verification { return c.VerificationCfg }
 
 func (c *config) String() string {
 	jsonBytes, err := json.MarshalIndent(c, "", "  ")
@@ -78,11 +176,19 @@ func InitConfig() (Config, error) {
 			Organizer:    getEnv("ORGANIZER_ADDR", ""),
 		},
 		DatabaseCfg: Database{
-			DSN:  getEnv("DATABASE_DSN", ""),
-			Name: getEnv("DATABASE_NAME", ""),
+			Backend: getEnv("DATABASE_BACKEND", "mongo"),
+			DSN:     getEnv("DATABASE_DSN", ""),
+			Name:    getEnv("DATABASE_NAME", ""),
 		},
 		JwtCfg: JWT{
-			Token: getEnv("JWT_SECRET", ""),
+			AccessTTL:        getEnvDuration("JWT_ACCESS_TTL", 15*time.Minute),
+			RefreshTTL:       getEnvDuration("JWT_REFRESH_TTL", 30*24*time.Hour),
+			Algorithm:        getEnv("JWT_ALGORITHM", "EdDSA"),
+			KeyRotation:      getEnvDuration("JWT_KEY_ROTATION", 7*24*time.Hour),
+			KeyOverlap:       getEnvDuration("JWT_KEY_OVERLAP", time.Hour),
+			JWKSAddr:         getEnv("JWKS_ADDR", ":8086"),
+			JWKSURL:          getEnv("JWKS_URL", "http://localhost:8086/.well-known/jwks.json"),
+			KeyEncryptionKey: getEnv("JWT_KEY_ENCRYPTION_KEY", ""),
 		},
 		RabbitMqCfg: RABBITMQ{
 			URI: getEnv("RABBITMQ_URI", ""),
@@ -91,6 +197,37 @@ func InitConfig() (Config, error) {
 			Email:   getEnv("EMAIL", ""),
 			EmailPW: getEnv("EMAIL_PW", ""),
 		},
+		TracingCfg: Tracing{
+			Exporter:     getEnv("TRACING_EXPORTER", "none"),
+			OTLPEndpoint: getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", "localhost:4317"),
+			SampleRatio:  getEnvFloat("TRACING_SAMPLE_RATIO", 1),
+		},
+		MetricsCfg: Metrics{
+			Addr: getEnv("METRICS_ADDR", ":9090"),
+		},
+		ShutdownCfg: Shutdown{
+			Timeout: getEnvDuration("SHUTDOWN_TIMEOUT", 15*time.Second),
+		},
+		APICfg: API{
+			LegacyDeprecated: getEnvTime("LEGACY_API_DEPRECATED", time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC)),
+			LegacySunset:     getEnvTime("LEGACY_API_SUNSET", time.Date(2027, time.April, 18, 0, 0, 0, 0, time.UTC)),
+			ProxyHeader:      getEnv("PROXY_HEADER", "X-Forwarded-For"),
+			TrustedProxies:   getEnvList("TRUSTED_PROXIES", nil),
+		},
+		ResilienceCfg: Resilience{
+			Timeout:          getEnvDuration("DOWNSTREAM_TIMEOUT", 5*time.Second),
+			MaxAttempts:      getEnvInt("DOWNSTREAM_MAX_ATTEMPTS", 3),
+			BackoffBase:      getEnvDuration("DOWNSTREAM_BACKOFF_BASE", 100*time.Millisecond),
+			BackoffMax:       getEnvDuration("DOWNSTREAM_BACKOFF_MAX", time.Second),
+			BreakerThreshold: getEnvInt("BREAKER_THRESHOLD", 5),
+			BreakerCooldown:  getEnvDuration("BREAKER_COOLDOWN", 30*time.Second),
+		},
+		VerificationCfg: Verification{
+			LinkURL:        getEnv("VERIFY_EMAIL_URL", "http://localhost:3000/verify-email"),
+			TokenTTL:       getEnvDuration("VERIFY_EMAIL_TTL", 24*time.Hour),
+			ResendCooldown: getEnvDuration("VERIFY_EMAIL_RESEND_COOLDOWN", time.Minute),
+			Restricted:     getEnvList("UNVERIFIED_RESTRICTIONS", []string{"dm"}),
+		},
 	}
 
 	if err := validator.New().Struct(cfg); err != nil {
@@ -106,3 +243,61 @@ func getEnv(key, defaultVal string) string {
 	}
 	return defaultVal
 }
+
+func getEnvFloat(key string, defaultVal float64) float64 {
+	if val := os.Getenv(key); val != "" {
+		if f, err := strconv.ParseFloat(val, 64); err == nil {
+			return f
+		}
+		log.Printf("ignoring invalid %s=%q", key, val)
+	}
+	return defaultVal
+}
+
+func getEnvInt(key string, defaultVal int) int {
+	if val := os.Getenv(key); val != "" {
+		if i, err := strconv.Atoi(val); err == nil {
+			return i
+		}
+		log.Printf("ignoring invalid %s=%q", key, val)
+	}
+	return defaultVal
+}
+
+func getEnvDuration(key string, defaultVal time.Duration) time.Duration {
+	if val := os.Getenv(key); val != "" {
+		if d, err := time.ParseDuration(val); err == nil {
+			return d
+		}
+		log.Printf("ignoring invalid %s=%q", key, val)
+	}
+	return defaultVal
+}
+
+// getEnvList splits a comma-separated value; "none" gives an empty list
+func getEnvList(key string, defaultVal []string) []string {
+	val := os.Getenv(key)
+	if val == "" {
+		return defaultVal
+	}
+	if val == "none" {
+		return nil
+	}
+	var list []string
+	for _, item := range strings.Split(val, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func getEnvTime(key string, defaultVal time.Time) time.Time {
+	if val := os.Getenv(key); val != "" {
+		if t, err := time.Parse(time.RFC3339, val); err == nil {
+			return t
+		}
+		log.Printf("ignoring invalid %s=%q", key, val)
+	}
+	return defaultVal
+}
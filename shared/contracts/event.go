@@ -1,5 +1,19 @@
 package contracts
 
+// Event lifecycle events are published on the "event" topic exchange
+const (
+	EventExchange       = "event"
+	EventCreatedRouting = "event.created"
+	EventDeletedRouting = "event.deleted"
+	// EventUpdatedRouting covers changes to an event's details or organizers
+	EventUpdatedRouting = "event.updated"
+)
+
+// EventChangedEvent announces that an event was created, updated or deleted
+type EventChangedEvent struct {
+	EventID string `json:"event_id"`
+}
+
 type CreateEventRequest struct {
 	Name        string `json:"name"`
 	Detail      string `json:"detail"`
@@ -17,14 +31,24 @@ type JoinEventResponse struct {
 	EventID uint `json:"event_id"`
 }
 
+// GetEventResponse is an event as the requester may see it: JoiningCode is
+// empty unless they organize it
 type GetEventResponse struct {
-	EventID     uint   `json:"event_id"`
-	Name        string `json:"name"`
-	Detail      string `json:"detail"`
-	Location    string `json:"location"`
-	Date        string `json:"date"`
-	JoiningCode string `json:"joining_code"`
-	OrganizerId string `json:"organizer_id"`
+	EventID        uint     `json:"event_id"`
+	Name           string   `json:"name"`
+	Detail         string   `json:"detail"`
+	Location       string   `json:"location"`
+	Date           string   `json:"date"`
+	JoiningCode    string   `json:"joining_code"`
+	OrganizerId    string   `json:"organizer_id"`
+	CoOrganizerIDs []string `json:"co_organizer_ids"`
+}
+
+type UpdateEventRequest struct {
+	Name     string `json:"name"`
+	Detail   string `json:"detail"`
+	Location string `json:"location"`
+	Date     string `json:"date"`
 }
 
 type JoinEventRequest struct {
@@ -0,0 +1,47 @@
+package contracts
+
+import "time"
+
+// User events are published on the "user" topic exchange
+const (
+	UserExchange                = "user"
+	UserProfileUpdatedRouting   = "user.profile.updated"
+	UserEventChangedRouting     = "user.event.changed"
+	UserSuspendedRouting        = "user.suspended"
+	UserReinstatedRouting       = "user.reinstated"
+	UserTokenRevokedRouting     = "user.token.revoked"
+	UserLogoutEverywhereRouting = "user.logout.everywhere"
+)
+
+// UserProfileUpdatedEvent announces that a user's public profile changed,
+// so consumers holding a cached copy can drop it
+type UserProfileUpdatedEvent struct {
+	UserID string `json:"user_id"`
+}
+
+// UserEventChangedEvent announces that a user joined or left an event, which
+// changes the attendee lists of both the old and the new event
+type UserEventChangedEvent struct {
+	UserID string `json:"user_id"`
+}
+
+// UserSuspensionChangedEvent announces that an admin suspended or reinstated
+// a user; the routing key tells which
+type UserSuspensionChangedEvent struct {
+	UserID string `json:"user_id"`
+}
+
+// UserTokenRevokedEvent announces that one access token was revoked before
+// it expired
+type UserTokenRevokedEvent struct {
+	UserID    string    `json:"user_id"`
+	JTI       string    `json:"jti"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// UserLogoutEverywhereEvent announces that a user logged out everywhere, so tokens
+// carrying an older TokenVersion are no longer valid
+type UserLogoutEverywhereEvent struct {
+	UserID       string `json:"user_id"`
+	TokenVersion uint   `json:"token_version"`
+}
@@ -0,0 +1,61 @@
+// Package correlation carries a request ID from the gateway through every
+// service a user action touches, so their logs can be tied together.
+package correlation
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Header is the HTTP header the ID travels in
+	Header = "X-Request-ID"
+	// MetadataKey is the gRPC metadata key and AMQP header the ID travels in
+	MetadataKey = "x-request-id"
+)
+
+// maxIDLength bounds IDs accepted from clients
+const maxIDLength = 128
+
+type contextKey struct{}
+
+// NewID generates a fresh request ID
+func NewID() string {
+	return uuid.NewString()
+}
+
+// WithID returns a copy of ctx carrying id
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID in ctx, or "" if there is none
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// valid reports whether an ID received from outside can be trusted as-is
+func valid(id string) bool {
+	if id == "" || len(id) > maxIDLength {
+		return false
+	}
+	for _, r := range id {
+		if r < 0x21 || r > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// orNew returns id when it is usable, otherwise a fresh one
+func orNew(id string) string {
+	if valid(id) {
+		return id
+	}
+	return NewID()
+}
@@ -0,0 +1,41 @@
+package correlation
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// FiberMiddleware accepts the caller's X-Request-ID or generates one, echoes
+// it in the response, and makes it available to c.Context() and
+// c.UserContext() for downstream calls
+func FiberMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := orNew(c.Get(Header))
+		c.Locals(contextKey{}, id)
+		c.SetUserContext(WithID(c.UserContext(), id))
+		c.Set(Header, id)
+		return c.Next()
+	}
+}
+
+// Transport adds the request ID of each outgoing request's context as the
+// X-Request-ID header
+type Transport struct {
+	Base http.RoundTripper
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	id := FromContext(req.Context())
+	if id == "" || req.Header.Get(Header) != "" {
+		return base.RoundTrip(req)
+	}
+	// RoundTrippers must not modify the caller's request
+	req = req.Clone(req.Context())
+	req.Header.Set(Header, id)
+	return base.RoundTrip(req)
+}
@@ -0,0 +1,24 @@
+package correlation
+
+import (
+	"context"
+	"fmt"
+	"log"
+)
+
+// Printf logs like log.Printf, prefixed with the request ID in ctx
+func Printf(ctx context.Context, format string, v ...any) {
+	log.Print(prefix(ctx) + fmt.Sprintf(format, v...))
+}
+
+// Println logs like log.Println, prefixed with the request ID in ctx
+func Println(ctx context.Context, v ...any) {
+	log.Print(prefix(ctx) + fmt.Sprintln(v...))
+}
+
+func prefix(ctx context.Context) string {
+	if id := FromContext(ctx); id != "" {
+		return "[request_id=" + id + "] "
+	}
+	return ""
+}
@@ -14,10 +14,15 @@ const (
 	CodeInvalidInput    = "INVALID_INPUT"
 	CodeNotFound        = "NOT_FOUND"
 	CodeUnauthorized    = "UNAUTHORIZED"
+	CodeForbidden       = "FORBIDDEN"
 	CodeAlreadyExists   = "ALREADY_EXISTS"
 	CodeInternalError   = "INTERNAL_ERROR"
 	CodeDatabaseError   = "DATABASE_ERROR"
 	CodeValidationError = "VALIDATION_ERROR"
+	CodeTooManyRequests = "TOO_MANY_REQUESTS"
+	// CodeEmailNotVerified is reported, unlike the codes above, as the prefix
+	// of the status message so the gateway passes it on as the error_code
+	CodeEmailNotVerified = "EMAIL_NOT_VERIFIED"
 )
 
 // GRPCError represents a structured error for gRPC responses
@@ -55,8 +60,12 @@ func (e *GRPCError) ToStatus() error {
 		grpcCode = codes.NotFound
 	case CodeUnauthorized:
 		grpcCode = codes.Unauthenticated
+	case CodeForbidden:
+		grpcCode = codes.PermissionDenied
 	case CodeAlreadyExists:
 		grpcCode = codes.AlreadyExists
+	case CodeTooManyRequests:
+		grpcCode = codes.ResourceExhausted
 	case CodeDatabaseError:
 		grpcCode = codes.Internal
 	case CodeInternalError:
@@ -96,6 +105,14 @@ func Unauthorized(message string) error {
 	return NewGRPCError(CodeUnauthorized, message, nil).ToStatus()
 }
 
+// PermissionDenied creates a forbidden error for authenticated callers
+func PermissionDenied(message string) error {
+	if message == "" {
+		message = "permission denied"
+	}
+	return NewGRPCError(CodeForbidden, message, nil).ToStatus()
+}
+
 // AlreadyExists creates an already exists error
 func AlreadyExists(resource string, field string) error {
 	msg := fmt.Sprintf("%s with this %s already exists", resource, field)
@@ -113,6 +130,17 @@ func InternalError(message string) error {
 	return NewGRPCError(CodeInternalError, message, nil).ToStatus()
 }
 
+// TooManyRequests asks the caller to slow down
+func TooManyRequests(message string) error {
+	return NewGRPCError(CodeTooManyRequests, message, nil).ToStatus()
+}
+
+// EmailNotVerified refuses an action held back until the caller verifies
+// their email
+func EmailNotVerified(message string) error {
+	return status.Error(codes.PermissionDenied, CodeEmailNotVerified+": "+message)
+}
+
 // DatabaseError creates a database error
 func DatabaseError(message string) error {
 	if message == "" {
@@ -0,0 +1,49 @@
+package health
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// RegisterGRPC adds the standard health service to server and keeps it up to
+// date by running checker every interval until ctx is done. The overall
+// status is reported under the empty service name and each dependency
+// under its own name, e.g. "postgres".
+func RegisterGRPC(ctx context.Context, server *grpc.Server, checker *Checker, interval time.Duration) *grpchealth.Server {
+	healthServer := grpchealth.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	update := func() {
+		report := checker.Run(ctx)
+		healthServer.SetServingStatus("", servingStatus(report.Status))
+		for name, dependency := range report.Dependencies {
+			healthServer.SetServingStatus(name, servingStatus(dependency.Status))
+		}
+	}
+
+	update()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				update()
+			}
+		}
+	}()
+	return healthServer
+}
+
+func servingStatus(status string) healthpb.HealthCheckResponse_ServingStatus {
+	if status == StatusUp {
+		return healthpb.HealthCheckResponse_SERVING
+	}
+	return healthpb.HealthCheckResponse_NOT_SERVING
+}
@@ -0,0 +1,103 @@
+// Package health runs dependency checks for readiness probes. HTTP services
+// serve the result on /healthz and /readyz, gRPC services through the
+// standard grpc.health.v1 service.
+package health
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// checkTimeout bounds every check, so one hanging dependency cannot stall a probe
+const checkTimeout = 2 * time.Second
+
+// Check reports whether a dependency is usable
+type Check func(ctx context.Context) error
+
+// DependencyStatus is the result of a single check
+type DependencyStatus struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Report is the result of running every check
+type Report struct {
+	Status       string                      `json:"status"`
+	Dependencies map[string]DependencyStatus `json:"dependencies"`
+}
+
+// Ready reports whether every dependency is up
+func (r Report) Ready() bool {
+	return r.Status == StatusUp
+}
+
+// Checker holds the named checks of a service
+type Checker struct {
+	mutex  sync.RWMutex
+	checks map[string]Check
+}
+
+func NewChecker() *Checker {
+	return &Checker{checks: make(map[string]Check)}
+}
+
+// Add registers a check under name, replacing any earlier one
+func (c *Checker) Add(name string, check Check) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.checks[name] = check
+}
+
+// Names returns the registered dependency names in order
+func (c *Checker) Names() []string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	names := make([]string, 0, len(c.checks))
+	for name := range c.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Run runs every check concurrently
+func (c *Checker) Run(ctx context.Context) Report {
+	c.mutex.RLock()
+	checks := make(map[string]Check, len(c.checks))
+	for name, check := range c.checks {
+		checks[name] = check
+	}
+	c.mutex.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	report := Report{Status: StatusUp, Dependencies: make(map[string]DependencyStatus, len(checks))}
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := DependencyStatus{Status: StatusUp}
+			if err := check(ctx); err != nil {
+				result = DependencyStatus{Status: StatusDown, Error: err.Error()}
+			}
+			mutex.Lock()
+			defer mutex.Unlock()
+			report.Dependencies[name] = result
+			if result.Status == StatusDown {
+				report.Status = StatusDown
+			}
+		}()
+	}
+	wg.Wait()
+	return report
+}
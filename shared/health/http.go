@@ -0,0 +1,29 @@
+package health
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/wutthichod/sa-connext/shared/contracts"
+)
+
+// RegisterRoutes serves /healthz, which only says the process is alive, and
+// /readyz, which runs every check and fails with 503 if any dependency is down
+func RegisterRoutes(app *fiber.App, checker *Checker) {
+	app.Get("/healthz", func(c *fiber.Ctx) error {
+		return c.Status(fiber.StatusOK).JSON(contracts.Resp{
+			Success: true,
+			Data:    Report{Status: StatusUp},
+		})
+	})
+
+	app.Get("/readyz", func(c *fiber.Ctx) error {
+		report := checker.Run(c.UserContext())
+		status := fiber.StatusOK
+		if !report.Ready() {
+			status = fiber.StatusServiceUnavailable
+		}
+		return c.Status(status).JSON(contracts.Resp{
+			Success: report.Ready(),
+			Data:    report,
+		})
+	})
+}
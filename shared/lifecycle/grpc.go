@@ -0,0 +1,27 @@
+package lifecycle
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// GRPCServer stops server gracefully, letting in-flight RPCs finish. RPCs
+// still running when ctx is done are cancelled.
+func GRPCServer(server *grpc.Server) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		done := make(chan struct{})
+		go func() {
+			server.GracefulStop()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			server.Stop()
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,97 @@
+// Package lifecycle runs a service until it is asked to stop, then shuts its
+// components down in order within a deadline.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+type hook struct {
+	name string
+	stop func(ctx context.Context) error
+}
+
+// Lifecycle waits for SIGINT or SIGTERM, or for a server to fail, and then
+// runs the registered shutdown hooks
+type Lifecycle struct {
+	timeout time.Duration
+	ctx     context.Context
+	cancel  context.CancelFunc
+
+	mutex sync.Mutex
+	hooks []hook
+	err   error
+}
+
+// New creates a lifecycle whose shutdown hooks must all finish within timeout
+func New(timeout time.Duration) *Lifecycle {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	return &Lifecycle{timeout: timeout, ctx: ctx, cancel: cancel}
+}
+
+// Context is cancelled as soon as shutdown begins. Background loops should
+// stop when it is done.
+func (l *Lifecycle) Context() context.Context {
+	return l.ctx
+}
+
+// OnShutdown registers stop to run on shutdown. Hooks run one at a time in
+// the order they were registered, so register servers before the
+// connections they depend on.
+func (l *Lifecycle) OnShutdown(name string, stop func(ctx context.Context) error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.hooks = append(l.hooks, hook{name: name, stop: stop})
+}
+
+// Go runs serve in the background. If it returns before shutdown has begun,
+// the service shuts down and Wait reports the error.
+func (l *Lifecycle) Go(name string, serve func() error) {
+	go func() {
+		err := serve()
+		if l.ctx.Err() != nil {
+			return // stopped by a shutdown hook
+		}
+		if err == nil {
+			err = errors.New("stopped unexpectedly")
+		}
+		l.mutex.Lock()
+		l.err = errors.Join(l.err, fmt.Errorf("%s: %w", name, err))
+		l.mutex.Unlock()
+		l.cancel()
+	}()
+}
+
+// Wait blocks until shutdown begins, runs every hook and returns the error
+// that triggered the shutdown, if any, along with any hook failures
+func (l *Lifecycle) Wait() error {
+	<-l.ctx.Done()
+	l.cancel()
+	log.Printf("Shutting down, waiting up to %v", l.timeout)
+
+	ctx, cancel := context.WithTimeout(context.Background(), l.timeout)
+	defer cancel()
+
+	l.mutex.Lock()
+	hooks := l.hooks
+	err := l.err
+	l.mutex.Unlock()
+
+	for _, h := range hooks {
+		if hookErr := h.stop(ctx); hookErr != nil {
+			log.Printf("Shutdown: %s: %v", h.name, hookErr)
+			err = errors.Join(err, fmt.Errorf("%s: %w", h.name, hookErr))
+			continue
+		}
+		log.Printf("Shutdown: %s stopped", h.name)
+	}
+	return err
+}
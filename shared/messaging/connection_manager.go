@@ -1,18 +1,27 @@
 package messaging
 
 import (
+	"context"
 	"errors"
 	"log"
 	"sync"
+	"time"
 
 	"github.com/gofiber/websocket/v2"
 	"github.com/wutthichod/sa-connext/shared/contracts"
+	"github.com/wutthichod/sa-connext/shared/metrics"
 )
 
 var (
 	ErrConnectionNotFound = errors.New("connection not found")
 )
 
+const (
+	// reconnectHint is the close reason sent to clients on shutdown
+	reconnectHint     = "server restarting, please reconnect"
+	closeWriteTimeout = time.Second
+)
+
 // connWrapper is a wrapper around the websocket connection to allow for thread-safe operations
 // WebSocket connections are not thread-safe by default
 type connWrapper struct {
@@ -20,8 +29,13 @@ type connWrapper struct {
 	mutex sync.Mutex
 }
 
+// subscriberBuffer is how many messages a slow subscriber may fall behind
+// before further messages to it are dropped
+const subscriberBuffer = 32
+
 type ConnectionManager struct {
 	connections map[string]*connWrapper // userId -> connection
+	subscribers map[string]map[chan contracts.WSMessage]struct{}
 	mutex       sync.RWMutex
 }
 
@@ -29,6 +43,33 @@ type ConnectionManager struct {
 func NewConnectionManager() *ConnectionManager {
 	return &ConnectionManager{
 		connections: make(map[string]*connWrapper),
+		subscribers: make(map[string]map[chan contracts.WSMessage]struct{}),
+	}
+}
+
+// Subscribe receives a copy of every message delivered to userID, for
+// listeners such as GraphQL subscriptions that sit next to the user's chat
+// websocket. The returned function unsubscribes and closes the channel.
+func (cm *ConnectionManager) Subscribe(userID string) (<-chan contracts.WSMessage, func()) {
+	ch := make(chan contracts.WSMessage, subscriberBuffer)
+	cm.mutex.Lock()
+	if cm.subscribers[userID] == nil {
+		cm.subscribers[userID] = make(map[chan contracts.WSMessage]struct{})
+	}
+	cm.subscribers[userID][ch] = struct{}{}
+	cm.mutex.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			cm.mutex.Lock()
+			defer cm.mutex.Unlock()
+			delete(cm.subscribers[userID], ch)
+			if len(cm.subscribers[userID]) == 0 {
+				delete(cm.subscribers, userID)
+			}
+			close(ch)
+		})
 	}
 }
 
@@ -40,6 +81,7 @@ func (cm *ConnectionManager) Add(userID string, conn *websocket.Conn) {
 		conn:  conn,
 		mutex: sync.Mutex{},
 	}
+	metrics.WebSocketConnections.Set(float64(len(cm.connections)))
 	log.Printf("Added connection for user %s", userID)
 }
 
@@ -48,6 +90,37 @@ func (cm *ConnectionManager) Remove(userID string) {
 	cm.mutex.Lock()
 	defer cm.mutex.Unlock()
 	delete(cm.connections, userID)
+	metrics.WebSocketConnections.Set(float64(len(cm.connections)))
+}
+
+// CloseAll tells every connected client that the server is going away and
+// closes its connection. Clients are asked to reconnect, which will reach
+// another instance once this one has stopped accepting connections.
+func (cm *ConnectionManager) CloseAll(ctx context.Context) error {
+	cm.mutex.RLock()
+	wrappers := make([]*connWrapper, 0, len(cm.connections))
+	for _, wrapper := range cm.connections {
+		wrappers = append(wrappers, wrapper)
+	}
+	cm.mutex.RUnlock()
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(closeWriteTimeout)
+	}
+	closeFrame := websocket.FormatCloseMessage(websocket.CloseServiceRestart, reconnectHint)
+
+	var errs []error
+	for _, wrapper := range wrappers {
+		wrapper.mutex.Lock()
+		if err := wrapper.conn.WriteControl(websocket.CloseMessage, closeFrame, deadline); err != nil && !errors.Is(err, websocket.ErrCloseSent) {
+			errs = append(errs, err)
+		}
+		// Unblocks the handler's read loop, which then removes the connection
+		_ = wrapper.conn.Close()
+		wrapper.mutex.Unlock()
+	}
+	return errors.Join(errs...)
 }
 
 // Get retrieves the WebSocket connection for a user
@@ -61,13 +134,25 @@ func (cm *ConnectionManager) Get(userID string) (*websocket.Conn, bool) {
 	return wrapper.conn, true
 }
 
-// SendMessage sends a message safely to a connected user
+// SendMessage sends a message safely to a connected user, and to the
+// user's subscribers
 func (cm *ConnectionManager) SendMessage(userID string, message contracts.WSMessage) error {
 	cm.mutex.RLock()
 	wrapper, exists := cm.connections[userID]
+	subscribed := len(cm.subscribers[userID]) > 0
+	for ch := range cm.subscribers[userID] {
+		select {
+		case ch <- message:
+		default:
+			log.Printf("Dropped message for slow subscriber of user %s", userID)
+		}
+	}
 	cm.mutex.RUnlock()
 
 	if !exists {
+		if subscribed {
+			return nil
+		}
 		return ErrConnectionNotFound
 	}
 
@@ -5,7 +5,10 @@ import (
 	"log"
 	"net/smtp"
 
+	amqp "github.com/rabbitmq/amqp091-go"
 	"github.com/wutthichod/sa-connext/shared/contracts"
+	"github.com/wutthichod/sa-connext/shared/correlation"
+	"github.com/wutthichod/sa-connext/shared/metrics"
 )
 
 type EmailConsumer struct {
@@ -31,37 +34,37 @@ func NewEmailConsumer(rb *RabbitMQ, queueName, from, password string) *EmailCons
 
 // Start begins consuming messages from the queue
 func (ec *EmailConsumer) Start() error {
-	msgs, err := ec.rb.Channel.Consume(
-		ec.queueName,
-		"",
-		true,  // auto-ack (set to false for manual ack if needed)
-		false, // not exclusive
-		false, // no-local
-		false, // no-wait
-		nil,
-	)
-	if err != nil {
+	// Failed emails are logged, not retried
+	if err := ec.rb.consume(ec.queueName, false, ec.handle); err != nil {
 		return err
 	}
 
-	go func() {
-		for msg := range msgs {
-			var event contracts.EmailEvent
-			if err := json.Unmarshal(msg.Body, &event); err != nil {
-				log.Println("Failed to unmarshal EmailEvent:", err)
-				continue
-			}
-
-			if err := ec.sendEmail(&event); err != nil {
-				log.Printf("Failed to send email to %s: %v", event.To, err)
-				continue
-			}
+	log.Printf(" [*] EmailConsumer listening on queue: %s", ec.queueName)
+	return nil
+}
 
-			log.Printf("Email sent to %s successfully!", event.To)
-		}
+// handle sends the email described by one delivery
+func (ec *EmailConsumer) handle(msg amqp.Delivery) error {
+	metrics.QueueMessages.WithLabelValues(ec.queueName, "consumed").Inc()
+	ctx, span := startConsumeSpan(ec.queueName, msg)
+	var err error
+	defer func() {
+		endSpan(span, err)
+		recordResult(ec.queueName, err)
 	}()
 
-	log.Printf(" [*] EmailConsumer listening on queue: %s", ec.queueName)
+	var event contracts.EmailEvent
+	if err = json.Unmarshal(msg.Body, &event); err != nil {
+		correlation.Println(ctx, "Failed to unmarshal EmailEvent:", err)
+		return err
+	}
+
+	if err = ec.sendEmail(&event); err != nil {
+		correlation.Printf(ctx, "Failed to send email to %s: %v", event.To, err)
+		return err
+	}
+
+	correlation.Printf(ctx, "Email sent to %s successfully!", event.To)
 	return nil
 }
 
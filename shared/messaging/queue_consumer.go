@@ -2,9 +2,11 @@ package messaging
 
 import (
 	"encoding/json"
-	"log"
 
+	amqp "github.com/rabbitmq/amqp091-go"
 	"github.com/wutthichod/sa-connext/shared/contracts"
+	"github.com/wutthichod/sa-connext/shared/correlation"
+	"github.com/wutthichod/sa-connext/shared/metrics"
 )
 
 type QueueConsumer struct {
@@ -22,47 +24,43 @@ func NewQueueConsumer(rb *RabbitMQ, connMgr *ConnectionManager, queueName string
 }
 
 func (qc *QueueConsumer) Start() error {
-	msgs, err := qc.rb.Channel.Consume(
-		qc.queueName,
-		"",
-		true,
-		false,
-		false,
-		false,
-		nil,
-	)
-	if err != nil {
-		return err
-	}
-
-	go func() {
-		for msg := range msgs {
-			var msgBody contracts.AmqpMessage
-			if err := json.Unmarshal(msg.Body, &msgBody); err != nil {
-				log.Println("Failed to unmarshal message:", err)
-				continue
-			}
+	// Users who are not connected miss the message; it is not retried
+	return qc.rb.consume(qc.queueName, false, qc.handle)
+}
 
-			userID := msgBody.OwnerID
+// handle forwards one delivery to the websocket of the user it is meant for
+func (qc *QueueConsumer) handle(msg amqp.Delivery) error {
+	metrics.QueueMessages.WithLabelValues(qc.queueName, "consumed").Inc()
+	ctx, span := startConsumeSpan(qc.queueName, msg)
+	var err error
+	defer func() {
+		endSpan(span, err)
+		recordResult(qc.queueName, err)
+	}()
 
-			var payload any
-			if msgBody.Data != nil {
-				if err := json.Unmarshal(msgBody.Data, &payload); err != nil {
-					log.Println("Failed to unmarshal payload:", err)
-					continue
-				}
-			}
+	var msgBody contracts.AmqpMessage
+	if err = json.Unmarshal(msg.Body, &msgBody); err != nil {
+		correlation.Println(ctx, "Failed to unmarshal message:", err)
+		return err
+	}
 
-			clientMsg := contracts.WSMessage{
-				Type: msg.RoutingKey,
-				Data: payload,
-			}
+	userID := msgBody.OwnerID
 
-			if err := qc.connMgr.SendMessage(userID, clientMsg); err != nil {
-				log.Printf("Failed to send message to user %s: %v", userID, err)
-			}
+	var payload any
+	if msgBody.Data != nil {
+		if err = json.Unmarshal(msgBody.Data, &payload); err != nil {
+			correlation.Println(ctx, "Failed to unmarshal payload:", err)
+			return err
 		}
-	}()
+	}
 
-	return nil
+	clientMsg := contracts.WSMessage{
+		Type: msg.RoutingKey,
+		Data: payload,
+	}
+
+	if err = qc.connMgr.SendMessage(userID, clientMsg); err != nil {
+		correlation.Printf(ctx, "Failed to send message to user %s: %v", userID, err)
+	}
+	return err
 }
@@ -3,17 +3,31 @@ package messaging
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"sync"
+
+	"github.com/google/uuid"
 
 	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/wutthichod/sa-connext/shared/correlation"
+	"github.com/wutthichod/sa-connext/shared/metrics"
 )
 
 type RabbitMQ struct {
 	conn    *amqp.Connection
 	Channel *amqp.Channel
+
+	mutex     sync.Mutex
+	consumers []string       // tags of the consumers started on Channel
+	inFlight  sync.WaitGroup // one per consumer loop still running
+	stopping  bool           // set by StopConsumers; prefetched deliveries are requeued
 }
 
+// consumerPrefetch bounds how many unacknowledged deliveries the broker
+// pushes to each consumer
+const consumerPrefetch = 10
+
 // NewRabbitMQ connects and returns a RabbitMQ client
 func NewRabbitMQ(uri string) (*RabbitMQ, error) {
 	conn, err := amqp.Dial(uri)
@@ -43,6 +57,17 @@ func (r *RabbitMQ) Close() {
 	}
 }
 
+// Check reports whether the connection and channel are still open
+func (r *RabbitMQ) Check(ctx context.Context) error {
+	if r.conn == nil || r.conn.IsClosed() {
+		return errors.New("rabbitmq connection is closed")
+	}
+	if r.Channel == nil || r.Channel.IsClosed() {
+		return errors.New("rabbitmq channel is closed")
+	}
+	return nil
+}
+
 func (r *RabbitMQ) SetupQueue(queueName, exchangeName, exchangeType, routingKey string, durable bool, args amqp.Table) (string, error) {
 	// Declare exchange
 	if err := r.Channel.ExchangeDeclare(exchangeName, exchangeType, durable, false, false, false, nil); err != nil {
@@ -92,18 +117,43 @@ func (r *RabbitMQ) DeclareQueue(name string, durable bool, args amqp.Table) (str
 	return q.Name, nil
 }
 
+// DeclareExclusiveQueue declares a server-named queue that only lives as long
+// as this connection, for events every instance needs its own copy of
+func (r *RabbitMQ) DeclareExclusiveQueue() (string, error) {
+	q, err := r.Channel.QueueDeclare(
+		"",
+		false, // durable
+		true,  // delete when unused
+		true,  // exclusive
+		false, // noWait
+		nil,
+	)
+	if err != nil {
+		return "", err
+	}
+	return q.Name, nil
+}
+
 // BindQueue binds a queue to an exchange with a routing key
 func (r *RabbitMQ) BindQueue(queue, exchange, routingKey string) error {
 	return r.Channel.QueueBind(queue, routingKey, exchange, false, nil)
 }
 
-// PublishMessage publishes a JSON message to an exchange with a routing key
-func (r *RabbitMQ) PublishMessage(ctx context.Context, exchange, routingKey string, message interface{}) error {
+// PublishMessage publishes a JSON message to an exchange with a routing key.
+// The request ID and trace context in ctx travel along in the headers.
+func (r *RabbitMQ) PublishMessage(ctx context.Context, exchange, routingKey string, message interface{}) (err error) {
 	body, err := json.Marshal(message)
 	if err != nil {
 		return fmt.Errorf("failed to marshal message: %v", err)
 	}
 
+	headers := amqp.Table{}
+	if id := correlation.FromContext(ctx); id != "" {
+		headers[correlation.MetadataKey] = id
+	}
+	ctx, span := startPublishSpan(ctx, exchange, routingKey, headers)
+	defer func() { endSpan(span, err) }()
+
 	return r.Channel.PublishWithContext(ctx,
 		exchange,
 		routingKey,
@@ -111,28 +161,118 @@ func (r *RabbitMQ) PublishMessage(ctx context.Context, exchange, routingKey stri
 		amqp.Publishing{
 			ContentType:  "application/json",
 			DeliveryMode: amqp.Persistent,
+			Headers:      headers,
 			Body:         body,
 		},
 	)
 }
 
-// ConsumeMessages starts consuming messages from a queue
-func (r *RabbitMQ) ConsumeMessages(queue string, handler func(msg []byte) error) error {
-	msgs, err := r.Channel.Consume(queue, "", false, false, false, false, nil)
+// deliveryContext restores the request ID a message was published with
+func deliveryContext(msg amqp.Delivery) context.Context {
+	ctx := context.Background()
+	if id, ok := msg.Headers[correlation.MetadataKey].(string); ok && id != "" {
+		ctx = correlation.WithID(ctx, id)
+	}
+	return ctx
+}
+
+// recordResult counts a handled delivery as acked or failed
+func recordResult(queue string, err error) {
+	result := "acked"
+	if err != nil {
+		result = "failed"
+	}
+	metrics.QueueMessages.WithLabelValues(queue, result).Inc()
+}
+
+// ConsumeMessages starts consuming messages from a queue. The handler's
+// context carries the request ID and trace the message was published with.
+func (r *RabbitMQ) ConsumeMessages(queue string, handler func(ctx context.Context, msg []byte) error) error {
+	// Failed messages are requeued
+	return r.consume(queue, true, func(msg amqp.Delivery) error {
+		metrics.QueueMessages.WithLabelValues(queue, "consumed").Inc()
+		ctx, span := startConsumeSpan(queue, msg)
+		err := handler(ctx, msg.Body)
+		endSpan(span, err)
+		recordResult(queue, err)
+		if err != nil {
+			correlation.Printf(ctx, "failed to handle message: %v", err)
+		}
+		return err
+	})
+}
+
+// consume registers a consumer on queue and hands each delivery to handle,
+// one at a time, until the consumer is cancelled by StopConsumers. A delivery
+// is acked once handle returns nil; otherwise it is rejected and, if
+// requeueFailed is set, put back on the queue.
+func (r *RabbitMQ) consume(queue string, requeueFailed bool, handle func(msg amqp.Delivery) error) error {
+	if err := r.Channel.Qos(consumerPrefetch, 0, false); err != nil {
+		return fmt.Errorf("failed to set prefetch: %w", err)
+	}
+	tag := queue + "-" + uuid.NewString()
+	msgs, err := r.Channel.Consume(queue, tag, false, false, false, false, nil)
 	if err != nil {
 		return err
 	}
 
+	r.mutex.Lock()
+	r.consumers = append(r.consumers, tag)
+	r.inFlight.Add(1)
+	r.mutex.Unlock()
+
 	go func() {
+		defer r.inFlight.Done()
+		// The channel is closed once the consumer is cancelled and every
+		// delivery already received has been handled
 		for msg := range msgs {
-			if err := handler(msg.Body); err != nil {
-				log.Printf("failed to handle message: %v", err)
-				msg.Nack(false, true) // requeue if failed
+			if r.isStopping() {
+				// Leave prefetched deliveries to another consumer
+				msg.Nack(false, true)
+				continue
+			}
+			if err := handle(msg); err != nil {
+				msg.Nack(false, requeueFailed)
 				continue
 			}
 			msg.Ack(false)
 		}
 	}()
-
 	return nil
 }
+
+func (r *RabbitMQ) isStopping() bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.stopping
+}
+
+// StopConsumers cancels every consumer started on this connection and waits
+// for the message being handled to finish. Deliveries that were prefetched
+// but not yet handled are requeued.
+func (r *RabbitMQ) StopConsumers(ctx context.Context) error {
+	r.mutex.Lock()
+	tags := r.consumers
+	r.consumers = nil
+	r.stopping = true
+	r.mutex.Unlock()
+
+	var errs []error
+	for _, tag := range tags {
+		if err := r.Channel.Cancel(tag, false); err != nil {
+			errs = append(errs, fmt.Errorf("failed to cancel consumer %s: %w", tag, err))
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		r.inFlight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		errs = append(errs, fmt.Errorf("consumers still busy: %w", ctx.Err()))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,76 @@
+package messaging
+
+import (
+	"context"
+	"fmt"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/wutthichod/sa-connext/shared/messaging"
+
+// headerCarrier lets the otel propagator read and write AMQP headers
+type headerCarrier amqp.Table
+
+func (h headerCarrier) Get(key string) string {
+	value, _ := h[key].(string)
+	return value
+}
+
+func (h headerCarrier) Set(key, value string) {
+	h[key] = value
+}
+
+func (h headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(h))
+	for key := range h {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// startPublishSpan starts a producer span and writes its trace context into headers
+func startPublishSpan(ctx context.Context, exchange, routingKey string, headers amqp.Table) (context.Context, trace.Span) {
+	ctx, span := otel.Tracer(instrumentationName).Start(ctx, fmt.Sprintf("%s publish", exchange),
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemRabbitmq,
+			semconv.MessagingOperationTypePublish,
+			semconv.MessagingDestinationName(exchange),
+			semconv.MessagingRabbitmqDestinationRoutingKey(routingKey),
+		),
+	)
+	otel.GetTextMapPropagator().Inject(ctx, headerCarrier(headers))
+	return ctx, span
+}
+
+// startConsumeSpan starts a consumer span for msg, continuing the trace it
+// was published with. The returned context also carries its request ID.
+func startConsumeSpan(queue string, msg amqp.Delivery) (context.Context, trace.Span) {
+	ctx := deliveryContext(msg)
+	if msg.Headers != nil {
+		ctx = otel.GetTextMapPropagator().Extract(ctx, headerCarrier(msg.Headers))
+	}
+	return otel.Tracer(instrumentationName).Start(ctx, fmt.Sprintf("%s process", queue),
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingSystemRabbitmq,
+			semconv.MessagingOperationTypeDeliver,
+			semconv.MessagingDestinationName(queue),
+			semconv.MessagingRabbitmqDestinationRoutingKey(msg.RoutingKey),
+		),
+	)
+}
+
+// endSpan records err on span, if any, and ends it
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
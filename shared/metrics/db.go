@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"database/sql"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+var mongoConnections = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "mongo_pool_connections",
+	Help: "MongoDB pool connections, by state: open or in_use.",
+}, []string{"state"})
+
+// RegisterDB exports the connection pool stats of db under dbName
+func RegisterDB(dbName string, db *sql.DB) error {
+	return prometheus.Register(collectors.NewDBStatsCollector(db, dbName))
+}
+
+// MongoPoolMonitor tracks the MongoDB connection pool. Set it with
+// options.Client().SetPoolMonitor.
+func MongoPoolMonitor() *event.PoolMonitor {
+	return &event.PoolMonitor{
+		Event: func(evt *event.PoolEvent) {
+			switch evt.Type {
+			case event.ConnectionCreated:
+				mongoConnections.WithLabelValues("open").Inc()
+			case event.ConnectionClosed:
+				mongoConnections.WithLabelValues("open").Dec()
+			case event.GetSucceeded:
+				mongoConnections.WithLabelValues("in_use").Inc()
+			case event.ConnectionReturned:
+				mongoConnections.WithLabelValues("in_use").Dec()
+			}
+		},
+	}
+}
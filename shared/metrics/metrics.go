@@ -0,0 +1,72 @@
+// Package metrics holds the Prometheus collectors shared by the services and
+// the middleware that feeds them. Every service exposes them on /metrics:
+// HTTP services on their Fiber app, gRPC services through NewServer.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	// WebSocketConnections is the number of users with an open websocket
+	WebSocketConnections = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "websocket_connections",
+		Help: "Number of open WebSocket connections.",
+	})
+
+	// QueueMessages counts deliveries by queue and result: "consumed" when a
+	// message arrives, then "acked" or "failed" once it has been handled
+	QueueMessages = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "queue_messages_total",
+		Help: "RabbitMQ deliveries by queue and result.",
+	}, []string{"queue", "result"})
+
+	// MessagesSent counts chat messages by type ("text" or "poll")
+	MessagesSent = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "chat_messages_sent_total",
+		Help: "Chat messages sent, by message type.",
+	}, []string{"type"})
+
+	// EventsCreated counts events created by organizers
+	EventsCreated = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "events_created_total",
+		Help: "Events created.",
+	})
+
+	// EventJoins counts users joining an event with its joining code
+	EventJoins = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "event_joins_total",
+		Help: "Users that joined an event.",
+	})
+
+	// DownstreamRetries counts retried calls to a downstream service
+	DownstreamRetries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "downstream_retries_total",
+		Help: "Retried calls to downstream services, by service.",
+	}, []string{"service"})
+
+	// BreakerState is 0 while a downstream's circuit breaker is closed, 1
+	// while it lets a trial call through and 2 while it is open
+	BreakerState = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "circuit_breaker_state",
+		Help: "Circuit breaker state by downstream service: 0 closed, 1 half-open, 2 open.",
+	}, []string{"service"})
+)
+
+// Handler serves the metrics in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// NewServer returns a server exposing /metrics on addr, for services that do
+// not otherwise serve HTTP. Callers run it alongside their gRPC server, so a
+// failed bind stops the service, and shut it down with the rest.
+func NewServer(addr string) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	return &http.Server{Addr: addr, Handler: mux}
+}
@@ -0,0 +1,70 @@
+package tracing
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const gormSpanKey = "tracing:span"
+
+// InstrumentGORM records a span for every query db runs. Queries must be run
+// with WithContext(ctx) to be attached to the caller's trace.
+func InstrumentGORM(db *gorm.DB) error {
+	callbacks := db.Callback()
+	registrations := []error{
+		callbacks.Create().Before("gorm:create").Register("tracing:before_create", startGORMSpan("create")),
+		callbacks.Create().After("gorm:create").Register("tracing:after_create", endGORMSpan),
+		callbacks.Query().Before("gorm:query").Register("tracing:before_query", startGORMSpan("query")),
+		callbacks.Query().After("gorm:query").Register("tracing:after_query", endGORMSpan),
+		callbacks.Update().Before("gorm:update").Register("tracing:before_update", startGORMSpan("update")),
+		callbacks.Update().After("gorm:update").Register("tracing:after_update", endGORMSpan),
+		callbacks.Delete().Before("gorm:delete").Register("tracing:before_delete", startGORMSpan("delete")),
+		callbacks.Delete().After("gorm:delete").Register("tracing:after_delete", endGORMSpan),
+		callbacks.Row().Before("gorm:row").Register("tracing:before_row", startGORMSpan("row")),
+		callbacks.Row().After("gorm:row").Register("tracing:after_row", endGORMSpan),
+		callbacks.Raw().Before("gorm:raw").Register("tracing:before_raw", startGORMSpan("raw")),
+		callbacks.Raw().After("gorm:raw").Register("tracing:after_raw", endGORMSpan),
+	}
+	if err := errors.Join(registrations...); err != nil {
+		return err
+	}
+	return nil
+}
+
+func startGORMSpan(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		ctx, span := tracer().Start(db.Statement.Context, "gorm."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				semconv.DBSystemPostgreSQL,
+				semconv.DBOperationName(operation),
+			),
+		)
+		db.Statement.Context = ctx
+		db.InstanceSet(gormSpanKey, span)
+	}
+}
+
+func endGORMSpan(db *gorm.DB) {
+	value, ok := db.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	span := value.(trace.Span)
+	defer span.End()
+
+	span.SetAttributes(
+		semconv.DBCollectionName(db.Statement.Table),
+		semconv.DBQueryText(db.Statement.SQL.String()),
+		attribute.Int64("db.rows_affected", db.Statement.RowsAffected),
+	)
+	if db.Error != nil && db.Error != gorm.ErrRecordNotFound {
+		span.RecordError(db.Error)
+		span.SetStatus(codes.Error, db.Error.Error())
+	}
+}
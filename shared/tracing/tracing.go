@@ -0,0 +1,97 @@
+// Package tracing sets up OpenTelemetry for a service and instruments the
+// libraries the services share: Fiber, gRPC, net/http, GORM and MongoDB.
+// RabbitMQ propagation lives in the messaging package.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/wutthichod/sa-connext/shared/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/wutthichod/sa-connext/shared/tracing"
+
+// Provider owns the service's tracer provider
+type Provider struct {
+	provider *sdktrace.TracerProvider
+	memory   *tracetest.InMemoryExporter
+}
+
+// Init installs a global tracer provider exporting to cfg.Exporter, and the
+// W3C trace context propagator. With the "none" exporter spans are still
+// created, so trace IDs propagate, but nothing is exported.
+func Init(ctx context.Context, serviceName string, cfg config.Tracing) (*Provider, error) {
+	var exporter sdktrace.SpanExporter
+	var memory *tracetest.InMemoryExporter
+	switch cfg.Exporter {
+	case "", "none":
+	case "stdout":
+		stdout, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create stdout exporter: %w", err)
+		}
+		exporter = stdout
+	case "memory":
+		memory = tracetest.NewInMemoryExporter()
+		exporter = memory
+	case "otlp":
+		otlp, err := otlptracegrpc.New(ctx,
+			otlptracegrpc.WithEndpoint(cfg.OTLPEndpoint),
+			otlptracegrpc.WithInsecure(),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create otlp exporter: %w", err)
+		}
+		exporter = otlp
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", cfg.Exporter)
+	}
+
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(serviceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	}
+	switch {
+	case memory != nil:
+		// Synchronous, so spans can be inspected as soon as they end
+		opts = append(opts, sdktrace.WithSyncer(memory))
+	case exporter != nil:
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+
+	provider := sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+	return &Provider{provider: provider, memory: memory}, nil
+}
+
+// Shutdown flushes pending spans
+func (p *Provider) Shutdown(ctx context.Context) error {
+	return p.provider.Shutdown(ctx)
+}
+
+// Spans returns the spans recorded so far by the "memory" exporter
+func (p *Provider) Spans() tracetest.SpanStubs {
+	if p.memory == nil {
+		return nil
+	}
+	return p.memory.GetSpans()
+}
+
+func tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}